@@ -4,24 +4,362 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
 )
 
+// The IDs of the billing services whose SKUs are used for cost estimation.
+const (
+	ComputeEngineService    = "services/6F81-5844-456A"
+	CloudSQLService         = "services/9662-B51E-5089"
+	CloudStorageService     = "services/95FF-2EF5-5EA1"
+	KubernetesEngineService = "services/CCD8-9BF1-090E"
+	NetworkingService       = "services/E505-1604-58F8"
+)
+
+// The names of the service catalogs, used by resource types to declare the catalogs they need.
+const (
+	ComputeEngine    = "compute-engine"
+	CloudSQL         = "cloud-sql"
+	CloudStorage     = "cloud-storage"
+	KubernetesEngine = "kubernetes-engine"
+	Network          = "network"
+)
+
+// Catalog serves the SKUs of any billing service by service ID. The SKUs of a service are loaded
+// from the billing API on first use, so only the services of the priced resources are fetched.
+// The service catalogs (ComputeEngine, CloudSQL etc.) index the SKUs of the supported services.
+type Catalog struct {
+	ctx        context.Context
+	fetch      func(ctx context.Context, service string) ([]*billingpb.Sku, error)
+	currency   string
+	explain    bool
+	monthHours float64
+	snapshot   time.Time
+
+	mu       sync.Mutex
+	services map[string][]*billingpb.Sku
+
+	catalogMu        sync.Mutex
+	computeEngine    *ComputeEngineCatalog
+	cloudSQL         *CloudSQLCatalog
+	cloudStorage     *CloudStorageCatalog
+	kubernetesEngine *KubernetesEngineCatalog
+	network          *NetworkCatalog
+}
+
+// NewCatalog creates a catalog loading the SKUs from the billing API with the given context,
+// with prices in the given ISO 4217 currency (USD if empty).
+// The service catalogs with the specified names are loaded right away, the others on first use.
+func NewCatalog(ctx context.Context, currency string, preload ...string) (*Catalog, error) {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	fetch := func(ctx context.Context, service string) ([]*billingpb.Sku, error) {
+		skus, err := GetSKUsInCurrency(ctx, service, currency)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkCurrency(skus, currency); err != nil {
+			return nil, err
+		}
+		return skus, nil
+	}
+
+	c := &Catalog{ctx: ctx, fetch: fetch, currency: currency, services: map[string][]*billingpb.Sku{}}
+	if err := c.Preload(preload...); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewCatalogFromSKUs creates a catalog serving the given SKUs of each service ID without calling the billing API.
+// Services missing from the map have no SKUs. The prices are taken as they are, in the currency of each tier rate.
+func NewCatalogFromSKUs(skus map[string][]*billingpb.Sku) *Catalog {
+	c := &Catalog{ctx: context.Background(), currency: DefaultCurrency, services: map[string][]*billingpb.Sku{}}
+	for s, l := range skus {
+		c.services[s] = l
+	}
+	c.fetch = func(context.Context, string) ([]*billingpb.Sku, error) { return nil, nil }
+	return c
+}
+
+// NewCatalogFromSnapshot creates a catalog serving the SKUs saved in the store by the snapshot of the given date,
+// with prices in the given currency (USD if empty). Loading a service missing from the snapshot is an error.
+func NewCatalogFromSnapshot(store *SnapshotStore, date time.Time, currency string) *Catalog {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	fetch := func(ctx context.Context, service string) ([]*billingpb.Sku, error) {
+		return store.Load(date, currency, service)
+	}
+	return &Catalog{ctx: context.Background(), fetch: fetch, currency: currency, snapshot: date, services: map[string][]*billingpb.Sku{}}
+}
+
+// Record saves the SKUs of every service loaded from now on to the snapshot of the given date in the store.
+// The first service loaded also loads and saves all the supported services (Services), so that the snapshot
+// can price any plan and not only the resource types of the plan being estimated.
+// A service that cannot be saved fails to load.
+func (c *Catalog) Record(store *SnapshotStore, date time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fetch := c.fetch
+	save := func(ctx context.Context, service string) ([]*billingpb.Sku, error) {
+		skus, err := fetch(ctx, service)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.Save(date, c.currency, service, skus); err != nil {
+			return nil, err
+		}
+		return skus, nil
+	}
+	c.fetch = func(ctx context.Context, service string) ([]*billingpb.Sku, error) {
+		// The services are fetched with c.mu held, so the other ones can be cached right away.
+		for _, s := range Services {
+			if _, ok := c.services[s]; ok || s == service {
+				continue
+			}
+			skus, err := save(ctx, s)
+			if err != nil {
+				return nil, err
+			}
+			c.services[s] = skus
+		}
+		return save(ctx, service)
+	}
+}
+
+// Snapshot returns the date of the snapshot whose SKUs the catalog serves, the zero time if they are not
+// from a snapshot.
+func (c *Catalog) Snapshot() time.Time {
+	return c.snapshot
+}
+
+// Preload loads the service catalogs with the specified names.
+func (c *Catalog) Preload(names ...string) error {
+	for _, name := range names {
+		var err error
+		switch name {
+		case ComputeEngine:
+			_, err = c.ComputeEngine()
+		case CloudSQL:
+			_, err = c.CloudSQL()
+		case CloudStorage:
+			_, err = c.CloudStorage()
+		case KubernetesEngine:
+			_, err = c.KubernetesEngine()
+		case Network:
+			_, err = c.Network()
+		default:
+			return fmt.Errorf("unknown service catalog '" + name + "'")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Currency returns the ISO 4217 code of the currency the catalog requests prices in.
+func (c *Catalog) Currency() string {
+	return c.currency
+}
+
+// checkCurrency returns an error if a tier rate of the SKUs is priced in another currency.
+func checkCurrency(skus []*billingpb.Sku, currency string) error {
+	for _, sku := range skus {
+		for _, p := range sku.PricingInfo {
+			if p.PricingExpression == nil {
+				continue
+			}
+			for _, tr := range p.PricingExpression.TieredRates {
+				if tr.UnitPrice != nil && tr.UnitPrice.CurrencyCode != currency {
+					return fmt.Errorf("SKU " + sku.SkuId + " is priced in " + tr.UnitPrice.CurrencyCode + " instead of " + currency)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// SetExplain sets whether the resources priced with the catalog record how their SKUs were matched
+// (filters applied and candidates rejected), which costs an extra pass over the candidate SKUs.
+func (c *Catalog) SetExplain(explain bool) {
+	c.explain = explain
+}
+
+// Explain reports whether the resources priced with the catalog record how their SKUs were matched.
+func (c *Catalog) Explain() bool {
+	return c != nil && c.explain
+}
+
+// DefaultMonthHours is the number of hours of a month of 30 days, over which monthly prices are spread
+// unless another month is set with SetMonthHours.
+const DefaultMonthHours = float64(24 * 30)
+
+// SetMonthHours sets the number of hours of the month over which the resources priced with the catalog spread
+// the prices of the SKUs charged per month (e.g. storage per GiB month), so that a month of that many hours
+// costs exactly the catalog price.
+func (c *Catalog) SetMonthHours(hours float64) {
+	c.monthHours = hours
+}
+
+// MonthHours returns the number of hours of the month over which monthly prices are spread.
+func (c *Catalog) MonthHours() float64 {
+	if c == nil || c.monthHours <= 0 {
+		return DefaultMonthHours
+	}
+	return c.monthHours
+}
+
+// ServiceSKUs returns all the SKUs of the service with the given ID, loading them on first use.
+func (c *Catalog) ServiceSKUs(service string) ([]*billingpb.Sku, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if skus, ok := c.services[service]; ok {
+		return skus, nil
+	}
+
+	skus, err := c.fetch(c.ctx, service)
+	if err != nil {
+		return nil, err
+	}
+	c.services[service] = skus
+	return skus, nil
+}
+
+// Query returns the SKUs of the service with the given ID that match the query.
+func (c *Catalog) Query(service string, q Query) ([]*billingpb.Sku, error) {
+	skus, err := c.ServiceSKUs(service)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []*billingpb.Sku
+	for _, sku := range skus {
+		if q.Matches(sku) {
+			matching = append(matching, sku)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, NewSKUNotFoundError("found no SKU of service " + service + " matching the query")
+	}
+	return matching, nil
+}
+
+// ComputeEngine returns the Compute Engine catalog, loading it on first use.
+func (c *Catalog) ComputeEngine() (*ComputeEngineCatalog, error) {
+	skus, err := c.ServiceSKUs(ComputeEngineService)
+	if err != nil {
+		return nil, err
+	}
+
+	c.catalogMu.Lock()
+	defer c.catalogMu.Unlock()
+	if c.computeEngine == nil {
+		c.computeEngine = emptyComputeEngineCatalog()
+		c.computeEngine.assignSKUCategories(skus)
+	}
+	return c.computeEngine, nil
+}
+
+// CloudSQL returns the Cloud SQL catalog, loading it on first use.
+func (c *Catalog) CloudSQL() (*CloudSQLCatalog, error) {
+	skus, err := c.ServiceSKUs(CloudSQLService)
+	if err != nil {
+		return nil, err
+	}
+
+	c.catalogMu.Lock()
+	defer c.catalogMu.Unlock()
+	if c.cloudSQL == nil {
+		c.cloudSQL = emptyCloudSQLCatalog()
+		c.cloudSQL.assignSKUCategories(skus)
+	}
+	return c.cloudSQL, nil
+}
+
+// CloudStorage returns the Cloud Storage catalog, loading it on first use.
+func (c *Catalog) CloudStorage() (*CloudStorageCatalog, error) {
+	skus, err := c.ServiceSKUs(CloudStorageService)
+	if err != nil {
+		return nil, err
+	}
+
+	c.catalogMu.Lock()
+	defer c.catalogMu.Unlock()
+	if c.cloudStorage == nil {
+		c.cloudStorage = emptyCloudStorageCatalog()
+		c.cloudStorage.assignSKUCategories(skus)
+	}
+	return c.cloudStorage, nil
+}
+
+// KubernetesEngine returns the Kubernetes Engine catalog holding the cluster fees, loading it on first use.
+// Nodes are priced as Compute Engine instances.
+func (c *Catalog) KubernetesEngine() (*KubernetesEngineCatalog, error) {
+	skus, err := c.ServiceSKUs(KubernetesEngineService)
+	if err != nil {
+		return nil, err
+	}
+
+	c.catalogMu.Lock()
+	defer c.catalogMu.Unlock()
+	if c.kubernetesEngine == nil {
+		c.kubernetesEngine = emptyKubernetesEngineCatalog()
+		c.kubernetesEngine.assignSKUCategories(skus)
+	}
+	return c.kubernetesEngine, nil
+}
+
+// Network returns the catalog of the Network resource family SKUs of Compute Engine and Networking
+// (Cloud NAT, IPs, egress, load balancing), loading it on first use.
+func (c *Catalog) Network() (*NetworkCatalog, error) {
+	n := emptyNetworkCatalog()
+	var all [][]*billingpb.Sku
+	for _, s := range n.services {
+		skus, err := c.ServiceSKUs(s)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, skus)
+	}
+
+	c.catalogMu.Lock()
+	defer c.catalogMu.Unlock()
+	if c.network == nil {
+		for _, skus := range all {
+			n.assignSKUCategories(skus)
+		}
+		c.network = n
+	}
+	return c.network, nil
+}
+
 // ComputeEngineCatalog holds the information from the billing catalog for Compute Engine SKUs.
 type ComputeEngineCatalog struct {
 	service       string
 	coreInstances map[string][]*billingpb.Sku
 	ramInstances  map[string][]*billingpb.Sku
 	disks         map[string][]*billingpb.Sku
+
+	coreIndex skuIndex
+	ramIndex  skuIndex
+	diskIndex skuIndex
 }
 
 // NewComputeEngineCatalog creates a catalog instance, calls the billing API and stores its response.
 // Core and RAM instances are stored by usage type.
 // Disks are stored by resource group.
+// All of them are also indexed by resource group, usage type, region and machine family for lookups.
 func NewComputeEngineCatalog(ctx context.Context) (*ComputeEngineCatalog, error) {
 	c := new(ComputeEngineCatalog)
-	c.service = "services/6F81-5844-456A"
+	c.service = ComputeEngineService
 	c.coreInstances = map[string][]*billingpb.Sku{}
 	c.ramInstances = map[string][]*billingpb.Sku{}
 	c.disks = map[string][]*billingpb.Sku{}
@@ -37,7 +375,7 @@ func NewComputeEngineCatalog(ctx context.Context) (*ComputeEngineCatalog, error)
 
 func emptyComputeEngineCatalog() *ComputeEngineCatalog {
 	c := new(ComputeEngineCatalog)
-	c.service = "services/6F81-5844-456A"
+	c.service = ComputeEngineService
 	c.coreInstances = map[string][]*billingpb.Sku{}
 	c.ramInstances = map[string][]*billingpb.Sku{}
 	c.disks = map[string][]*billingpb.Sku{}
@@ -73,13 +411,34 @@ func (catalog *ComputeEngineCatalog) assignSKUCategories(skus []*billingpb.Sku)
 
 		}
 	}
+	catalog.indexSKUs()
+}
+
+// indexSKUs builds the core, RAM and disk indexes from the stored SKUs.
+func (catalog *ComputeEngineCatalog) indexSKUs() {
+	catalog.coreIndex, catalog.ramIndex, catalog.diskIndex = skuIndex{}, skuIndex{}, skuIndex{}
+	for _, skus := range catalog.coreInstances {
+		for _, sku := range skus {
+			catalog.coreIndex.add(sku, skuMachineFamily(sku.Description))
+		}
+	}
+	for _, skus := range catalog.ramInstances {
+		for _, sku := range skus {
+			catalog.ramIndex.add(sku, skuMachineFamily(sku.Description))
+		}
+	}
+	for _, skus := range catalog.disks {
+		for _, sku := range skus {
+			catalog.diskIndex.add(sku, "")
+		}
+	}
 }
 
 // GetCoreSKUs returns the Core Instance SKUs from the billing API.
 func (catalog *ComputeEngineCatalog) GetCoreSKUs(usageType string) ([]*billingpb.Sku, error) {
 	skus, ok := catalog.coreInstances[usageType]
 	if !ok {
-		return nil, fmt.Errorf("found no core SKU of this usage type")
+		return nil, NewSKUNotFoundError("found no core SKU of this usage type")
 	}
 	return skus, nil
 }
@@ -88,30 +447,68 @@ func (catalog *ComputeEngineCatalog) GetCoreSKUs(usageType string) ([]*billingpb
 func (catalog *ComputeEngineCatalog) GetRAMSKUs(usageType string) ([]*billingpb.Sku, error) {
 	skus, ok := catalog.ramInstances[usageType]
 	if !ok {
-		return nil, fmt.Errorf("found no RAM SKU of this usage type")
+		return nil, NewSKUNotFoundError("found no RAM SKU of this usage type")
 	}
 	return skus, nil
 }
 
-// DiskSKUs returns the SKUs matching the resource group of the specified disk type.
-func (catalog *ComputeEngineCatalog) DiskSKUs(diskType string) ([]*billingpb.Sku, error) {
-	var rg string
+// LookupCoreSKUs returns the core SKUs with the resource group, usage type, region and machine family of the key.
+func (catalog *ComputeEngineCatalog) LookupCoreSKUs(k SKUKey) ([]*billingpb.Sku, error) {
+	skus := catalog.coreIndex.lookup(k)
+	if len(skus) == 0 {
+		return nil, NewSKUNotFoundError("found no " + k.Family + " core SKU of usage type " + k.UsageType + " in region '" + k.Region + "'")
+	}
+	return skus, nil
+}
+
+// LookupRAMSKUs returns the RAM SKUs with the resource group, usage type, region and machine family of the key.
+func (catalog *ComputeEngineCatalog) LookupRAMSKUs(k SKUKey) ([]*billingpb.Sku, error) {
+	skus := catalog.ramIndex.lookup(k)
+	if len(skus) == 0 {
+		return nil, NewSKUNotFoundError("found no " + k.Family + " RAM SKU of usage type " + k.UsageType + " in region '" + k.Region + "'")
+	}
+	return skus, nil
+}
+
+// LookupDiskSKUs returns the on demand SKUs of the specified disk type in the region.
+func (catalog *ComputeEngineCatalog) LookupDiskSKUs(diskType, region string) ([]*billingpb.Sku, error) {
+	rg, err := diskResourceGroup(diskType)
+	if err != nil {
+		return nil, err
+	}
+
+	skus := catalog.diskIndex.lookup(SKUKey{ResourceGroup: rg, UsageType: "OnDemand", Region: region})
+	if len(skus) == 0 {
+		return nil, NewSKUNotFoundError("found no disk SKU of this resource group in region '" + region + "'")
+	}
+	return skus, nil
+}
+
+func diskResourceGroup(diskType string) (string, error) {
 	switch diskType {
 	case "pd-standard":
-		rg = "PDStandard"
+		return "PDStandard", nil
 	case "pd-balanced":
-		rg = "SSD"
+		return "SSD", nil
 	case "pd-ssd":
-		rg = "SSD"
+		return "SSD", nil
 	case "local-ssd":
-		rg = "LocalSSD"
+		return "LocalSSD", nil
 	default:
-		return nil, fmt.Errorf("invalid disk type '" + diskType + "'")
+		return "", fmt.Errorf("invalid disk type '" + diskType + "'")
+	}
+}
+
+// DiskSKUs returns the SKUs matching the resource group of the specified disk type.
+func (catalog *ComputeEngineCatalog) DiskSKUs(diskType string) ([]*billingpb.Sku, error) {
+	rg, err := diskResourceGroup(diskType)
+	if err != nil {
+		return nil, err
 	}
 
 	skus, ok := catalog.disks[rg]
 	if !ok {
-		return nil, fmt.Errorf("found no disk SKU of this resource group")
+		return nil, NewSKUNotFoundError("found no disk SKU of this resource group")
 	}
 	return skus, nil
 }
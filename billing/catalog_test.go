@@ -1,6 +1,7 @@
 package billing
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
@@ -84,6 +85,10 @@ func TestAssignSKUCategories(t *testing.T) {
 	c4.ramInstances["OnDemand"] = []*billingpb.Sku{skus[0], skus[5], skus[9]}
 	c4.ramInstances["Preemptible"] = []*billingpb.Sku{skus[1]}
 
+	for _, c := range []*ComputeEngineCatalog{c1, c2, c3, c4} {
+		c.indexSKUs()
+	}
+
 	tests := []struct {
 		name    string
 		skus    []*billingpb.Sku
@@ -108,3 +113,11 @@ func TestAssignSKUCategories(t *testing.T) {
 		})
 	}
 }
+
+func TestNewCatalogUnknownService(t *testing.T) {
+	// Unknown services are rejected before calling the billing API.
+	c, err := NewCatalog(context.Background(), "", "compute")
+	if expected := fmt.Errorf("unknown service catalog 'compute'"); c != nil || !reflect.DeepEqual(err, expected) {
+		t.Errorf("NewCatalog(compute) = %+v, %+v; want nil, %+v", c, err, expected)
+	}
+}
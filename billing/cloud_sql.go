@@ -0,0 +1,113 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+// CloudSQLCatalog holds the information from the billing catalog for Cloud SQL SKUs.
+type CloudSQLCatalog struct {
+	service   string
+	instances map[string][]*billingpb.Sku
+	storage   map[string][]*billingpb.Sku
+}
+
+// NewCloudSQLCatalog creates a catalog instance, calls the billing API and stores its response.
+// Instance (CPU, RAM and shared core) SKUs are stored by resource group.
+// Storage SKUs are stored by resource group.
+func NewCloudSQLCatalog(ctx context.Context) (*CloudSQLCatalog, error) {
+	c := emptyCloudSQLCatalog()
+
+	skus, err := GetSKUs(ctx, c.service)
+	if err != nil {
+		return nil, err
+	}
+	c.assignSKUCategories(skus)
+
+	return c, nil
+}
+
+func emptyCloudSQLCatalog() *CloudSQLCatalog {
+	c := new(CloudSQLCatalog)
+	c.service = CloudSQLService
+	c.instances = map[string][]*billingpb.Sku{}
+	c.storage = map[string][]*billingpb.Sku{}
+	return c
+}
+
+func (catalog *CloudSQLCatalog) assignSKUCategories(skus []*billingpb.Sku) {
+	for _, sku := range skus {
+		c := sku.Category
+		// Only OnDemand SKUs are used for estimations.
+		if c.UsageType != "OnDemand" {
+			continue
+		}
+
+		switch {
+		case c.ResourceFamily == "ApplicationServices":
+			catalog.instances[c.ResourceGroup] = append(catalog.instances[c.ResourceGroup], sku)
+		case c.ResourceFamily == "Storage":
+			catalog.storage[c.ResourceGroup] = append(catalog.storage[c.ResourceGroup], sku)
+		default:
+
+		}
+	}
+}
+
+// CoreSKUs returns the SKUs for the vCPUs of dedicated core database instances.
+func (catalog *CloudSQLCatalog) CoreSKUs() ([]*billingpb.Sku, error) {
+	skus, ok := catalog.instances["SQLGen2InstancesCPU"]
+	if !ok {
+		return nil, NewSKUNotFoundError("found no Cloud SQL core SKU")
+	}
+	return skus, nil
+}
+
+// RAMSKUs returns the SKUs for the memory of dedicated core database instances.
+func (catalog *CloudSQLCatalog) RAMSKUs() ([]*billingpb.Sku, error) {
+	skus, ok := catalog.instances["SQLGen2InstancesRAM"]
+	if !ok {
+		return nil, NewSKUNotFoundError("found no Cloud SQL RAM SKU")
+	}
+	return skus, nil
+}
+
+// SharedCoreSKUs returns the SKUs for the shared core database instance tier (db-f1-micro, db-g1-small).
+func (catalog *CloudSQLCatalog) SharedCoreSKUs(tier string) ([]*billingpb.Sku, error) {
+	var rg string
+	switch tier {
+	case "db-f1-micro":
+		rg = "SQLGen2InstancesF1Micro"
+	case "db-g1-small":
+		rg = "SQLGen2InstancesG1Small"
+	default:
+		return nil, fmt.Errorf("invalid shared core tier '" + tier + "'")
+	}
+
+	skus, ok := catalog.instances[rg]
+	if !ok {
+		return nil, NewSKUNotFoundError("found no Cloud SQL SKU for tier '" + tier + "'")
+	}
+	return skus, nil
+}
+
+// StorageSKUs returns the SKUs matching the resource group of the specified database disk type.
+func (catalog *CloudSQLCatalog) StorageSKUs(diskType string) ([]*billingpb.Sku, error) {
+	var rg string
+	switch diskType {
+	case "PD_SSD":
+		rg = "SSD"
+	case "PD_HDD":
+		rg = "PDStandard"
+	default:
+		return nil, fmt.Errorf("invalid database disk type '" + diskType + "'")
+	}
+
+	skus, ok := catalog.storage[rg]
+	if !ok {
+		return nil, NewSKUNotFoundError("found no Cloud SQL storage SKU of this resource group")
+	}
+	return skus, nil
+}
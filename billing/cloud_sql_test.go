@@ -0,0 +1,45 @@
+package billing
+
+import (
+	"reflect"
+	"testing"
+
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+func testSKU(description, family, group, usageType string) *billingpb.Sku {
+	return &billingpb.Sku{
+		Description: description,
+		Category:    &billingpb.Category{ResourceFamily: family, ResourceGroup: group, UsageType: usageType},
+	}
+}
+
+func TestCloudSQLAssignSKUCategories(t *testing.T) {
+	cpu := testSKU("Cloud SQL for MySQL: Zonal - vCPU in Americas", "ApplicationServices", "SQLGen2InstancesCPU", "OnDemand")
+	ram := testSKU("Cloud SQL for MySQL: Zonal - RAM in Americas", "ApplicationServices", "SQLGen2InstancesRAM", "OnDemand")
+	micro := testSKU("Cloud SQL for MySQL: Zonal - Micro instance in Americas", "ApplicationServices", "SQLGen2InstancesF1Micro", "OnDemand")
+	ssd := testSKU("Cloud SQL for MySQL: Zonal - SSD storage in Americas", "Storage", "SSD", "OnDemand")
+	commit := testSKU("Commitment - dollar based v1: Cloud SQL database vCPU", "ApplicationServices", "SQLGen2InstancesCPU", "Commit1Yr")
+	network := testSKU("Network Internet Egress from Americas to Americas", "Network", "PremiumInternetEgress", "OnDemand")
+
+	c := emptyCloudSQLCatalog()
+	c.assignSKUCategories([]*billingpb.Sku{cpu, ram, micro, ssd, commit, network})
+
+	expected := emptyCloudSQLCatalog()
+	expected.instances["SQLGen2InstancesCPU"] = []*billingpb.Sku{cpu}
+	expected.instances["SQLGen2InstancesRAM"] = []*billingpb.Sku{ram}
+	expected.instances["SQLGen2InstancesF1Micro"] = []*billingpb.Sku{micro}
+	expected.storage["SSD"] = []*billingpb.Sku{ssd}
+
+	if !reflect.DeepEqual(c, expected) {
+		t.Errorf("catalog.assignSKUCategories(skus) -> %+v; want %+v", c, expected)
+	}
+
+	if _, err := c.SharedCoreSKUs("db-g1-small"); err == nil {
+		t.Errorf("catalog.SharedCoreSKUs(db-g1-small) returned no error for a missing resource group")
+	}
+
+	if skus, err := c.StorageSKUs("PD_SSD"); err != nil || !reflect.DeepEqual(skus, []*billingpb.Sku{ssd}) {
+		t.Errorf("catalog.StorageSKUs(PD_SSD) = %+v, %+v; want %+v, <nil>", skus, err, []*billingpb.Sku{ssd})
+	}
+}
@@ -0,0 +1,109 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+// CloudStorageCatalog holds the information from the billing catalog for Cloud Storage SKUs.
+type CloudStorageCatalog struct {
+	service    string
+	storage    map[string][]*billingpb.Sku
+	operations map[string][]*billingpb.Sku
+	egress     []*billingpb.Sku
+}
+
+// NewCloudStorageCatalog creates a catalog instance, calls the billing API and stores its response.
+// Storage SKUs are stored by resource group and operation SKUs by operation class (ClassA, ClassB).
+func NewCloudStorageCatalog(ctx context.Context) (*CloudStorageCatalog, error) {
+	c := emptyCloudStorageCatalog()
+
+	skus, err := GetSKUs(ctx, c.service)
+	if err != nil {
+		return nil, err
+	}
+	c.assignSKUCategories(skus)
+
+	return c, nil
+}
+
+func emptyCloudStorageCatalog() *CloudStorageCatalog {
+	c := new(CloudStorageCatalog)
+	c.service = CloudStorageService
+	c.storage = map[string][]*billingpb.Sku{}
+	c.operations = map[string][]*billingpb.Sku{}
+	return c
+}
+
+func (catalog *CloudStorageCatalog) assignSKUCategories(skus []*billingpb.Sku) {
+	for _, sku := range skus {
+		c := sku.Category
+		if c.UsageType != "OnDemand" {
+			continue
+		}
+
+		switch {
+		case c.ResourceFamily == "Storage" && strings.Contains(sku.Description, "Class A"):
+			catalog.operations["ClassA"] = append(catalog.operations["ClassA"], sku)
+		case c.ResourceFamily == "Storage" && strings.Contains(sku.Description, "Class B"):
+			catalog.operations["ClassB"] = append(catalog.operations["ClassB"], sku)
+		case c.ResourceFamily == "Storage":
+			catalog.storage[c.ResourceGroup] = append(catalog.storage[c.ResourceGroup], sku)
+		case c.ResourceFamily == "Network" && strings.HasPrefix(sku.Description, "Download"):
+			catalog.egress = append(catalog.egress, sku)
+		default:
+
+		}
+	}
+}
+
+// StorageSKUs returns the SKUs for data stored in the specified storage class and location type
+// (REGION, DUAL_REGION, MULTI_REGION).
+func (catalog *CloudStorageCatalog) StorageSKUs(storageClass, locationType string) ([]*billingpb.Sku, error) {
+	var rg string
+	switch storageClass {
+	case "STANDARD":
+		switch locationType {
+		case "MULTI_REGION":
+			rg = "MultiRegionalStorage"
+		case "DUAL_REGION":
+			rg = "DualRegionalStorage"
+		default:
+			rg = "RegionalStorage"
+		}
+	case "NEARLINE":
+		rg = "NearlineStorage"
+	case "COLDLINE":
+		rg = "ColdlineStorage"
+	case "ARCHIVE":
+		rg = "ArchiveStorage"
+	default:
+		return nil, fmt.Errorf("invalid storage class '" + storageClass + "'")
+	}
+
+	skus, ok := catalog.storage[rg]
+	if !ok {
+		return nil, NewSKUNotFoundError("found no Cloud Storage SKU of this resource group")
+	}
+	return skus, nil
+}
+
+// OperationSKUs returns the SKUs for the operations of the specified class (A or B).
+func (catalog *CloudStorageCatalog) OperationSKUs(class string) ([]*billingpb.Sku, error) {
+	skus, ok := catalog.operations["Class"+class]
+	if !ok {
+		return nil, NewSKUNotFoundError("found no Cloud Storage SKU for class " + class + " operations")
+	}
+	return skus, nil
+}
+
+// EgressSKUs returns the SKUs for data downloaded from Cloud Storage.
+func (catalog *CloudStorageCatalog) EgressSKUs() ([]*billingpb.Sku, error) {
+	if len(catalog.egress) == 0 {
+		return nil, NewSKUNotFoundError("found no Cloud Storage egress SKU")
+	}
+	return catalog.egress, nil
+}
@@ -0,0 +1,57 @@
+package billing
+
+import (
+	"reflect"
+	"testing"
+
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+func TestCloudStorageAssignSKUCategories(t *testing.T) {
+	regional := testSKU("Standard Storage Iowa", "Storage", "RegionalStorage", "OnDemand")
+	multi := testSKU("Multi-Region Standard Storage US", "Storage", "MultiRegionalStorage", "OnDemand")
+	nearline := testSKU("Nearline Storage Iowa", "Storage", "NearlineStorage", "OnDemand")
+	classA := testSKU("Regional Storage Class A Operations", "Storage", "RegionalOps", "OnDemand")
+	classB := testSKU("Nearline Storage Class B Operations", "Storage", "NearlineOps", "OnDemand")
+	download := testSKU("Download China (excluding Hong Kong)", "Network", "InternetEgress", "OnDemand")
+	commit := testSKU("Commitment: Standard Storage Iowa", "Storage", "RegionalStorage", "Commit1Yr")
+
+	c := emptyCloudStorageCatalog()
+	c.assignSKUCategories([]*billingpb.Sku{regional, multi, nearline, classA, classB, download, commit})
+
+	expected := emptyCloudStorageCatalog()
+	expected.storage["RegionalStorage"] = []*billingpb.Sku{regional}
+	expected.storage["MultiRegionalStorage"] = []*billingpb.Sku{multi}
+	expected.storage["NearlineStorage"] = []*billingpb.Sku{nearline}
+	expected.operations["ClassA"] = []*billingpb.Sku{classA}
+	expected.operations["ClassB"] = []*billingpb.Sku{classB}
+	expected.egress = []*billingpb.Sku{download}
+
+	if !reflect.DeepEqual(c, expected) {
+		t.Errorf("catalog.assignSKUCategories(skus) -> %+v; want %+v", c, expected)
+	}
+
+	tests := []struct {
+		name         string
+		storageClass string
+		locationType string
+		skus         []*billingpb.Sku
+		ok           bool
+	}{
+		{"standard_region", "STANDARD", "REGION", []*billingpb.Sku{regional}, true},
+		{"standard_multi_region", "STANDARD", "MULTI_REGION", []*billingpb.Sku{multi}, true},
+		{"nearline", "NEARLINE", "MULTI_REGION", []*billingpb.Sku{nearline}, true},
+		{"missing_group", "ARCHIVE", "REGION", nil, false},
+		{"invalid_class", "ICE", "REGION", nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			skus, err := c.StorageSKUs(test.storageClass, test.locationType)
+			if (err == nil) != test.ok || !reflect.DeepEqual(skus, test.skus) {
+				t.Errorf("catalog.StorageSKUs(%s, %s) = %+v, %+v; want %+v", test.storageClass, test.locationType,
+					skus, err, test.skus)
+			}
+		})
+	}
+}
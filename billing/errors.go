@@ -0,0 +1,25 @@
+package billing
+
+import "errors"
+
+// ErrSKUNotFound is matched (with errors.Is) by the errors of the lookups that found no SKU
+// for a resource, whatever the SKU looked for.
+var ErrSKUNotFound = errors.New("SKU not found")
+
+// skuNotFoundError is an error of a lookup that found no SKU, with a message saying which one.
+type skuNotFoundError struct {
+	msg string
+}
+
+func (e *skuNotFoundError) Error() string {
+	return e.msg
+}
+
+func (e *skuNotFoundError) Is(target error) bool {
+	return target == ErrSKUNotFound
+}
+
+// NewSKUNotFoundError returns an error matching ErrSKUNotFound with the given message.
+func NewSKUNotFoundError(msg string) error {
+	return &skuNotFoundError{msg: msg}
+}
@@ -0,0 +1,103 @@
+package billing
+
+import (
+	"strings"
+	"unicode"
+
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+// globalRegion is the service region of the SKUs available in all regions.
+const globalRegion = "global"
+
+// SKUKey identifies the SKUs of a resource group and usage type available in a region.
+// Family is the machine family of instance SKUs (see MachineFamily) and empty for the other SKUs.
+type SKUKey struct {
+	ResourceGroup string
+	UsageType     string
+	Region        string
+	Family        string
+}
+
+// skuIndex stores SKUs by key, so that resources find their candidate SKUs without scanning the whole service.
+// Global SKUs are stored under the "global" region and match any region.
+type skuIndex map[SKUKey][]*billingpb.Sku
+
+// add stores the SKU under the given machine family in each of its service regions.
+func (idx skuIndex) add(sku *billingpb.Sku, family string) {
+	if len(sku.ServiceRegions) == 0 {
+		return
+	}
+
+	regions := sku.ServiceRegions
+	if regions[0] == globalRegion {
+		regions = regions[:1]
+	}
+	for _, r := range regions {
+		k := SKUKey{ResourceGroup: sku.Category.ResourceGroup, UsageType: sku.Category.UsageType, Region: r, Family: family}
+		idx[k] = append(idx[k], sku)
+	}
+}
+
+// lookup returns the SKUs of the key, followed by the global SKUs of the same resource group, usage type and family.
+func (idx skuIndex) lookup(k SKUKey) []*billingpb.Sku {
+	skus := idx[k]
+	if k.Region == globalRegion {
+		return skus
+	}
+
+	k.Region = globalRegion
+	if global := idx[k]; len(global) > 0 {
+		skus = append(append([]*billingpb.Sku{}, skus...), global...)
+	}
+	return skus
+}
+
+// MachineFamily returns the machine family whose SKUs price the cores and memory of the machine type.
+// N1 custom, shared core (f1, g1) and predefined machine types are priced as N1; the memory-optimized
+// machine types (m1, m2 and the N1 megamem and ultramem) share their SKUs.
+func MachineFamily(machineType string) string {
+	switch {
+	case strings.HasPrefix(machineType, "custom-") || strings.HasPrefix(machineType, "f1-") ||
+		strings.HasPrefix(machineType, "g1-"):
+		return "n1"
+	case strings.HasPrefix(machineType, "n1-mega") || strings.HasPrefix(machineType, "n1-ultra") ||
+		strings.HasPrefix(machineType, "m1-") || strings.HasPrefix(machineType, "m2-"):
+		return "memory-optimized"
+	}
+
+	if i := strings.Index(machineType, "-"); i > 0 {
+		return machineType[:i]
+	}
+	return machineType
+}
+
+// skuMachineFamily returns the machine family of a core or RAM SKU from its description,
+// e.g. "Preemptible N2D AMD Instance Core running in Americas" is of the n2d family.
+func skuMachineFamily(description string) string {
+	switch {
+	case strings.Contains(description, "Compute optimized"):
+		return "c2"
+	case strings.Contains(description, "Memory-optimized") || strings.Contains(description, "Memory Optimized"):
+		return "memory-optimized"
+	case strings.Contains(description, "Sole Tenancy"):
+		return "sole-tenancy"
+	}
+
+	for _, w := range strings.Fields(description) {
+		if isFamilyName(w) {
+			return strings.ToLower(w)
+		}
+	}
+
+	// N1 custom and committed use SKUs don't have the machine family specified.
+	return "n1"
+}
+
+// isFamilyName reports whether the word is a machine family name such as N1, E2 or N2D.
+func isFamilyName(w string) bool {
+	if len(w) < 2 || len(w) > 3 || !unicode.IsUpper(rune(w[0])) || !unicode.IsDigit(rune(w[1])) {
+		return false
+	}
+	return len(w) == 2 || unicode.IsUpper(rune(w[2]))
+}
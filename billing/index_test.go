@@ -0,0 +1,93 @@
+package billing
+
+import (
+	"reflect"
+	"testing"
+
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+func TestMachineFamily(t *testing.T) {
+	tests := []struct {
+		machineType string
+		family      string
+	}{
+		{"n1-standard-1", "n1"},
+		{"custom-2-4096", "n1"},
+		{"f1-micro", "n1"},
+		{"n2-custom-4-8192-ext", "n2"},
+		{"n2d-highmem-2", "n2d"},
+		{"e2-custom-2-4096", "e2"},
+		{"c2-standard-8", "c2"},
+		{"n1-megamem-96", "memory-optimized"},
+		{"m2-ultramem-208", "memory-optimized"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.machineType, func(t *testing.T) {
+			if f := MachineFamily(test.machineType); f != test.family {
+				t.Errorf("MachineFamily(%s) = %s; want %s", test.machineType, f, test.family)
+			}
+		})
+	}
+}
+
+func TestSKUMachineFamily(t *testing.T) {
+	tests := []struct {
+		description string
+		family      string
+	}{
+		{"N1 Predefined Instance Core running in Zurich", "n1"},
+		{"Custom Extended Instance Ram running in Americas", "n1"},
+		{"Commitment v1: Cpu in Montreal for 1 Year", "n1"},
+		{"Preemptible N2 Custom Instance Core running in Sao Paulo", "n2"},
+		{"N2D AMD Instance Ram running in Americas", "n2d"},
+		{"Commitment v1: E2 Ram in Tokyo for 3 Year", "e2"},
+		{"Preemptible Compute optimized Ram running in Montreal", "c2"},
+		{"Memory Optimized Upgrade Premium for Memory-optimized Instance Core running in Americas", "memory-optimized"},
+		{"Sole Tenancy Instance Ram running in Jakarta", "sole-tenancy"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if f := skuMachineFamily(test.description); f != test.family {
+				t.Errorf("skuMachineFamily(%s) = %s; want %s", test.description, f, test.family)
+			}
+		})
+	}
+}
+
+func TestSKUIndexLookup(t *testing.T) {
+	regional := testSKU("N2 Instance Core running in Americas", "Compute", "CPU", "OnDemand")
+	regional.ServiceRegions = []string{"us-central1", "us-east1"}
+	global := testSKU("N2 Instance Core running in Americas", "Compute", "CPU", "OnDemand")
+	global.ServiceRegions = []string{"global"}
+	preemptible := testSKU("Preemptible N2 Instance Core running in Americas", "Compute", "CPU", "Preemptible")
+	preemptible.ServiceRegions = []string{"us-central1"}
+	noRegion := testSKU("N2 Instance Core", "Compute", "CPU", "OnDemand")
+
+	idx := skuIndex{}
+	for _, sku := range []*billingpb.Sku{regional, global, preemptible, noRegion} {
+		idx.add(sku, skuMachineFamily(sku.Description))
+	}
+
+	tests := []struct {
+		name     string
+		key      SKUKey
+		expected []*billingpb.Sku
+	}{
+		{"regional_and_global", SKUKey{"CPU", "OnDemand", "us-east1", "n2"}, []*billingpb.Sku{regional, global}},
+		{"global_only", SKUKey{"CPU", "OnDemand", "europe-west1", "n2"}, []*billingpb.Sku{global}},
+		{"usage_type", SKUKey{"CPU", "Preemptible", "us-central1", "n2"}, []*billingpb.Sku{preemptible}},
+		{"other_family", SKUKey{"CPU", "OnDemand", "us-central1", "e2"}, nil},
+		{"other_group", SKUKey{"RAM", "OnDemand", "us-central1", "n2"}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if skus := idx.lookup(test.key); !reflect.DeepEqual(skus, test.expected) {
+				t.Errorf("index.lookup(%+v) = %+v; want %+v", test.key, skus, test.expected)
+			}
+		})
+	}
+}
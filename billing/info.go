@@ -12,6 +12,9 @@ import (
 
 const nano = float64(1000 * 1000 * 1000)
 
+// DefaultCurrency is the currency of the prices unless another one is requested.
+const DefaultCurrency = "USD"
+
 func fitsDescription(sku *billingpb.Sku, contains, omits []string) bool {
 	if contains != nil {
 		for _, d := range contains {
@@ -49,6 +52,17 @@ func fitsRegion(sku *billingpb.Sku, region string) bool {
 	return false
 }
 
+// PricingTier returns the last tier rate of the SKU accepted by correctTieredRate, nil if none is accepted.
+func PricingTier(sku *billingpb.Sku, correctTieredRate func(*billingpb.PricingExpression_TierRate) bool) *billingpb.PricingExpression_TierRate {
+	pExpr := sku.PricingInfo[0].PricingExpression
+	for i := len(pExpr.TieredRates) - 1; i >= 0; i-- {
+		if correctTieredRate(pExpr.TieredRates[i]) {
+			return pExpr.TieredRates[i]
+		}
+	}
+	return nil
+}
+
 // PricingInfo returns the pricing information of an SKU.
 func PricingInfo(sku *billingpb.Sku, correctTieredRate func(*billingpb.PricingExpression_TierRate) bool) (usageUnit string,
 	pricePerUnit float64, currencyType string) {
@@ -56,24 +70,56 @@ func PricingInfo(sku *billingpb.Sku, correctTieredRate func(*billingpb.PricingEx
 	pExpr := sku.PricingInfo[0].PricingExpression
 	usageUnit = strings.Split(pExpr.UsageUnitDescription, " ")[0]
 
-	var tr *billingpb.PricingExpression_TierRate
-	for i := len(pExpr.TieredRates) - 1; i >= 0; i-- {
-		if correctTieredRate(pExpr.TieredRates[i]) {
-			tr = pExpr.TieredRates[i]
-			break
-		}
-	}
+	tr := PricingTier(sku, correctTieredRate)
 	if tr == nil {
 		return
 	}
 
-	pricePerUnit = float64(tr.UnitPrice.Nanos) / nano
+	pricePerUnit = float64(tr.UnitPrice.Units) + float64(tr.UnitPrice.Nanos)/nano
 	currencyType = tr.UnitPrice.CurrencyCode
 	return
 }
 
+// GraduatedPricingInfo returns the pricing information of an SKU whose tier rates are graduated, the rate of each tier
+// applying only to the usage within it, for the given amount of usage: the price per unit is the cost of the amount
+// over all the tiers it spans divided by the amount, or the rate of the first tier if there is no usage.
+func GraduatedPricingInfo(sku *billingpb.Sku, amount float64) (usageUnit string, pricePerUnit float64, currencyType string) {
+	pExpr := sku.PricingInfo[0].PricingExpression
+	usageUnit = strings.Split(pExpr.UsageUnitDescription, " ")[0]
+	if len(pExpr.TieredRates) == 0 {
+		return
+	}
+
+	rate := func(tr *billingpb.PricingExpression_TierRate) float64 {
+		return float64(tr.UnitPrice.Units) + float64(tr.UnitPrice.Nanos)/nano
+	}
+	currencyType = pExpr.TieredRates[0].UnitPrice.CurrencyCode
+	if amount <= 0 {
+		return usageUnit, rate(pExpr.TieredRates[0]), currencyType
+	}
+
+	cost := 0.0
+	for i, tr := range pExpr.TieredRates {
+		end := amount
+		if i+1 < len(pExpr.TieredRates) && pExpr.TieredRates[i+1].StartUsageAmount < amount {
+			end = pExpr.TieredRates[i+1].StartUsageAmount
+		}
+		if end > tr.StartUsageAmount {
+			cost += (end - tr.StartUsageAmount) * rate(tr)
+		}
+	}
+	return usageUnit, cost / amount, currencyType
+}
+
 // GetSKUs returns the SKUs from the billing API for the specific service or an error.
+// Prices are in USD.
 func GetSKUs(ctx context.Context, service string) ([]*billingpb.Sku, error) {
+	return GetSKUsInCurrency(ctx, service, DefaultCurrency)
+}
+
+// GetSKUsInCurrency returns the SKUs from the billing API for the specific service with prices in the given
+// ISO 4217 currency, or an error.
+func GetSKUsInCurrency(ctx context.Context, service, currency string) ([]*billingpb.Sku, error) {
 	var skus []*billingpb.Sku
 
 	c, err := billing.NewCloudCatalogClient(ctx)
@@ -82,7 +128,8 @@ func GetSKUs(ctx context.Context, service string) ([]*billingpb.Sku, error) {
 	}
 
 	req := &billingpb.ListSkusRequest{
-		Parent: service,
+		Parent:       service,
+		CurrencyCode: currency,
 	}
 
 	it := c.ListSkus(ctx, req)
@@ -114,7 +161,7 @@ func DescriptionFilter(skus []*billingpb.Sku, contains, omits []string) ([]*bill
 	}
 
 	if len(filtered) == 0 {
-		return nil, fmt.Errorf("no SKU with the specified description")
+		return nil, NewSKUNotFoundError("no SKU with the specified description")
 	}
 
 	return filtered, nil
@@ -135,7 +182,7 @@ func RegionFilter(skus []*billingpb.Sku, region string) ([]*billingpb.Sku, error
 	}
 
 	if len(filtered) == 0 {
-		return nil, fmt.Errorf("region '" + region + "' is invalid")
+		return nil, NewSKUNotFoundError("region '" + region + "' is invalid")
 	}
 
 	return filtered, nil
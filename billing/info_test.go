@@ -1,10 +1,13 @@
 package billing
 
 import (
+	"fmt"
 	"math"
+	"reflect"
 	"testing"
 
 	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+	"google.golang.org/genproto/googleapis/type/money"
 )
 
 func TestFitsDescription(t *testing.T) {
@@ -103,3 +106,93 @@ func TestGetPricingInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestPricingInfoCurrency(t *testing.T) {
+	price := func(currency string, units int64, nanos int32) *billingpb.Sku {
+		return &billingpb.Sku{SkuId: "A", PricingInfo: []*billingpb.PricingInfo{{PricingExpression: &billingpb.PricingExpression{
+			UsageUnitDescription: "hour",
+			TieredRates:          []*billingpb.PricingExpression_TierRate{{UnitPrice: &money.Money{CurrencyCode: currency, Units: units, Nanos: nanos}}},
+		}}}}
+	}
+
+	tests := []struct {
+		name     string
+		sku      *billingpb.Sku
+		price    float64
+		currency string
+	}{
+		{"nanos", price("USD", 0, 31611000), 0.031611, "USD"},
+		{"units_and_nanos", price("EUR", 2, 500000000), 2.5, "EUR"},
+		{"units", price("JPY", 4, 0), 4, "JPY"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, p, c := PricingInfo(test.sku, func(*billingpb.PricingExpression_TierRate) bool { return true })
+			if p != test.price || c != test.currency {
+				t.Errorf("PricingInfo(sku) = %f %s; want %f %s", p, c, test.price, test.currency)
+			}
+		})
+	}
+}
+
+func TestGraduatedPricingInfo(t *testing.T) {
+	const epsilon = 1e-10
+	// Egress: the first GiB is free, 0.12 up to 1 TiB and 0.11 above.
+	sku := &billingpb.Sku{PricingInfo: []*billingpb.PricingInfo{{PricingExpression: &billingpb.PricingExpression{
+		UsageUnitDescription: "gibibyte",
+		TieredRates: []*billingpb.PricingExpression_TierRate{
+			{StartUsageAmount: 0, UnitPrice: &money.Money{CurrencyCode: "USD"}},
+			{StartUsageAmount: 1, UnitPrice: &money.Money{CurrencyCode: "USD", Nanos: 120000000}},
+			{StartUsageAmount: 1024, UnitPrice: &money.Money{CurrencyCode: "USD", Nanos: 110000000}},
+		},
+	}}}}
+
+	tests := []struct {
+		name   string
+		amount float64
+		cost   float64
+	}{
+		{"no_usage", 0, 0},
+		{"free_tier", 0.5, 0},
+		{"second_tier", 11, 10 * 0.12},
+		{"two_tiers", 2048, 1023*0.12 + 1024*0.11},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			unit, p, c := GraduatedPricingInfo(sku, test.amount)
+			if unit != "gibibyte" || math.Abs(p*test.amount-test.cost) > epsilon || c != "USD" {
+				t.Errorf("GraduatedPricingInfo(sku, %f) = %s %f %s, want a cost of %f USD per gibibyte",
+					test.amount, unit, p*test.amount, c, test.cost)
+			}
+		})
+	}
+}
+
+func TestCheckCurrency(t *testing.T) {
+	sku := func(id, currency string) *billingpb.Sku {
+		return &billingpb.Sku{SkuId: id, PricingInfo: []*billingpb.PricingInfo{{PricingExpression: &billingpb.PricingExpression{
+			TieredRates: []*billingpb.PricingExpression_TierRate{{UnitPrice: &money.Money{CurrencyCode: currency}}},
+		}}}}
+	}
+
+	tests := []struct {
+		name     string
+		skus     []*billingpb.Sku
+		currency string
+		err      error
+	}{
+		{"same_currency", []*billingpb.Sku{sku("A", "EUR"), sku("B", "EUR")}, "EUR", nil},
+		{"mixed_currencies", []*billingpb.Sku{sku("A", "EUR"), sku("B", "USD")}, "EUR",
+			fmt.Errorf("SKU B is priced in USD instead of EUR")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := checkCurrency(test.skus, test.currency); !reflect.DeepEqual(err, test.err) {
+				t.Errorf("checkCurrency(skus, %s) = %+v; want %+v", test.currency, err, test.err)
+			}
+		})
+	}
+}
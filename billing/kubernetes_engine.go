@@ -0,0 +1,75 @@
+package billing
+
+import (
+	"context"
+	"strings"
+
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+// KubernetesEngineCatalog holds the information from the billing catalog for Kubernetes Engine SKUs.
+type KubernetesEngineCatalog struct {
+	service   string
+	clusters  []*billingpb.Sku
+	autopilot map[string][]*billingpb.Sku
+}
+
+// NewKubernetesEngineCatalog creates a catalog instance, calls the billing API and stores its response.
+// Autopilot pod SKUs are stored by the requested resource (CPU, Memory, Storage).
+func NewKubernetesEngineCatalog(ctx context.Context) (*KubernetesEngineCatalog, error) {
+	c := emptyKubernetesEngineCatalog()
+
+	skus, err := GetSKUs(ctx, c.service)
+	if err != nil {
+		return nil, err
+	}
+	c.assignSKUCategories(skus)
+
+	return c, nil
+}
+
+func emptyKubernetesEngineCatalog() *KubernetesEngineCatalog {
+	c := new(KubernetesEngineCatalog)
+	c.service = KubernetesEngineService
+	c.autopilot = map[string][]*billingpb.Sku{}
+	return c
+}
+
+func (catalog *KubernetesEngineCatalog) assignSKUCategories(skus []*billingpb.Sku) {
+	for _, sku := range skus {
+		if sku.Category.UsageType != "OnDemand" {
+			continue
+		}
+
+		d := sku.Description
+		switch {
+		case strings.Contains(d, "Kubernetes Clusters"):
+			catalog.clusters = append(catalog.clusters, sku)
+		case strings.HasPrefix(d, "Autopilot Pod") && strings.Contains(d, "CPU"):
+			catalog.autopilot["CPU"] = append(catalog.autopilot["CPU"], sku)
+		case strings.HasPrefix(d, "Autopilot Pod") && strings.Contains(d, "Memory"):
+			catalog.autopilot["Memory"] = append(catalog.autopilot["Memory"], sku)
+		case strings.HasPrefix(d, "Autopilot Pod") && strings.Contains(d, "Storage"):
+			catalog.autopilot["Storage"] = append(catalog.autopilot["Storage"], sku)
+		default:
+
+		}
+	}
+}
+
+// ClusterFeeSKUs returns the SKUs for the cluster management fee.
+func (catalog *KubernetesEngineCatalog) ClusterFeeSKUs() ([]*billingpb.Sku, error) {
+	if len(catalog.clusters) == 0 {
+		return nil, NewSKUNotFoundError("found no cluster management fee SKU")
+	}
+	return catalog.clusters, nil
+}
+
+// AutopilotSKUs returns the SKUs for the pod resource requests (CPU, Memory, Storage) of Autopilot clusters.
+func (catalog *KubernetesEngineCatalog) AutopilotSKUs(resource string) ([]*billingpb.Sku, error) {
+	skus, ok := catalog.autopilot[resource]
+	if !ok {
+		return nil, NewSKUNotFoundError("found no Autopilot SKU for pod " + resource + " requests")
+	}
+	return skus, nil
+}
@@ -0,0 +1,35 @@
+package billing
+
+import (
+	"reflect"
+	"testing"
+
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+func TestKubernetesEngineAssignSKUCategories(t *testing.T) {
+	zonal := testSKU("Zonal Kubernetes Clusters", "Compute", "Google Kubernetes Engine", "OnDemand")
+	regional := testSKU("Regional Kubernetes Clusters", "Compute", "Google Kubernetes Engine", "OnDemand")
+	cpu := testSKU("Autopilot Pod mCPU Requests (us-central1)", "Compute", "CPU", "OnDemand")
+	mem := testSKU("Autopilot Pod Memory Requests (us-central1)", "Compute", "RAM", "OnDemand")
+	storage := testSKU("Autopilot Pod Ephemeral Storage Requests (us-central1)", "Compute", "Storage", "OnDemand")
+	spot := testSKU("Autopilot Spot Pod mCPU Requests (us-central1)", "Compute", "CPU", "OnDemand")
+	commit := testSKU("Commitment: Autopilot Pod mCPU Requests", "Compute", "CPU", "Commit1Yr")
+
+	c := emptyKubernetesEngineCatalog()
+	c.assignSKUCategories([]*billingpb.Sku{zonal, regional, cpu, mem, storage, spot, commit})
+
+	expected := emptyKubernetesEngineCatalog()
+	expected.clusters = []*billingpb.Sku{zonal, regional}
+	expected.autopilot["CPU"] = []*billingpb.Sku{cpu}
+	expected.autopilot["Memory"] = []*billingpb.Sku{mem}
+	expected.autopilot["Storage"] = []*billingpb.Sku{storage}
+
+	if !reflect.DeepEqual(c, expected) {
+		t.Errorf("catalog.assignSKUCategories(skus) -> %+v; want %+v", c, expected)
+	}
+
+	if _, err := emptyKubernetesEngineCatalog().ClusterFeeSKUs(); err == nil {
+		t.Errorf("emptyKubernetesEngineCatalog().ClusterFeeSKUs() returned no error")
+	}
+}
@@ -0,0 +1,82 @@
+package billing
+
+import (
+	"context"
+	"strings"
+
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+// NetworkCatalog holds the information from the billing catalog for the SKUs of the Network resource family,
+// which are split between the Compute Engine and Networking services.
+type NetworkCatalog struct {
+	services []string
+	groups   map[string][]*billingpb.Sku
+}
+
+// NewNetworkCatalog creates a catalog instance, calls the billing API and stores its response.
+// SKUs are stored by resource group, except the Cloud NAT SKUs which are all stored in the NAT group.
+func NewNetworkCatalog(ctx context.Context) (*NetworkCatalog, error) {
+	c := emptyNetworkCatalog()
+
+	for _, s := range c.services {
+		skus, err := GetSKUs(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		c.assignSKUCategories(skus)
+	}
+
+	return c, nil
+}
+
+func emptyNetworkCatalog() *NetworkCatalog {
+	c := new(NetworkCatalog)
+	c.services = []string{ComputeEngineService, NetworkingService}
+	c.groups = map[string][]*billingpb.Sku{}
+	return c
+}
+
+func (catalog *NetworkCatalog) assignSKUCategories(skus []*billingpb.Sku) {
+	for _, sku := range skus {
+		c := sku.Category
+		if c.ResourceFamily != "Network" || c.UsageType != "OnDemand" {
+			continue
+		}
+
+		g := c.ResourceGroup
+		if strings.Contains(sku.Description, "Cloud NAT") {
+			g = "NAT"
+		}
+		catalog.groups[g] = append(catalog.groups[g], sku)
+	}
+}
+
+func (catalog *NetworkCatalog) groupSKUs(group, kind string) ([]*billingpb.Sku, error) {
+	skus, ok := catalog.groups[group]
+	if !ok {
+		return nil, NewSKUNotFoundError("found no " + kind + " SKU")
+	}
+	return skus, nil
+}
+
+// NATSKUs returns the SKUs for Cloud NAT gateway uptime and data processing.
+func (catalog *NetworkCatalog) NATSKUs() ([]*billingpb.Sku, error) {
+	return catalog.groupSKUs("NAT", "Cloud NAT")
+}
+
+// IPAddressSKUs returns the SKUs for static and in use external IP addresses.
+func (catalog *NetworkCatalog) IPAddressSKUs() ([]*billingpb.Sku, error) {
+	return catalog.groupSKUs("IpAddress", "IP address")
+}
+
+// LoadBalancingSKUs returns the SKUs for forwarding rules and the data they process.
+func (catalog *NetworkCatalog) LoadBalancingSKUs() ([]*billingpb.Sku, error) {
+	return catalog.groupSKUs("LoadBalancing", "load balancing")
+}
+
+// EgressSKUs returns the SKUs for the egress of the specified resource group
+// (PremiumInternetEgress, InterregionEgress, InterzoneEgress etc.).
+func (catalog *NetworkCatalog) EgressSKUs(group string) ([]*billingpb.Sku, error) {
+	return catalog.groupSKUs(group, group)
+}
@@ -0,0 +1,39 @@
+package billing
+
+import (
+	"reflect"
+	"testing"
+
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+func TestNetworkAssignSKUCategories(t *testing.T) {
+	static := testSKU("Static Ip Charge", "Network", "IpAddress", "OnDemand")
+	inUse := testSKU("External IP Charge on a Standard VM", "Network", "IpAddress", "OnDemand")
+	internet := testSKU("Network Internet Egress from Americas to EMEA", "Network", "PremiumInternetEgress", "OnDemand")
+	zone := testSKU("Network Inter Zone Egress", "Network", "InterzoneEgress", "OnDemand")
+	lb := testSKU("Network Load Balancing: Forwarding Rule Minimum Service Charge in Americas", "Network",
+		"LoadBalancing", "OnDemand")
+	gateway := testSKU("Networking Cloud NAT Gateway Uptime charge", "Network", "Cloud NAT", "OnDemand")
+	processing := testSKU("Networking Cloud NAT Data Processing", "Network", "Cloud NAT", "OnDemand")
+	core := testSKU("N1 Predefined Instance Core running in Americas", "Compute", "N1Standard", "OnDemand")
+	commit := testSKU("Commitment: Static Ip Charge", "Network", "IpAddress", "Commit1Yr")
+
+	c := emptyNetworkCatalog()
+	c.assignSKUCategories([]*billingpb.Sku{static, inUse, internet, zone, lb, gateway, processing, core, commit})
+
+	expected := emptyNetworkCatalog()
+	expected.groups["IpAddress"] = []*billingpb.Sku{static, inUse}
+	expected.groups["PremiumInternetEgress"] = []*billingpb.Sku{internet}
+	expected.groups["InterzoneEgress"] = []*billingpb.Sku{zone}
+	expected.groups["LoadBalancing"] = []*billingpb.Sku{lb}
+	expected.groups["NAT"] = []*billingpb.Sku{gateway, processing}
+
+	if !reflect.DeepEqual(c, expected) {
+		t.Errorf("catalog.assignSKUCategories(skus) -> %+v; want %+v", c, expected)
+	}
+
+	if _, err := c.EgressSKUs("InterregionEgress"); err == nil {
+		t.Errorf("catalog.EgressSKUs(InterregionEgress) returned no error")
+	}
+}
@@ -0,0 +1,41 @@
+package billing
+
+import (
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+// Query selects SKUs by their category, service region and description.
+// Empty fields match any SKU.
+type Query struct {
+	ResourceFamily string
+	ResourceGroup  string
+	UsageType      string
+	// Region matches the SKUs available in the region, including global SKUs.
+	Region string
+	// Contains and Omits are the strings the description must contain or not contain.
+	Contains []string
+	Omits    []string
+	// Description is an additional predicate on the description.
+	Description func(string) bool
+}
+
+// Matches returns whether the SKU matches all the fields of the query.
+func (q Query) Matches(sku *billingpb.Sku) bool {
+	c := sku.Category
+	switch {
+	case q.ResourceFamily != "" && c.GetResourceFamily() != q.ResourceFamily:
+		return false
+	case q.ResourceGroup != "" && c.GetResourceGroup() != q.ResourceGroup:
+		return false
+	case q.UsageType != "" && c.GetUsageType() != q.UsageType:
+		return false
+	case q.Region != "" && !fitsRegion(sku, q.Region):
+		return false
+	case !fitsDescription(sku, q.Contains, q.Omits):
+		return false
+	case q.Description != nil && !q.Description(sku.Description):
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,80 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+func TestQueryMatches(t *testing.T) {
+	sku := testSKU("N1 Predefined Instance Core running in Americas", "Compute", "N1Standard", "OnDemand")
+	sku.ServiceRegions = []string{"us-central1", "us-east1"}
+
+	tests := []struct {
+		name  string
+		query Query
+		match bool
+	}{
+		{"empty", Query{}, true},
+		{"category", Query{ResourceFamily: "Compute", ResourceGroup: "N1Standard", UsageType: "OnDemand"}, true},
+		{"wrong_group", Query{ResourceGroup: "CPU"}, false},
+		{"wrong_usage_type", Query{UsageType: "Preemptible"}, false},
+		{"region", Query{Region: "us-east1"}, true},
+		{"wrong_region", Query{Region: "europe-west1"}, false},
+		{"contains", Query{Contains: []string{"Core", "Americas"}, Omits: []string{"Custom"}}, true},
+		{"omits", Query{Omits: []string{"Predefined"}}, false},
+		{"predicate", Query{Description: func(d string) bool { return strings.HasPrefix(d, "N1") }}, true},
+		{"wrong_predicate", Query{Description: func(d string) bool { return strings.HasPrefix(d, "N2") }}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if m := test.query.Matches(sku); m != test.match {
+				t.Errorf("%+v.Matches(sku) = %t; want %t", test.query, m, test.match)
+			}
+		})
+	}
+}
+
+func TestCatalogLazyLoading(t *testing.T) {
+	core := testSKU("N1 Predefined Instance Core running in Americas", "Compute", "N1Standard", "OnDemand")
+	ssd := testSKU("Cloud SQL for MySQL: Zonal - SSD storage in Americas", "Storage", "SSD", "OnDemand")
+	services := map[string][]*billingpb.Sku{ComputeEngineService: {core}, CloudSQLService: {ssd}}
+
+	var fetched []string
+	c := NewCatalogFromSKUs(nil)
+	c.fetch = func(_ context.Context, service string) ([]*billingpb.Sku, error) {
+		fetched = append(fetched, service)
+		skus, ok := services[service]
+		if !ok {
+			return nil, fmt.Errorf("unknown service")
+		}
+		return skus, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		skus, err := c.Query(ComputeEngineService, Query{ResourceGroup: "N1Standard"})
+		if err != nil || !reflect.DeepEqual(skus, []*billingpb.Sku{core}) {
+			t.Errorf("catalog.Query(ComputeEngineService) = %+v, %+v; want %+v", skus, err, []*billingpb.Sku{core})
+		}
+	}
+	if _, err := c.ComputeEngine(); err != nil {
+		t.Errorf("catalog.ComputeEngine() returned error %+v", err)
+	}
+
+	// The Compute Engine SKUs are fetched once, while Cloud SQL is never used.
+	if expected := []string{ComputeEngineService}; !reflect.DeepEqual(fetched, expected) {
+		t.Errorf("catalog fetched services %+v; want %+v", fetched, expected)
+	}
+
+	if _, err := c.Query(ComputeEngineService, Query{ResourceGroup: "CPU"}); err == nil {
+		t.Errorf("catalog.Query(ComputeEngineService, CPU) returned no error")
+	}
+	if _, err := c.CloudStorage(); err == nil {
+		t.Errorf("catalog.CloudStorage() returned no error for a service failing to load")
+	}
+}
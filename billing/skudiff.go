@@ -0,0 +1,139 @@
+package billing
+
+import (
+	"reflect"
+	"sort"
+
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+// ComputeEngineResourceGroups are the resource groups of the Compute Engine SKUs that ComputeEngineCatalog
+// prices the cores and RAM of the compute instances and the disks with.
+var ComputeEngineResourceGroups = []string{"CPU", "RAM", "N1Standard", "PDStandard", "SSD", "LocalSSD"}
+
+// The kinds of SKU changes between two catalogs.
+const (
+	SKUAdded     = "added"
+	SKURemoved   = "removed"
+	SKUTierRates = "tier_rates"
+	SKURegions   = "regions"
+)
+
+// TierRate is a tier rate of the current pricing of a SKU: its unit price from the start usage amount.
+type TierRate struct {
+	StartUsageAmount float64 `json:"start_usage_amount"`
+	UnitPrice        float64 `json:"unit_price"`
+	Currency         string  `json:"currency"`
+}
+
+// SKUState is the pricing of a SKU in a catalog: its service regions, usage unit and tier rates.
+type SKUState struct {
+	Regions   []string   `json:"regions"`
+	UsageUnit string     `json:"usage_unit"`
+	TierRates []TierRate `json:"tier_rates"`
+}
+
+// SKUChange is a change of a SKU between two catalogs: added, removed or with changed tier rates or regions.
+// Before is null for added SKUs and After for removed ones.
+type SKUChange struct {
+	Change        string    `json:"change"`
+	SKUID         string    `json:"sku_id"`
+	Description   string    `json:"description"`
+	ResourceGroup string    `json:"resource_group"`
+	UsageType     string    `json:"usage_type"`
+	Before        *SKUState `json:"before"`
+	After         *SKUState `json:"after"`
+}
+
+// skuState returns the pricing of the SKU, with the tier rates of its current pricing information.
+func skuState(sku *billingpb.Sku) *SKUState {
+	s := &SKUState{Regions: append([]string{}, sku.ServiceRegions...), TierRates: []TierRate{}}
+	sort.Strings(s.Regions)
+	if len(sku.PricingInfo) == 0 || sku.PricingInfo[0].PricingExpression == nil {
+		return s
+	}
+
+	p := sku.PricingInfo[0].PricingExpression
+	s.UsageUnit = p.UsageUnitDescription
+	for _, tr := range p.TieredRates {
+		rate := TierRate{StartUsageAmount: tr.StartUsageAmount}
+		if tr.UnitPrice != nil {
+			rate.UnitPrice = float64(tr.UnitPrice.Units) + float64(tr.UnitPrice.Nanos)/nano
+			rate.Currency = tr.UnitPrice.CurrencyCode
+		}
+		s.TierRates = append(s.TierRates, rate)
+	}
+	return s
+}
+
+// skusByID returns the SKUs of the given resource groups (all if none) by ID.
+func skusByID(skus []*billingpb.Sku, groups []string) map[string]*billingpb.Sku {
+	m := map[string]*billingpb.Sku{}
+	for _, sku := range skus {
+		if len(groups) > 0 && (sku.Category == nil || !contains(groups, sku.Category.ResourceGroup)) {
+			continue
+		}
+		m[sku.SkuId] = sku
+	}
+	return m
+}
+
+func contains(l []string, s string) bool {
+	for _, x := range l {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffSKUs returns the changes of the SKUs of the given resource groups (all if none) from the before catalog
+// to the after one, sorted by resource group, description and SKU ID. A SKU whose tier rates and regions
+// both changed has a change of each kind.
+func DiffSKUs(before, after []*billingpb.Sku, groups []string) []SKUChange {
+	b, a := skusByID(before, groups), skusByID(after, groups)
+
+	changes := []SKUChange{}
+	add := func(kind string, sku *billingpb.Sku, before, after *SKUState) {
+		c := SKUChange{Change: kind, SKUID: sku.SkuId, Description: sku.Description, Before: before, After: after}
+		if sku.Category != nil {
+			c.ResourceGroup, c.UsageType = sku.Category.ResourceGroup, sku.Category.UsageType
+		}
+		changes = append(changes, c)
+	}
+
+	for id, sku := range b {
+		if _, ok := a[id]; !ok {
+			add(SKURemoved, sku, skuState(sku), nil)
+		}
+	}
+	for id, sku := range a {
+		old, ok := b[id]
+		if !ok {
+			add(SKUAdded, sku, nil, skuState(sku))
+			continue
+		}
+		s1, s2 := skuState(old), skuState(sku)
+		if s1.UsageUnit != s2.UsageUnit || !reflect.DeepEqual(s1.TierRates, s2.TierRates) {
+			add(SKUTierRates, sku, s1, s2)
+		}
+		if !reflect.DeepEqual(s1.Regions, s2.Regions) {
+			add(SKURegions, sku, s1, s2)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		x, y := changes[i], changes[j]
+		if x.ResourceGroup != y.ResourceGroup {
+			return x.ResourceGroup < y.ResourceGroup
+		}
+		if x.Description != y.Description {
+			return x.Description < y.Description
+		}
+		if x.SKUID != y.SKUID {
+			return x.SKUID < y.SKUID
+		}
+		return x.Change < y.Change
+	})
+	return changes
+}
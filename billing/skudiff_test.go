@@ -0,0 +1,69 @@
+package billing
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+func TestDiffSKUs(t *testing.T) {
+	skus, err := readSKUs()
+	if err != nil {
+		t.Fatal("Failed to read SKU JSON files")
+	}
+	clone := func(sku *billingpb.Sku, change func(sku *billingpb.Sku)) *billingpb.Sku {
+		c := proto.Clone(sku).(*billingpb.Sku)
+		change(c)
+		return c
+	}
+	// skus[0] and skus[9] are N1Standard RAM SKUs, skus[3] a Windows licensing fee outside the Compute Engine groups.
+	repriced := clone(skus[0], func(sku *billingpb.Sku) {
+		sku.PricingInfo[0].PricingExpression.TieredRates[0].UnitPrice.Nanos = 6000000
+	})
+	moved := clone(skus[0], func(sku *billingpb.Sku) { sku.ServiceRegions = []string{"europe-west6"} })
+	both := clone(skus[0], func(sku *billingpb.Sku) {
+		sku.ServiceRegions = []string{"europe-west6"}
+		sku.PricingInfo[0].PricingExpression.TieredRates[0].UnitPrice.Nanos = 6000000
+	})
+	license := clone(skus[3], func(sku *billingpb.Sku) { sku.ServiceRegions = nil })
+
+	state := func(sku *billingpb.Sku) *SKUState { return skuState(sku) }
+	change := func(kind string, sku *billingpb.Sku, before, after *SKUState) SKUChange {
+		return SKUChange{Change: kind, SKUID: sku.SkuId, Description: sku.Description,
+			ResourceGroup: sku.Category.ResourceGroup, UsageType: sku.Category.UsageType, Before: before, After: after}
+	}
+
+	tests := []struct {
+		name     string
+		before   []*billingpb.Sku
+		after    []*billingpb.Sku
+		groups   []string
+		expected []SKUChange
+	}{
+		{"unchanged", skus, skus, ComputeEngineResourceGroups, []SKUChange{}},
+		{"added", []*billingpb.Sku{skus[9]}, []*billingpb.Sku{skus[0], skus[9]}, ComputeEngineResourceGroups,
+			[]SKUChange{change(SKUAdded, skus[0], nil, state(skus[0]))}},
+		{"removed", []*billingpb.Sku{skus[0], skus[9]}, []*billingpb.Sku{skus[9]}, ComputeEngineResourceGroups,
+			[]SKUChange{change(SKURemoved, skus[0], state(skus[0]), nil)}},
+		{"tier_rates", []*billingpb.Sku{skus[0]}, []*billingpb.Sku{repriced}, ComputeEngineResourceGroups,
+			[]SKUChange{change(SKUTierRates, skus[0], state(skus[0]), state(repriced))}},
+		{"regions", []*billingpb.Sku{skus[0]}, []*billingpb.Sku{moved}, ComputeEngineResourceGroups,
+			[]SKUChange{change(SKURegions, skus[0], state(skus[0]), state(moved))}},
+		{"tier_rates_and_regions", []*billingpb.Sku{skus[0]}, []*billingpb.Sku{both}, ComputeEngineResourceGroups,
+			[]SKUChange{change(SKURegions, skus[0], state(skus[0]), state(both)), change(SKUTierRates, skus[0], state(skus[0]), state(both))}},
+		{"other_group", []*billingpb.Sku{skus[3]}, []*billingpb.Sku{license}, ComputeEngineResourceGroups, []SKUChange{}},
+		{"all_groups", []*billingpb.Sku{skus[3]}, []*billingpb.Sku{license}, nil,
+			[]SKUChange{change(SKURegions, skus[3], state(skus[3]), state(license))}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := DiffSKUs(test.before, test.after, test.groups)
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("DiffSKUs() = %+v, want %+v", actual, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package billing
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+// SnapshotDate is the layout of the snapshot dates, which name the snapshot directories of a SnapshotStore.
+const SnapshotDate = "2006-01-02"
+
+// Services are the IDs of the billing services whose SKUs are used for cost estimation.
+var Services = []string{ComputeEngineService, CloudSQLService, CloudStorageService, KubernetesEngineService, NetworkingService}
+
+// SnapshotStore is a local directory of dated snapshots of the billing catalog. A snapshot holds the SKUs of
+// the services loaded on its date, in one file per currency and service in the json format of the ListSkus
+// response of the billing API, e.g. DIR/2021-03-01/USD/6F81-5844-456A.json.
+type SnapshotStore struct {
+	dir string
+}
+
+// NewSnapshotStore returns the snapshot store in the given directory, created on the first saved snapshot.
+func NewSnapshotStore(dir string) *SnapshotStore {
+	return &SnapshotStore{dir: dir}
+}
+
+func (s *SnapshotStore) path(date time.Time, currency, service string) string {
+	return filepath.Join(s.dir, date.Format(SnapshotDate), currency, strings.TrimPrefix(service, "services/")+".json")
+}
+
+// Save saves the SKUs of the service with prices in the given currency to the snapshot of the date,
+// replacing those saved earlier on the same date.
+func (s *SnapshotStore) Save(date time.Time, currency, service string, skus []*billingpb.Sku) error {
+	path := s.path(date, currency, service)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	m := jsonpb.Marshaler{Indent: "  "}
+	if err := m.Marshal(&b, &billingpb.ListSkusResponse{Skus: skus}); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b.Bytes(), 0644)
+}
+
+// Load returns the SKUs of the service with prices in the given currency saved in the snapshot of the date.
+func (s *SnapshotStore) Load(date time.Time, currency, service string) ([]*billingpb.Sku, error) {
+	skus, err := ReadSKUsFile(s.path(date, currency, service))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("snapshot %s has no %s SKUs of %s", date.Format(SnapshotDate), currency, service)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %s: %w", date.Format(SnapshotDate), err)
+	}
+	return skus, nil
+}
+
+// ReadSKUsFile returns the SKUs of the file in the json format of the ListSkus response of the billing API,
+// e.g. a snapshot file or an export of the API.
+func ReadSKUsFile(path string) ([]*billingpb.Sku, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp billingpb.ListSkusResponse
+	if err := jsonpb.Unmarshal(bytes.NewReader(data), &resp); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return resp.Skus, nil
+}
+
+// Dates returns the dates of the snapshots with prices in the given currency, in ascending order.
+func (s *SnapshotStore) Dates(currency string) ([]time.Time, error) {
+	entries, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var dates []time.Time
+	for _, e := range entries {
+		date, err := time.Parse(SnapshotDate, e.Name())
+		if err != nil || !e.IsDir() {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(s.dir, e.Name(), currency)); err == nil && info.IsDir() {
+			dates = append(dates, date)
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates, nil
+}
+
+// Nearest returns the date of the latest snapshot with prices in the given currency on or before the given date.
+func (s *SnapshotStore) Nearest(date time.Time, currency string) (time.Time, error) {
+	dates, err := s.Dates(currency)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for i := len(dates) - 1; i >= 0; i-- {
+		if !dates[i].After(date) {
+			return dates[i], nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("no snapshot of the %s catalog on or before %s in %s", currency, date.Format(SnapshotDate), s.dir)
+}
@@ -0,0 +1,125 @@
+package billing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+func date(s string) time.Time {
+	d, _ := time.Parse(SnapshotDate, s)
+	return d
+}
+
+func TestSnapshotStore(t *testing.T) {
+	skus, err := readSKUs()
+	if err != nil {
+		t.Fatal("Failed to read SKU JSON files")
+	}
+
+	store := NewSnapshotStore(t.TempDir())
+	for _, d := range []string{"2021-01-01", "2021-03-01"} {
+		if err := store.Save(date(d), "USD", ComputeEngineService, skus); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Save(date("2021-02-01"), "EUR", ComputeEngineService, skus[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := store.Load(date("2021-03-01"), "USD", ComputeEngineService)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != len(skus) {
+		t.Fatalf("Load() returned %d SKUs, want %d", len(loaded), len(skus))
+	}
+	for i := range skus {
+		if !proto.Equal(loaded[i], skus[i]) {
+			t.Errorf("Load() SKU %d = %v, want %v", i, loaded[i], skus[i])
+		}
+	}
+
+	tests := []struct {
+		name     string
+		date     string
+		currency string
+		expected string
+		ok       bool
+	}{
+		{"same_day", "2021-03-01", "USD", "2021-03-01", true},
+		{"between", "2021-02-15", "USD", "2021-01-01", true},
+		{"after", "2022-01-01", "USD", "2021-03-01", true},
+		{"before_first", "2020-12-31", "USD", "", false},
+		{"other_currency", "2021-03-01", "EUR", "2021-02-01", true},
+		{"no_currency", "2021-03-01", "JPY", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := store.Nearest(date(test.date), test.currency)
+			if ok := err == nil; ok != test.ok {
+				t.Fatalf("Nearest(%s, %s) error = %v, want ok %v", test.date, test.currency, err, test.ok)
+			}
+			if test.ok && actual.Format(SnapshotDate) != test.expected {
+				t.Errorf("Nearest(%s, %s) = %s, want %s", test.date, test.currency, actual.Format(SnapshotDate), test.expected)
+			}
+		})
+	}
+}
+
+func TestCatalogSnapshot(t *testing.T) {
+	skus, err := readSKUs()
+	if err != nil {
+		t.Fatal("Failed to read SKU JSON files")
+	}
+	store := NewSnapshotStore(t.TempDir())
+
+	// The SKUs loaded by a recording catalog are served by the catalog of the snapshot.
+	fetched := map[string]int{}
+	live := &Catalog{ctx: context.Background(), currency: "USD", services: map[string][]*billingpb.Sku{}}
+	live.fetch = func(_ context.Context, service string) ([]*billingpb.Sku, error) {
+		fetched[service]++
+		if service == "services/FFFF-0000-0000" {
+			return skus[:1], nil
+		}
+		return skus, nil
+	}
+	live.Record(store, date("2021-03-01"))
+	if _, err := live.ServiceSKUs(ComputeEngineService); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := live.ServiceSKUs(CloudSQLService); err != nil {
+		t.Fatal(err)
+	}
+	// Every supported service is saved, once, with the first one loaded.
+	for _, s := range Services {
+		if fetched[s] != 1 {
+			t.Errorf("recording catalog fetched %s %d times, want once", s, fetched[s])
+		}
+	}
+	if _, err := live.ServiceSKUs("services/FFFF-0000-0000"); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err := store.Load(date("2021-03-01"), "USD", "services/FFFF-0000-0000"); err != nil || len(loaded) != 1 {
+		t.Errorf("Load() of a service loaded by a recording catalog = %d SKUs, %v; want 1 SKU", len(loaded), err)
+	}
+
+	c := NewCatalogFromSnapshot(store, date("2021-03-01"), "")
+	if c.Snapshot() != date("2021-03-01") {
+		t.Errorf("Snapshot() = %v, want 2021-03-01", c.Snapshot())
+	}
+	if loaded, err := c.ServiceSKUs(ComputeEngineService); err != nil || len(loaded) != len(skus) {
+		t.Errorf("ServiceSKUs(compute engine) = %d SKUs, %v; want %d SKUs", len(loaded), err, len(skus))
+	}
+	if loaded, err := c.ServiceSKUs(CloudSQLService); err != nil || len(loaded) != len(skus) {
+		t.Errorf("ServiceSKUs(cloud sql) of a snapshot recorded with compute engine = %d SKUs, %v; want %d SKUs",
+			len(loaded), err, len(skus))
+	}
+	if _, err := c.ServiceSKUs("services/EEEE-0000-0000"); err == nil {
+		t.Errorf("ServiceSKUs() of a service missing from the snapshot returned no error")
+	}
+}
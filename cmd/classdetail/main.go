@@ -0,0 +1,151 @@
+// Command classdetail regenerates the data files of the classdetail packages from local exports of the
+// Compute Engine API and shows what changed in them.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cd "github.com/googleinterns/terraform-cost-estimation/resources/classdetail"
+	"github.com/googleinterns/terraform-cost-estimation/resources/classdetail/datagen"
+	"github.com/googleinterns/terraform-cost-estimation/resources/classdetail/instance"
+)
+
+const usage = `Usage: go run ./cmd/classdetail [OPTIONS]
+
+Regenerates machine_types.json, machine_type_zones.json, compute_disk_types.json and compute_images.json
+from json exports of the Compute Engine API: the responses of machineTypes.aggregatedList,
+diskTypes.aggregatedList and images.list saved to local files. A file may also hold an array of response pages.
+Only the files whose exports are given are regenerated. The changes of every file are printed as a diff.
+
+Options:
+`
+
+// paths splits a comma separated list of paths.
+func paths(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// dataFile is a data file to regenerate: the changes of its entries and its new content.
+type dataFile struct {
+	path    string
+	changes []datagen.Change
+	data    []byte
+}
+
+// generate returns the data file with the entries generated from the exports, compared to the current ones.
+func generate(path string, generated, current interface{}, changes func() []datagen.Change) (*dataFile, error) {
+	if err := datagen.ReadFile(path, current); err != nil {
+		return nil, err
+	}
+	data, err := datagen.Marshal(generated)
+	if err != nil {
+		return nil, err
+	}
+	return &dataFile{path: path, changes: changes(), data: data}, nil
+}
+
+func main() {
+	log.SetFlags(0)
+	fs := flag.NewFlagSet("classdetail", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		fs.PrintDefaults()
+	}
+	dir := fs.String("dir", "resources/classdetail", "Write the data files to the given classdetail directory.")
+	machineTypes := fs.String("machine-types", "", "Regenerate machine_types.json and machine_type_zones.json from the given comma separated machineTypes.aggregatedList exports.")
+	diskTypes := fs.String("disk-types", "", "Regenerate compute_disk_types.json from the given comma separated diskTypes.aggregatedList exports.")
+	images := fs.String("images", "", "Regenerate compute_images.json from the given comma separated images.list exports (e.g. one per image project).")
+	check := fs.Bool("check", false, "Only show the changes without writing the files, and exit with status 1 if there are any.")
+	fs.Parse(os.Args[1:])
+	if *machineTypes == "" && *diskTypes == "" && *images == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	files, err := generateAll(*dir, paths(*machineTypes), paths(*diskTypes), paths(*images))
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	changed := false
+	for _, f := range files {
+		fmt.Printf("%s: %d changes\n", f.path, len(f.changes))
+		for _, c := range f.changes {
+			fmt.Println(c)
+		}
+		changed = changed || len(f.changes) > 0
+		if old, err := ioutil.ReadFile(f.path); *check || err == nil && bytes.Equal(old, f.data) {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if err := ioutil.WriteFile(f.path, f.data, 0644); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+	if *check && changed {
+		os.Exit(1)
+	}
+}
+
+// generateAll generates and validates the data files of the given exports, before any file is written.
+func generateAll(dir string, machineTypes, diskTypes, images []string) ([]*dataFile, error) {
+	var files []*dataFile
+	if len(machineTypes) > 0 {
+		generated, zones, err := datagen.MachineTypes(machineTypes...)
+		if err != nil {
+			return nil, err
+		}
+		current := map[string]instance.ComputeInstanceInfo{}
+		f, err := generate(filepath.Join(dir, cd.MachineTypesFile), generated, &current,
+			func() []datagen.Change { return datagen.DiffMachineTypes(current, generated) })
+		if err != nil {
+			return nil, err
+		}
+		currentZones := map[string][]string{}
+		z, err := generate(filepath.Join(dir, cd.MachineZonesFile), zones, &currentZones,
+			func() []datagen.Change { return datagen.DiffMachineTypeZones(currentZones, zones) })
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f, z)
+	}
+	if len(diskTypes) > 0 {
+		generated, err := datagen.DiskTypes(diskTypes...)
+		if err != nil {
+			return nil, err
+		}
+		var current []datagen.DiskType
+		f, err := generate(filepath.Join(dir, cd.DiskTypesFile), generated, &current,
+			func() []datagen.Change { return datagen.DiffDiskTypes(current, generated) })
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	if len(images) > 0 {
+		generated, err := datagen.Images(images...)
+		if err != nil {
+			return nil, err
+		}
+		var current []datagen.Image
+		f, err := generate(filepath.Join(dir, cd.ImagesFile), generated, &current,
+			func() []datagen.Change { return datagen.DiffImages(current, generated) })
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
@@ -0,0 +1,333 @@
+// Command snapshots keeps dated snapshots of the billing catalog in a local directory, compares the prices
+// of a machine type or disk type in two of them and compares two catalogs SKU by SKU.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/googleinterns/terraform-cost-estimation/billing"
+	"github.com/googleinterns/terraform-cost-estimation/pricediff"
+	res "github.com/googleinterns/terraform-cost-estimation/resources"
+	cd "github.com/googleinterns/terraform-cost-estimation/resources/classdetail"
+	"github.com/jedib0t/go-pretty/v6/table"
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+const usage = `Usage: go run ./cmd/snapshots COMMAND [OPTIONS] [ARGS]
+
+Keeps dated snapshots of the billing catalog in a local directory.
+
+Commands:
+  save             Save the SKUs of the billing services priced by the estimator to the snapshot of today.
+  list             List the dates of the snapshots.
+  diff FROM TO     Compare the prices of a machine type or disk type in the snapshots of the given dates
+                   (YYYY-MM-DD), using the latest snapshot on or before each date.
+  compare FROM TO  Compare two catalogs SKU by SKU: added and removed SKUs, changed tier rates and regions.
+                   Each catalog is the snapshot of a date (YYYY-MM-DD), 'live' for the Cloud Billing Catalog API
+                   or the path of a json file in the format of the ListSkus response of the API.
+
+Run go run ./cmd/snapshots COMMAND -h for the options of a command.
+`
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "save":
+		err = save(os.Args[2:])
+	case "list":
+		err = list(os.Args[2:])
+	case "diff":
+		err = diff(os.Args[2:])
+	case "compare":
+		err = compare(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+// storeFlags adds the options of the snapshot store and currency to the flag set.
+func storeFlags(fs *flag.FlagSet) (dir, currency *string) {
+	dir = fs.String("dir", "snapshots", "Keep the snapshots in the given directory.")
+	currency = fs.String("currency", billing.DefaultCurrency, "Use the snapshots with prices in the given ISO 4217 currency.")
+	return
+}
+
+func save(args []string) error {
+	fs := flag.NewFlagSet("save", flag.ExitOnError)
+	dir, currency := storeFlags(fs)
+	fs.Parse(args)
+
+	store := billing.NewSnapshotStore(*dir)
+	today := time.Now()
+	for _, service := range billing.Services {
+		skus, err := billing.GetSKUsInCurrency(context.Background(), service, *currency)
+		if err != nil {
+			return fmt.Errorf("%s: %v", service, err)
+		}
+		if err := store.Save(today, *currency, service, skus); err != nil {
+			return err
+		}
+		log.Printf("Saved %d SKUs of %s to the snapshot of %s.", len(skus), service, today.Format(billing.SnapshotDate))
+	}
+	return nil
+}
+
+func list(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	dir, currency := storeFlags(fs)
+	fs.Parse(args)
+
+	dates, err := billing.NewSnapshotStore(*dir).Dates(*currency)
+	if err != nil {
+		return err
+	}
+	for _, d := range dates {
+		fmt.Println(d.Format(billing.SnapshotDate))
+	}
+	return nil
+}
+
+// diffOut is the json output of the diff command.
+type diffOut struct {
+	Item    string             `json:"item"`
+	Period  string             `json:"period"`
+	From    string             `json:"from"`
+	To      string             `json:"to"`
+	Changes []pricediff.Change `json:"changes"`
+}
+
+func diff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	dir, currency := storeFlags(fs)
+	machineType := fs.String("machine-type", "", "Compare the prices of the given machine type (e.g. n1-standard-2).")
+	diskType := fs.String("disk-type", "", "Compare the prices of the given disk type (e.g. pd-ssd).")
+	zone := fs.String("zone", "us-central1-a", "Price the machine type or disk type in the given zone.")
+	usageType := fs.String("usage-type", "OnDemand", `Price the machine type with the given usage type.
+Can be set to: OnDemand, Preemptible, Commit1Yr, Commit3Yr.`)
+	size := fs.Int64("size", 0, "Price a disk of the given size in GiB, the default size of the disk type if omitted.")
+	period := fs.String("period", "hour", `Show the unit costs over the given period.
+Can be set to: hour, month, year.`)
+	format := fs.String("format", "txt", `Write the comparison in the specified format.
+Can be set to: txt, json.`)
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		return fmt.Errorf("diff takes the FROM and TO dates of the snapshots")
+	}
+
+	p, err := res.NewPeriod(*period, res.Month30d)
+	if err != nil {
+		return err
+	}
+	store := billing.NewSnapshotStore(*dir)
+	var dates [2]time.Time
+	var catalogs [2]*billing.Catalog
+	for i, arg := range fs.Args() {
+		asOf, err := time.Parse(billing.SnapshotDate, arg)
+		if err != nil {
+			return fmt.Errorf("invalid date %q, must be YYYY-MM-DD", arg)
+		}
+		if dates[i], err = store.Nearest(asOf, *currency); err != nil {
+			return err
+		}
+		catalogs[i] = billing.NewCatalogFromSnapshot(store, dates[i], *currency)
+	}
+
+	details, err := cd.NewResourceDetail()
+	if err != nil {
+		return err
+	}
+	item := pricediff.Item{MachineType: *machineType, DiskType: *diskType, Zone: *zone, UsageType: *usageType, SizeGiB: *size}
+	changes, err := pricediff.Diff(details, catalogs[0], catalogs[1], item)
+	if err != nil {
+		return err
+	}
+
+	from, to := dates[0].Format(billing.SnapshotDate), dates[1].Format(billing.SnapshotDate)
+	switch *format {
+	case "txt":
+		fmt.Println(diffTable(item, p, from, to, changes).Render())
+	case "json":
+		out := diffOut{Item: item.String(), Period: p.Name, From: from, To: to, Changes: changes}
+		for i := range out.Changes {
+			out.Changes[i].Before, out.Changes[i].After = p.Cost(changes[i].Before), p.Cost(changes[i].After)
+		}
+		return json.NewEncoder(os.Stdout).Encode(out)
+	default:
+		return fmt.Errorf("unknown format %q, must be txt or json", *format)
+	}
+	return nil
+}
+
+// diffTable returns the table of the unit costs of the item components over the period in the two snapshots.
+func diffTable(item pricediff.Item, p res.Period, from, to string, changes []pricediff.Change) *table.Table {
+	t := &table.Table{}
+	t.SetTitle(fmt.Sprintf("Prices of %s in the catalog snapshots of %s and %s", item, from, to))
+	t.AppendHeader(table.Row{"Component", "Usage unit", "Units", "Unit cost " + from, "Unit cost " + to, "Change", "Change (%)", "Cost change"})
+	total := 0.0
+	for _, c := range changes {
+		unit := p.Unit(c.Currency)
+		t.AppendRow(table.Row{c.Component, c.UsageUnit, fmt.Sprintf("%.2f", c.Units),
+			fmt.Sprintf("%.6f %s", p.Cost(c.Before), unit), fmt.Sprintf("%.6f %s", p.Cost(c.After), unit),
+			fmt.Sprintf("%.6f %s", p.Cost(c.After-c.Before), unit), fmt.Sprintf("%+.2f%%", c.Percent()),
+			fmt.Sprintf("%.6f %s", p.Cost(c.Delta()), unit)})
+		total += c.Delta()
+	}
+	if len(changes) > 0 {
+		t.AppendSeparator()
+		t.AppendRow(table.Row{"Total", "", "", "", "", "", "", fmt.Sprintf("%.6f %s", p.Cost(total), p.Unit(changes[0].Currency))})
+	}
+	t.SetStyle(table.StyleLight)
+	return t
+}
+
+// services are the IDs of the billing services by catalog name.
+var services = map[string]string{
+	billing.ComputeEngine:    billing.ComputeEngineService,
+	billing.CloudSQL:         billing.CloudSQLService,
+	billing.CloudStorage:     billing.CloudStorageService,
+	billing.KubernetesEngine: billing.KubernetesEngineService,
+	billing.Network:          billing.NetworkingService,
+}
+
+// loadSKUs returns the SKUs of the service in the catalog given by the source: the latest snapshot on or before
+// a date, 'live' for the billing API or a json file. It also returns the name of the catalog.
+func loadSKUs(store *billing.SnapshotStore, source, currency, service string) ([]*billingpb.Sku, string, error) {
+	if source == "live" {
+		skus, err := billing.GetSKUsInCurrency(context.Background(), service, currency)
+		return skus, "live", err
+	}
+	if asOf, err := time.Parse(billing.SnapshotDate, source); err == nil {
+		date, err := store.Nearest(asOf, currency)
+		if err != nil {
+			return nil, "", err
+		}
+		skus, err := store.Load(date, currency, service)
+		return skus, date.Format(billing.SnapshotDate), err
+	}
+	skus, err := billing.ReadSKUsFile(source)
+	return skus, source, err
+}
+
+// compareOut is the json output of the compare command.
+type compareOut struct {
+	Service string              `json:"service"`
+	From    string              `json:"from"`
+	To      string              `json:"to"`
+	Groups  []string            `json:"resource_groups"`
+	Changes []billing.SKUChange `json:"changes"`
+}
+
+func compare(args []string) error {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	dir, currency := storeFlags(fs)
+	service := fs.String("service", billing.ComputeEngine, `Compare the SKUs of the given billing service.
+Can be set to: compute-engine, cloud-sql, cloud-storage, kubernetes-engine, network.`)
+	groups := fs.String("groups", "", `Compare the SKUs of the given resource groups, delimited by ','. 'all' compares every SKU.
+If omitted, the resource groups ComputeEngineCatalog prices instances and disks with for compute-engine, all the others.`)
+	format := fs.String("format", "txt", `Write the changes in the specified format.
+Can be set to: txt, json.`)
+	exitCode := fs.Bool("exit-code", false, "Exit with status 1 if any SKU changed, e.g. to raise alerts.")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		return fmt.Errorf("compare takes the FROM and TO catalogs")
+	}
+
+	id, ok := services[*service]
+	if !ok {
+		return fmt.Errorf("unknown service %q", *service)
+	}
+	var filter []string
+	switch {
+	case *groups == "" && *service == billing.ComputeEngine:
+		filter = billing.ComputeEngineResourceGroups
+	case *groups != "" && *groups != "all":
+		filter = strings.Split(*groups, ",")
+	}
+
+	store := billing.NewSnapshotStore(*dir)
+	before, from, err := loadSKUs(store, fs.Arg(0), *currency, id)
+	if err != nil {
+		return err
+	}
+	after, to, err := loadSKUs(store, fs.Arg(1), *currency, id)
+	if err != nil {
+		return err
+	}
+	changes := billing.DiffSKUs(before, after, filter)
+
+	switch *format {
+	case "txt":
+		fmt.Println(compareTable(*service, from, to, changes).Render())
+	case "json":
+		out := compareOut{Service: *service, From: from, To: to, Groups: filter, Changes: changes}
+		if out.Groups == nil {
+			out.Groups = []string{}
+		}
+		if err := json.NewEncoder(os.Stdout).Encode(out); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown format %q, must be txt or json", *format)
+	}
+	if *exitCode && len(changes) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// rates returns the tier rates of the SKU pricing, e.g. "0.040000 USD per hour; 0.030000 USD per hour from 100".
+func rates(s *billing.SKUState) string {
+	var l []string
+	for _, r := range s.TierRates {
+		rate := fmt.Sprintf("%.6f %s per %s", r.UnitPrice, r.Currency, s.UsageUnit)
+		if r.StartUsageAmount > 0 {
+			rate += fmt.Sprintf(" from %g", r.StartUsageAmount)
+		}
+		l = append(l, rate)
+	}
+	return strings.Join(l, "; ")
+}
+
+// skuCell returns the description of the SKU pricing shown for the change.
+func skuCell(change string, s *billing.SKUState) string {
+	switch {
+	case s == nil:
+		return ""
+	case change == billing.SKUTierRates:
+		return rates(s)
+	case change == billing.SKURegions:
+		return strings.Join(s.Regions, ", ")
+	}
+	return rates(s) + "\nin " + strings.Join(s.Regions, ", ")
+}
+
+// compareTable returns the table of the SKU changes between the two catalogs.
+func compareTable(service, from, to string, changes []billing.SKUChange) *table.Table {
+	t := &table.Table{}
+	t.SetTitle(fmt.Sprintf("%d SKU changes of %s from %s to %s", len(changes), service, from, to))
+	t.AppendHeader(table.Row{"Change", "SKU", "Description", "Resource group", "Usage type", "Before", "After"})
+	for _, c := range changes {
+		t.AppendRow(table.Row{c.Change, c.SKUID, c.Description, c.ResourceGroup, c.UsageType,
+			skuCell(c.Change, c.Before), skuCell(c.Change, c.After)})
+	}
+	t.SetStyle(table.StyleLight)
+	t.Style().Options.SeparateRows = true
+	return t
+}
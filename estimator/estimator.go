@@ -0,0 +1,347 @@
+// Package estimator estimates the cost changes of Terraform plans in-process, so that other programs can embed
+// the estimation without going through the command line. An Estimator decodes the resource changes of a plan,
+// prices them with a billing catalog and returns a report, which the io package renders in every output format:
+//
+//	e, err := estimator.New(ctx, estimator.WithPeriod(resources.Year))
+//	...
+//	report, err := e.EstimateReader(r)
+//	...
+//	err = io.GenerateJsonV2Out(w, report)
+package estimator
+
+import (
+	"context"
+	goio "io"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/googleinterns/terraform-cost-estimation/billing"
+	"github.com/googleinterns/terraform-cost-estimation/io"
+	"github.com/googleinterns/terraform-cost-estimation/jsdecode"
+	"github.com/googleinterns/terraform-cost-estimation/resources"
+	cd "github.com/googleinterns/terraform-cost-estimation/resources/classdetail"
+	"github.com/googleinterns/terraform-cost-estimation/usage"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// Estimator prices the resource changes of Terraform plans. It is safe for concurrent use,
+// the catalog and the class details being read-only once loaded.
+type Estimator struct {
+	catalog  *billing.Catalog
+	currency string
+	details  *cd.ResourceDetail
+	usage    *usage.Assumptions
+	period   resources.Period
+	month    resources.Period
+	workers  int
+	explain  bool
+
+	snapshots *billing.SnapshotStore
+	asOf      time.Time
+	record    bool
+}
+
+// Option configures an Estimator.
+type Option func(e *Estimator)
+
+// WithCatalog prices the resources with the given catalog (e.g. billing.NewCatalogFromSKUs for offline estimates)
+// instead of the Cloud Billing Catalog API. The currency option is then ignored.
+func WithCatalog(catalog *billing.Catalog) Option {
+	return func(e *Estimator) {
+		e.catalog = catalog
+	}
+}
+
+// WithSnapshot prices the resources with the latest catalog snapshot of the store on or before the given date,
+// in the currency of the WithCurrency option, instead of the current prices of the Cloud Billing Catalog API.
+// The date of the snapshot used is given by the reports.
+func WithSnapshot(store *billing.SnapshotStore, asOf time.Time) Option {
+	return func(e *Estimator) {
+		e.snapshots, e.asOf, e.record = store, asOf, false
+	}
+}
+
+// WithRecording saves the SKUs of all the supported services from the Cloud Billing Catalog API to the snapshot
+// of the current date in the store, once the first one is loaded, so that later estimates of any plan can be run
+// as of that date with WithSnapshot.
+func WithRecording(store *billing.SnapshotStore) Option {
+	return func(e *Estimator) {
+		e.snapshots, e.asOf, e.record = store, time.Time{}, true
+	}
+}
+
+// WithCurrency prices the resources in the given ISO 4217 currency, converted by the Cloud Billing Catalog API.
+// It defaults to billing.DefaultCurrency.
+func WithCurrency(currency string) Option {
+	return func(e *Estimator) {
+		e.currency = currency
+	}
+}
+
+// WithClassDetails uses the given machine type, disk type and image details instead of those embedded in the binary,
+// e.g. loaded with classdetail.LoadResourceDetail from a data directory.
+func WithClassDetails(details *cd.ResourceDetail) Option {
+	return func(e *Estimator) {
+		e.details = details
+	}
+}
+
+// WithPeriod reports the costs over the given period. It defaults to resources.Hour.
+func WithPeriod(p resources.Period) Option {
+	return func(e *Estimator) {
+		e.period = p
+	}
+}
+
+// WithMonth uses the given month period for the monthly costs, over which the catalog prices charged per month
+// are also spread (see billing.Catalog.SetMonthHours). It defaults to a month of 30 days.
+func WithMonth(month resources.Period) Option {
+	return func(e *Estimator) {
+		e.month = month
+	}
+}
+
+// WithUsage prices the resources priced by usage with the given usage assumptions.
+// They are estimated with zero usage otherwise.
+func WithUsage(assumptions *usage.Assumptions) Option {
+	return func(e *Estimator) {
+		e.usage = assumptions
+	}
+}
+
+// WithWorkers decodes the plans and prices their resources with at most the given number of workers.
+// It defaults to the number of CPUs.
+func WithWorkers(workers int) Option {
+	return func(e *Estimator) {
+		e.workers = workers
+	}
+}
+
+// WithExplain records the SKU matched for each component, shown in the explain section of the outputs.
+// It is set on the catalog, including one given with WithCatalog.
+func WithExplain(explain bool) Option {
+	return func(e *Estimator) {
+		e.explain = explain
+	}
+}
+
+// New returns an Estimator configured with the given options. Unless a catalog is given, the SKUs are loaded
+// from the Cloud Billing Catalog API with the given context on first use, one service at a time.
+func New(ctx context.Context, opts ...Option) (*Estimator, error) {
+	month, err := resources.Month(resources.Month30d)
+	if err != nil {
+		return nil, err
+	}
+	e := &Estimator{currency: billing.DefaultCurrency, period: resources.Hour, month: month, workers: runtime.NumCPU()}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	if e.details == nil {
+		if e.details, err = cd.NewResourceDetail(); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case e.catalog != nil:
+	case e.snapshots != nil && !e.record:
+		date, err := e.snapshots.Nearest(e.asOf, e.currency)
+		if err != nil {
+			return nil, err
+		}
+		e.catalog = billing.NewCatalogFromSnapshot(e.snapshots, date, e.currency)
+	default:
+		if e.catalog, err = billing.NewCatalog(ctx, e.currency); err != nil {
+			return nil, err
+		}
+		if e.record {
+			e.catalog.Record(e.snapshots, time.Now())
+		}
+	}
+	if e.explain {
+		e.catalog.SetExplain(true)
+	}
+	e.catalog.SetMonthHours(e.month.Hours)
+	return e, nil
+}
+
+// parallel calls f for every index in [0, n) with at most the given number of concurrent workers
+// and returns when all the calls are done.
+func parallel(n, workers int, f func(i int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				f(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// estimation holds the resources decoded from a plan, the errors they got when priced
+// and the resource changes that could not be decoded.
+type estimation struct {
+	resources []resources.ResourceState
+	errs      []error
+	unpriced  []*resources.ResourceError
+}
+
+// report returns the report of the priced resources, in plan order, adding the others to the unpriced resource changes.
+func (e *Estimator) report(est *estimation) (*io.Report, error) {
+	var states []resources.ResourceState
+	for i, r := range est.resources {
+		if est.errs[i] == nil {
+			states = append(states, r)
+		} else {
+			est.unpriced = append(est.unpriced, resources.NewStateErrors(r, est.errs[i])...)
+		}
+	}
+	r, err := io.NewReport(states, est.unpriced, e.period, e.month)
+	if err != nil {
+		return nil, err
+	}
+	if date := e.catalog.Snapshot(); !date.IsZero() {
+		r.Snapshot = date.Format(billing.SnapshotDate)
+	}
+	r.DataVersion = e.details.Version()
+	return r, nil
+}
+
+// EstimatePlans returns the reports of the plans, in the same order, or the error each plan got instead.
+// The plans are decoded and all their resources priced by the same pool of workers.
+// Each plan first loads the service catalogs of its own resources, so that a catalog failing to load
+// only fails the plans that need it.
+func (e *Estimator) EstimatePlans(plans ...*tfjson.Plan) ([]*io.Report, []error) {
+	reports := make([]*io.Report, len(plans))
+	errs := make([]error, len(plans))
+	for i, plan := range plans {
+		errs[i] = e.catalog.Preload(jsdecode.CatalogDependencies(plan)...)
+	}
+
+	estimations := make([]*estimation, len(plans))
+	parallel(len(plans), e.workers, func(i int) {
+		est := &estimation{}
+		estimations[i] = est
+		if errs[i] != nil {
+			return
+		}
+		est.resources, est.unpriced = jsdecode.GetResources(e.details, e.usage, plans[i])
+		est.errs = make([]error, len(est.resources))
+	})
+
+	type job struct{ plan, resource int }
+	var jobs []job
+	for i, est := range estimations {
+		for j := range est.resources {
+			jobs = append(jobs, job{i, j})
+		}
+	}
+	parallel(len(jobs), e.workers, func(i int) {
+		est := estimations[jobs[i].plan]
+		est.errs[jobs[i].resource] = est.resources[jobs[i].resource].CompletePricingInfo(e.catalog)
+	})
+
+	for i, est := range estimations {
+		if errs[i] == nil {
+			reports[i], errs[i] = e.report(est)
+		}
+	}
+	return reports, errs
+}
+
+// EstimatePlan returns the report of the plan.
+func (e *Estimator) EstimatePlan(plan *tfjson.Plan) (*io.Report, error) {
+	reports, errs := e.EstimatePlans(plan)
+	return reports[0], errs[0]
+}
+
+// EstimateReader returns the report of the plan read in the json format of terraform show -json.
+func (e *Estimator) EstimateReader(r goio.Reader) (*io.Report, error) {
+	plan, err := jsdecode.ExtractPlanStruct(r)
+	if err != nil {
+		return nil, err
+	}
+	return e.EstimatePlan(plan)
+}
+
+// EstimateFiles returns the reports of the plan files, in the same order, or the error each file got instead.
+// The files are read concurrently and estimated together as with EstimatePlans.
+func (e *Estimator) EstimateFiles(paths ...string) ([]*io.Report, []error) {
+	plans := make([]*tfjson.Plan, len(paths))
+	errs := make([]error, len(paths))
+	parallel(len(paths), e.workers, func(i int) {
+		plans[i], errs[i] = io.GetPlan(paths[i])
+	})
+
+	var read []*tfjson.Plan
+	for i, plan := range plans {
+		if errs[i] == nil {
+			read = append(read, plan)
+		}
+	}
+	estimated, estimateErrs := e.EstimatePlans(read...)
+
+	reports := make([]*io.Report, len(paths))
+	for i := range paths {
+		if errs[i] == nil {
+			reports[i], errs[i] = estimated[0], estimateErrs[0]
+			estimated, estimateErrs = estimated[1:], estimateErrs[1:]
+		}
+	}
+	return reports, errs
+}
+
+// statePlan returns the plan creating the managed resources of the state, in module order.
+// They are no-op changes from nothing, so that their cost change is their cost.
+func statePlan(state *tfjson.State) *tfjson.Plan {
+	plan := &tfjson.Plan{}
+	if state.Values == nil {
+		return plan
+	}
+
+	var walk func(m *tfjson.StateModule)
+	walk = func(m *tfjson.StateModule) {
+		if m == nil {
+			return
+		}
+		for _, r := range m.Resources {
+			if r.Mode != tfjson.ManagedResourceMode {
+				continue
+			}
+			plan.ResourceChanges = append(plan.ResourceChanges, &tfjson.ResourceChange{
+				Address: r.Address,
+				Mode:    r.Mode,
+				Type:    r.Type,
+				Name:    r.Name,
+				Index:   r.Index,
+				Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionNoop}, After: r.AttributeValues},
+			})
+		}
+		for _, child := range m.ChildModules {
+			walk(child)
+		}
+	}
+	walk(state.Values.RootModule)
+	return plan
+}
+
+// EstimateState returns the report of the managed resources of the state (terraform show -json without a plan).
+// They are reported as no-op changes from nothing, so that the cost change is the cost of the resources.
+func (e *Estimator) EstimateState(state *tfjson.State) (*io.Report, error) {
+	return e.EstimatePlan(statePlan(state))
+}
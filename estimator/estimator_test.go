@@ -0,0 +1,224 @@
+package estimator
+
+import (
+	"context"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googleinterns/terraform-cost-estimation/billing"
+	"github.com/googleinterns/terraform-cost-estimation/jsdecode"
+	"github.com/googleinterns/terraform-cost-estimation/registry"
+	"github.com/googleinterns/terraform-cost-estimation/resources"
+	tfjson "github.com/hashicorp/terraform-json"
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+	"google.golang.org/genproto/googleapis/type/money"
+)
+
+// testSKU builds a Compute Engine SKU of us-central1 with one tier rate of the given price in nanos.
+func testSKU(description, group, unit string, nanos int32) *billingpb.Sku {
+	return &billingpb.Sku{
+		Description:    description,
+		Category:       &billingpb.Category{ResourceFamily: "Compute", ResourceGroup: group, UsageType: "OnDemand"},
+		ServiceRegions: []string{"us-central1"},
+		PricingInfo: []*billingpb.PricingInfo{{PricingExpression: &billingpb.PricingExpression{
+			UsageUnitDescription: unit,
+			TieredRates:          []*billingpb.PricingExpression_TierRate{{UnitPrice: &money.Money{CurrencyCode: "USD", Nanos: nanos}}},
+		}}},
+	}
+}
+
+func testEstimator(t *testing.T) *Estimator {
+	catalog := billing.NewCatalogFromSKUs(map[string][]*billingpb.Sku{
+		billing.ComputeEngineService: {
+			testSKU("N1 Predefined Instance Core running in Americas", "N1Standard", "hour", 40000000),
+			testSKU("N1 Predefined Instance Ram running in Americas", "N1Standard", "gibibyte hour", 4000000),
+		},
+	})
+	e, err := New(context.Background(), WithCatalog(catalog), WithPeriod(resources.Year), WithWorkers(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestEstimateReader(t *testing.T) {
+	f, err := os.Open("../testdata/new-compute-instance/tfplan.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	report, err := testEstimator(t).EstimateReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// n1-standard-1: 1 core and 3.75 GiB of RAM.
+	expected := (0.04 + 3.75*0.004) * 8760
+	if math.Abs(report.CostChange()-expected) > 1e-9 || report.Coverage() != 100 || len(report.Unpriced) != 0 {
+		t.Errorf("EstimateReader() = %f, coverage %f, unpriced %v; want %f, 100, none",
+			report.CostChange(), report.Coverage(), report.Unpriced, expected)
+	}
+	if r := report.Resources(); len(r) != 1 || r[0].Kind != "compute_instance" || r[0].Action != "create" ||
+		len(r[0].Addresses) != 1 || r[0].Addresses[0] != "google_compute_instance.default" {
+		t.Errorf("EstimateReader().Resources() = %+v, want the created google_compute_instance.default", r)
+	}
+}
+
+func TestEstimateState(t *testing.T) {
+	instance := func(name, zone string) map[string]interface{} {
+		return map[string]interface{}{"name": name, "machine_type": "n1-standard-1", "zone": zone}
+	}
+	state := &tfjson.State{Values: &tfjson.StateValues{RootModule: &tfjson.StateModule{
+		Resources: []*tfjson.StateResource{
+			{Address: "google_compute_network.net", Mode: tfjson.ManagedResourceMode, Type: "google_compute_network", Name: "net",
+				AttributeValues: map[string]interface{}{}},
+			{Address: "google_redis_instance.cache", Mode: tfjson.ManagedResourceMode, Type: "google_redis_instance", Name: "cache",
+				AttributeValues: map[string]interface{}{}},
+			{Address: "data.google_compute_zones.all", Mode: tfjson.DataResourceMode, Type: "google_compute_zones", Name: "all"},
+		},
+		ChildModules: []*tfjson.StateModule{{
+			Address: "module.vm",
+			Resources: []*tfjson.StateResource{
+				{Address: "module.vm.google_compute_instance.a", Mode: tfjson.ManagedResourceMode, Type: "google_compute_instance",
+					Name: "a", AttributeValues: instance("a", "us-central1-a")},
+				{Address: "module.vm.google_compute_instance.b", Mode: tfjson.ManagedResourceMode, Type: "google_compute_instance",
+					Name: "b", AttributeValues: instance("b", "europe-west1-b")},
+			},
+		}},
+	}}}
+
+	report, err := testEstimator(t).EstimateState(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		address string
+		kind    resources.ErrorKind
+	}{
+		{"google_redis_instance.cache", resources.KindUnsupportedType},
+		{"module.vm.google_compute_instance.b", resources.KindMissingSKU},
+	}
+	if len(report.Unpriced) != len(expected) {
+		t.Fatalf("EstimateState() unpriced = %v, want %d", report.Unpriced, len(expected))
+	}
+	for i, e := range expected {
+		if report.Unpriced[i].Address != e.address || report.Unpriced[i].Kind != e.kind {
+			t.Errorf("EstimateState() unpriced %d = %s %s, want %s %s",
+				i, report.Unpriced[i].Address, report.Unpriced[i].Kind, e.address, e.kind)
+		}
+	}
+
+	r := report.Resources()
+	if len(r) != 1 || r[0].Action != "no-op" || r[0].Before != nil || r[0].After == nil {
+		t.Fatalf("EstimateState().Resources() = %+v, want module.vm.google_compute_instance.a from nothing", r)
+	}
+	if cost := r[0].After.Total() * 8760; math.Abs(report.CostChange()-cost) > 1e-9 || report.Coverage() != 100.0/3 {
+		t.Errorf("EstimateState() = %f, coverage %f; want %f, %f", report.CostChange(), report.Coverage(), cost, 100.0/3)
+	}
+}
+
+func TestEstimateFiles(t *testing.T) {
+	paths := []string{"../testdata/new-compute-instance/tfplan.json", "missing.json", "../testdata/new-compute-instance/tfplan.json"}
+	reports, errs := testEstimator(t).EstimateFiles(paths...)
+
+	for i, ok := range []bool{true, false, true} {
+		if (errs[i] == nil) != ok || (reports[i] != nil) != ok {
+			t.Errorf("EstimateFiles() file %d = %v, %v; want ok %t", i, reports[i], errs[i], ok)
+		}
+	}
+}
+
+func TestEstimatePlansCatalogError(t *testing.T) {
+	// The resources of this type need a service catalog that fails to load.
+	const failing = "test_unavailable_service_resource"
+	if _, ok := registry.Lookup(failing); !ok {
+		registry.MustRegister(&registry.Pricer{
+			Types:    []string{failing},
+			Catalogs: []string{"unavailable-service"},
+			Decode:   func(*registry.Context, interface{}) (interface{}, error) { return nil, nil },
+			NewState: func(before, after interface{}, action string) resources.ResourceState { return nil },
+		})
+	}
+
+	f, err := os.Open("../testdata/new-compute-instance/tfplan.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	instance, err := jsdecode.ExtractPlanStruct(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unavailable := &tfjson.Plan{ResourceChanges: []*tfjson.ResourceChange{{
+		Address: failing + ".a", Mode: tfjson.ManagedResourceMode, Type: failing,
+		Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionCreate}, After: map[string]interface{}{}},
+	}}}
+
+	reports, errs := testEstimator(t).EstimatePlans(instance, unavailable, instance)
+	for i, ok := range []bool{true, false, true} {
+		if (errs[i] == nil) != ok || (reports[i] != nil) != ok {
+			t.Errorf("EstimatePlans() plan %d = %v, %v; want ok %t", i, reports[i], errs[i], ok)
+		}
+	}
+}
+
+func TestEstimateSnapshot(t *testing.T) {
+	store := billing.NewSnapshotStore(t.TempDir())
+	for date, core := range map[string]int32{"2021-01-01": 40000000, "2021-06-01": 50000000} {
+		d, _ := time.Parse(billing.SnapshotDate, date)
+		skus := []*billingpb.Sku{
+			testSKU("N1 Predefined Instance Core running in Americas", "N1Standard", "hour", core),
+			testSKU("N1 Predefined Instance Ram running in Americas", "N1Standard", "gibibyte hour", 4000000),
+		}
+		if err := store.Save(d, "USD", billing.ComputeEngineService, skus); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		asOf     string
+		snapshot string
+		core     float64
+		ok       bool
+	}{
+		{"first", "2021-03-01", "2021-01-01", 0.04, true},
+		{"latest", "2021-06-01", "2021-06-01", 0.05, true},
+		{"before_first", "2020-12-01", "", 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			asOf, _ := time.Parse(billing.SnapshotDate, test.asOf)
+			e, err := New(context.Background(), WithSnapshot(store, asOf))
+			if ok := err == nil; ok != test.ok {
+				t.Fatalf("New(WithSnapshot(%s)) error = %v, want ok %v", test.asOf, err, test.ok)
+			}
+			if !test.ok {
+				return
+			}
+
+			f, err := os.Open("../testdata/new-compute-instance/tfplan.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			report, err := e.EstimateReader(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := test.core + 3.75*0.004; math.Abs(report.CostChange()-expected) > 1e-9 || report.Snapshot != test.snapshot {
+				t.Errorf("EstimateReader() = %f with snapshot %q, want %f with snapshot %q",
+					report.CostChange(), report.Snapshot, expected, test.snapshot)
+			}
+			if !strings.HasPrefix(report.DataVersion, "embedded-") {
+				t.Errorf("EstimateReader() data version = %q, want the embedded data", report.DataVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package io
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/googleinterns/terraform-cost-estimation/io/js"
+	"github.com/googleinterns/terraform-cost-estimation/io/web"
+	"github.com/googleinterns/terraform-cost-estimation/resources"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// PlanReport is the report of a plan file in an aggregate report, labelled with the name of the file.
+type PlanReport struct {
+	Name string
+	*Report
+}
+
+// Aggregate is the combined report of several plan files, e.g. the Terraform workspaces of a release.
+// It gives the grand total of the cost changes of all the plans next to the subtotal of each one.
+type Aggregate struct {
+	// Currency is the ISO 4217 code of the currency all the costs are priced in.
+	Currency string
+	// Period is the reporting period of the costs, Month the one of the html monthly tables.
+	Period resources.Period
+	Month  resources.Period
+	// Plans are the reports of the plan files, in input order.
+	Plans []PlanReport
+	// Snapshot is the date of the catalog snapshot the resources are priced with, empty for the current prices.
+	Snapshot string
+	// DataVersion is the version of the machine type, disk type and image details the resources are priced with.
+	DataVersion string
+}
+
+// NewAggregate returns the aggregate report of the plans, or an error if there are none
+// or they are priced in different currencies, over different periods, with different catalog snapshots
+// or with different versions of the class detail data.
+func NewAggregate(plans []PlanReport) (*Aggregate, error) {
+	if len(plans) == 0 {
+		return nil, fmt.Errorf("no plan reports to aggregate")
+	}
+	first := plans[0]
+	for _, p := range plans[1:] {
+		if p.Currency != first.Currency {
+			return nil, fmt.Errorf("plans are priced in different currencies (%s, %s)", first.Currency, p.Currency)
+		}
+		if p.Period != first.Period || p.Month != first.Month {
+			return nil, fmt.Errorf("plans are reported over different periods (%s, %s)", first.Period.Name, p.Period.Name)
+		}
+		if p.Snapshot != first.Snapshot {
+			return nil, fmt.Errorf("plans are priced with different catalog snapshots (%q, %q)", first.Snapshot, p.Snapshot)
+		}
+		if p.DataVersion != first.DataVersion {
+			return nil, fmt.Errorf("plans are priced with different class detail data (%q, %q)", first.DataVersion, p.DataVersion)
+		}
+	}
+	return &Aggregate{Currency: first.Currency, Period: first.Period, Month: first.Month, Plans: plans, Snapshot: first.Snapshot,
+		DataVersion: first.DataVersion}, nil
+}
+
+// report returns the report of the resources of all the plans, in input order.
+func (a *Aggregate) report() *Report {
+	r := &Report{Currency: a.Currency, Period: a.Period, Month: a.Month, Snapshot: a.Snapshot, DataVersion: a.DataVersion}
+	for _, p := range a.Plans {
+		r.States = append(r.States, p.States...)
+		r.Unpriced = append(r.Unpriced, p.Unpriced...)
+	}
+	return r
+}
+
+// CostChange returns the grand total cost change of all the plans over the reporting period.
+func (a *Aggregate) CostChange() float64 {
+	return a.report().CostChange()
+}
+
+// Coverage returns the percentage of the resource changes of all the plans that were priced.
+func (a *Aggregate) Coverage() float64 {
+	return a.report().Coverage()
+}
+
+// GetPlansTable returns the table with the cost change, coverage and number of priced and unpriced resources
+// of each plan over the reporting period, and their grand total.
+func GetPlansTable(a *Aggregate) *table.Table {
+	t := &table.Table{}
+	unit := a.Period.Unit(a.Currency)
+	t.SetTitle("Plans")
+	t.AppendHeader(table.Row{"Plan", "Resources", "Unpriced", "Coverage", "Delta\n(" + unit + ")"})
+	priced, unpriced := 0, 0
+	for _, p := range a.Plans {
+		n := resources.PricedCount(p.States)
+		priced += n
+		unpriced += len(p.Unpriced)
+		t.AppendRow(table.Row{p.Name, n, len(p.Unpriced), fmt.Sprintf("%.1f%%", p.Coverage()),
+			fmt.Sprintf("%.6f", p.CostChange())})
+	}
+	t.AppendFooter(table.Row{"Total", priced, unpriced, fmt.Sprintf("%.1f%%", a.Coverage()), fmt.Sprintf("%.6f", a.CostChange())})
+	t.SetStyle(table.StyleLight)
+	return t
+}
+
+// GetAggregateSummaryTable returns the table with brief cost changes info about all resources of the plans
+// over the reporting period, each labelled with its plan, with the coverage of all the plans in its caption.
+func GetAggregateSummaryTable(a *Aggregate) *table.Table {
+	t := &table.Table{}
+	autoMerge := table.RowConfig{AutoMerge: true}
+
+	unit := a.Period.Unit(a.Currency)
+	t.SetTitle(fmt.Sprintf("The total cost change for all Plans is %.6f %s.", a.CostChange(), unit))
+	h := "Pricing Information\n(" + unit + ")"
+	t.AppendRow(table.Row{h, h, h, h, h, h}, autoMerge)
+	t.AppendRow(table.Row{"Plan", "Name", "ID", "Type", "Action", "Delta"})
+	for _, p := range a.Plans {
+		for _, s := range p.States {
+			if row, err := s.GetSummaryRow(a.Period); err == nil {
+				t.AppendRow(append(table.Row{p.Name}, row...))
+			} else {
+				log.Printf("Error: %v", err)
+			}
+		}
+	}
+	r := a.report()
+	t.SetCaption("%s", coverageString(r.States, r.Unpriced)+snapshotString(a.Snapshot)+dataVersionString(a.DataVersion))
+	t.SetStyle(table.StyleLight)
+	t.Style().Options.SeparateRows = true
+	return t
+}
+
+// getAggregateUnpricedTable returns the table with the resource changes of the plans that could not be priced and why.
+func getAggregateUnpricedTable(a *Aggregate) *table.Table {
+	t := &table.Table{}
+	t.SetTitle("Unpriced resources")
+	t.AppendHeader(table.Row{"Plan", "Address", "Type", "Reason", "Error"})
+	for _, p := range a.Plans {
+		for _, e := range p.Unpriced {
+			t.AppendRow(table.Row{p.Name, e.Address, e.Type, e.Kind, e.Err.Error()})
+		}
+	}
+	t.SetStyle(table.StyleLight)
+	return t
+}
+
+// OutputAggregate writes the subtotal of each plan and the grand total, the summary of all resources labelled
+// with their plan, the resource changes that could not be priced, the pricing information of the resources
+// of each plan and the SKU matches recorded in explain mode.
+func OutputAggregate(w io.Writer, a *Aggregate) error {
+	r := a.report()
+	var b strings.Builder
+	b.WriteString(GetPlansTable(a).Render() + "\n\n")
+	b.WriteString(GetAggregateSummaryTable(a).Render() + "\n\n")
+	if len(r.Unpriced) > 0 {
+		b.WriteString(getAggregateUnpricedTable(a).Render() + "\n\n")
+	}
+	for _, p := range a.Plans {
+		b.WriteString(fmt.Sprintf("\n List of all Resources of %s:\n\n", p.Name))
+		for _, s := range p.States {
+			t, err := s.ToTable(a.Period)
+			if err == nil {
+				b.WriteString(t.Render() + "\n\n\n")
+			} else {
+				log.Printf("Error: %v", err)
+			}
+		}
+	}
+
+	if matches := explainMatches(r.States); len(matches) > 0 {
+		b.WriteString("\n Explanation of the SKU matches:\n\n")
+		b.WriteString(GetExplainTable(matches).Render() + "\n\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// RenderJsonAggregate returns the version 2 json output of all resources of the plans, with the costs over the
+// reporting period. The resources and unpriced resources are labelled with their "plan" and the subtotal of each
+// plan is listed under "plans", while "cost_change", "periods" and "coverage" are the grand totals.
+func RenderJsonAggregate(a *Aggregate) (string, error) {
+	out := reportV2(a.report())
+	out.Resources = []js.ResourceV2{}
+	out.Unpriced = []js.UnpricedOut{}
+	for _, p := range a.Plans {
+		for _, s := range p.Resources() {
+			r := resourceV2(s, a.Period)
+			r.Plan = p.Name
+			out.Resources = append(out.Resources, r)
+		}
+		for _, u := range unpricedOut(p.Unpriced) {
+			u.Plan = p.Name
+			out.Unpriced = append(out.Unpriced, u)
+		}
+		out.Plans = append(out.Plans, js.PlanV2{
+			Name:       p.Name,
+			CostChange: p.CostChange(),
+			Coverage:   p.Coverage(),
+			Resources:  resources.PricedCount(p.States),
+			Unpriced:   len(p.Unpriced),
+		})
+	}
+
+	jsonString, err := json.Marshal(out)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonString), nil
+}
+
+// GenerateJsonAggregateOut writes the version 2 json output of the aggregate report.
+func GenerateJsonAggregateOut(w io.Writer, a *Aggregate) error {
+	jsonString, err := RenderJsonAggregate(a)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, jsonString)
+	return err
+}
+
+// GenerateAggregateWebPage writes the html output of the aggregate report: the page of GenerateWebPage with the
+// resources of all the plans, which can also be filtered and sorted by plan, and a table with the cost change
+// of the shown resources of each plan and their total.
+func GenerateAggregateWebPage(w io.Writer, a *Aggregate) error {
+	t, err := web.ParseTemplate()
+	if err != nil {
+		return err
+	}
+
+	r := a.report()
+	page := web.Page{
+		Currency:    a.Currency,
+		Coverage:    coverageString(r.States, r.Unpriced),
+		Snapshot:    a.Snapshot,
+		DataVersion: a.DataVersion,
+		Explain:     explainRows(explainMatches(r.States)),
+	}
+	for _, p := range a.Plans {
+		pageResources, err := webResources(p.States, a.Month)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p.Name, err)
+		}
+		for _, res := range pageResources {
+			res.Index, res.Plan = len(page.Resources), p.Name
+			page.Resources = append(page.Resources, res)
+		}
+		for _, u := range unpricedRows(p.Unpriced) {
+			u.Plan = p.Name
+			page.Unpriced = append(page.Unpriced, u)
+		}
+		page.Plans = append(page.Plans, web.PlanRow{Name: p.Name, Resources: resources.PricedCount(p.States), Unpriced: len(p.Unpriced), Coverage: p.Coverage()})
+	}
+	return t.Execute(w, page)
+}
+
+// RenderAggregate writes the aggregate report in the given format to w. The json and json-v2 formats
+// both write the version 2 json output, the csv and tsv formats the rows of all the plans.
+func RenderAggregate(w io.Writer, format string, a *Aggregate) error {
+	switch format {
+	case "txt":
+		return OutputAggregate(w, a)
+	case "json", "json-v2":
+		return GenerateJsonAggregateOut(w, a)
+	case "html":
+		return GenerateAggregateWebPage(w, a)
+	case "csv", "tsv":
+		c := NewCSVWriter(w, csvComma(format))
+		for _, p := range a.Plans {
+			if err := c.Write(p.Name, p.Report); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
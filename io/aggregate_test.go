@@ -0,0 +1,186 @@
+package io
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/googleinterns/terraform-cost-estimation/resources"
+	"github.com/googleinterns/terraform-cost-estimation/resources/classdetail/instance"
+)
+
+// addressReport returns the report of the creation of a global address at the given hourly price.
+func addressReport(t *testing.T, name, currency string, price float64, unpriced []*resources.ResourceError) *Report {
+	month, _ := resources.Month(resources.Month730h)
+	pricing := resources.PricingInfo{UsageUnit: "hour", HourlyUnitPrice: price, CurrencyType: currency}
+	address := &resources.Address{Name: name, Region: "europe-west1", Global: true, NetworkTier: "PREMIUM", UnusedPricing: pricing}
+	state := &resources.AddressState{After: address, Action: "create"}
+	state.AddAddress("google_compute_global_address." + name)
+
+	r, err := NewReport([]resources.ResourceState{state}, unpriced, month, month)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestNewAggregate(t *testing.T) {
+	custom := addressReport(t, "b", "EUR", 2, nil)
+	custom.DataVersion = "embedded-1f0c83a4d2b7+overlay-6be02a9153cd"
+
+	tests := []struct {
+		name  string
+		plans []PlanReport
+		ok    bool
+	}{
+		{"plans", []PlanReport{{"a.json", addressReport(t, "a", "EUR", 1, nil)}, {"b.json", addressReport(t, "b", "EUR", 2, nil)}}, true},
+		{"no_plans", nil, false},
+		{"different_currencies", []PlanReport{{"a.json", addressReport(t, "a", "EUR", 1, nil)}, {"b.json", addressReport(t, "b", "USD", 2, nil)}}, false},
+		{"different_data_versions", []PlanReport{{"a.json", addressReport(t, "a", "EUR", 1, nil)}, {"b.json", custom}}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewAggregate(test.plans)
+			if ok := err == nil; ok != test.ok {
+				t.Errorf("NewAggregate() error = %v, want ok %v", err, test.ok)
+			}
+		})
+	}
+}
+
+func TestRenderJsonAggregate(t *testing.T) {
+	unpriced := []*resources.ResourceError{resources.NewResourceError("google_compute_instance.vm", "google_compute_instance",
+		resources.KindInvalidConfig, instance.ErrUnknownMachineType)}
+	a, err := NewAggregate([]PlanReport{
+		{"a.json", addressReport(t, "a", "EUR", 0.5, nil)},
+		{"b.json", addressReport(t, "b", "EUR", 1, unpriced)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := RenderJsonAggregate(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		CostChange float64 `json:"cost_change"`
+		Coverage   float64 `json:"coverage"`
+		Plans      []struct {
+			Name       string  `json:"name"`
+			CostChange float64 `json:"cost_change"`
+			Unpriced   int     `json:"unpriced_resources"`
+		} `json:"plans"`
+		Resources []struct {
+			Plan string `json:"plan"`
+		} `json:"resources"`
+		Unpriced []struct {
+			Plan string `json:"plan"`
+		} `json:"unpriced_resources"`
+	}
+	if err := json.Unmarshal([]byte(actual), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.CostChange != 1095 {
+		t.Errorf("cost_change = %v, want 1095", out.CostChange)
+	}
+	if want := 200.0 / 3; out.Coverage != want {
+		t.Errorf("coverage = %v, want %v", out.Coverage, want)
+	}
+	var plans, labels []string
+	var subtotals []float64
+	for _, p := range out.Plans {
+		plans = append(plans, p.Name)
+		subtotals = append(subtotals, p.CostChange)
+	}
+	for _, r := range out.Resources {
+		labels = append(labels, r.Plan)
+	}
+	for _, u := range out.Unpriced {
+		labels = append(labels, u.Plan)
+	}
+	if want := []string{"a.json", "b.json"}; !reflect.DeepEqual(plans, want) {
+		t.Errorf("plans = %v, want %v", plans, want)
+	}
+	if want := []float64{365, 730}; !reflect.DeepEqual(subtotals, want) {
+		t.Errorf("plan cost changes = %v, want %v", subtotals, want)
+	}
+	if want := []string{"a.json", "b.json", "b.json"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("resource plans = %v, want %v", labels, want)
+	}
+}
+
+func TestPlanResources(t *testing.T) {
+	grouped := addressReport(t, "a", "EUR", 0.5, nil)
+	grouped.States[0].(*resources.AddressState).AddAddress("google_compute_global_address.a2")
+	a, err := NewAggregate([]PlanReport{{"a.json", grouped}, {"b.json", addressReport(t, "b", "EUR", 1, nil)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := RenderJsonAggregate(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Plans []struct {
+			Resources int `json:"resources"`
+		} `json:"plans"`
+	}
+	if err := json.Unmarshal([]byte(actual), &out); err != nil {
+		t.Fatal(err)
+	}
+	var counts []int
+	for _, p := range out.Plans {
+		counts = append(counts, p.Resources)
+	}
+	if want := []int{2, 1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("plan resources = %v, want %v", counts, want)
+	}
+
+	if table := GetPlansTable(a).Render(); !strings.Contains(table, "│ a.json │         2 │") {
+		t.Errorf("GetPlansTable() = %s, want 2 resources for a.json", table)
+	}
+}
+
+func TestAggregateOutputs(t *testing.T) {
+	plans := []PlanReport{
+		{"a.json", addressReport(t, "a", "EUR", 0.5, nil)},
+		{"b.json", addressReport(t, "b", "EUR", 1, nil)},
+	}
+	for _, p := range plans {
+		p.DataVersion = "embedded-1f0c83a4d2b7"
+	}
+	a, err := NewAggregate(plans)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		format   string
+		contains []string
+	}{
+		{"txt", []string{"The total cost change for all Plans is 1095.000000 EUR/month.", "List of all Resources of b.json", "Coverage: 100.0% of the resource changes are priced.",
+			"Class detail data embedded-1f0c83a4d2b7."}},
+		{"html", []string{`"plan":"b.json"`, `plans: [{"name":"a.json","resources":1,"unpriced":0,"coverage":100}`, `<select name="plan">`, "drawPlans",
+			"Class detail data embedded-1f0c83a4d2b7."}},
+		{"csv", []string{"b.json,google_compute_global_address.b", ",embedded-1f0c83a4d2b7\n"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			var b strings.Builder
+			if err := RenderAggregate(&b, test.format, a); err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range test.contains {
+				if !strings.Contains(b.String(), s) {
+					t.Errorf("RenderAggregate(%s) output does not contain %s", test.format, s)
+				}
+			}
+		})
+	}
+}
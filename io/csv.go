@@ -0,0 +1,124 @@
+package io
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/googleinterns/terraform-cost-estimation/resources"
+)
+
+// csvHeader is the header row of the flat export, one row per billing component of a resource
+// and one per unpriced resource change, with the reason it was not priced. Every row has the version
+// of the class detail data its plan file is priced with.
+var csvHeader = []string{
+	"plan_file", "address", "kind", "name", "action", "component", "usage_unit", "currency", "period",
+	"before_unit_cost", "before_units", "before_cost", "after_unit_cost", "after_units", "after_cost", "cost_change",
+	"unpriced_reason", "error", "data_version",
+}
+
+// CSVWriter writes the flat export of the reports of one or more plan files: one row per billing component,
+// with the before and after costs over the reporting period. The header is written before the first row.
+type CSVWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSVWriter returns a CSVWriter writing to w the fields separated by comma (',' for csv, '\t' for tsv).
+func NewCSVWriter(w io.Writer, comma rune) *CSVWriter {
+	c := csv.NewWriter(w)
+	c.Comma = comma
+	return &CSVWriter{w: c}
+}
+
+// formatFloat formats the number without exponent, so that spreadsheets read it as is.
+func formatFloat(x float64) string {
+	return strconv.FormatFloat(x, 'f', -1, 64)
+}
+
+// csvCosts returns the unit cost, units and cost cells of the component over the period, empty if it does not exist.
+func csvCosts(c *resources.ComponentCost, p resources.Period) []string {
+	if c == nil {
+		return []string{"", "", ""}
+	}
+	return []string{formatFloat(p.Cost(c.UnitCost)), formatFloat(c.Units), formatFloat(p.Cost(c.Total()))}
+}
+
+// csvRows returns the rows of the billing components of the resource change, in the order they first appear.
+func csvRows(planFile, currency string, r resources.StateReport, p resources.Period) (rows [][]string) {
+	name := ""
+	var names []string
+	before := map[string]*resources.ComponentCost{}
+	after := map[string]*resources.ComponentCost{}
+	for _, side := range []struct {
+		r          *resources.ResourceReport
+		components map[string]*resources.ComponentCost
+	}{{r.Before, before}, {r.After, after}} {
+		if side.r == nil {
+			continue
+		}
+		name = side.r.Name
+		for i, c := range side.r.Components {
+			if before[c.Name] == nil && after[c.Name] == nil {
+				names = append(names, c.Name)
+			}
+			side.components[c.Name] = &side.r.Components[i]
+		}
+	}
+
+	for _, n := range names {
+		b, a := before[n], after[n]
+		unit := ""
+		delta := 0.0
+		if b != nil {
+			unit = b.UsageUnit
+			delta -= b.Total()
+		}
+		if a != nil {
+			unit = a.UsageUnit
+			delta += a.Total()
+		}
+
+		row := []string{planFile, strings.Join(r.Addresses, ";"), r.Kind, name, r.Action, n, unit, currency, p.Name}
+		row = append(row, csvCosts(b, p)...)
+		row = append(row, csvCosts(a, p)...)
+		rows = append(rows, append(row, formatFloat(p.Cost(delta)), "", ""))
+	}
+	return
+}
+
+// unpricedRow returns the row of a resource change that could not be priced, with empty costs.
+func unpricedRow(planFile, currency string, e *resources.ResourceError, p resources.Period) []string {
+	row := []string{planFile, e.Address, e.Type, "", "", "", "", currency, p.Name}
+	row = append(row, csvCosts(nil, p)...)
+	row = append(row, csvCosts(nil, p)...)
+	return append(row, "", string(e.Kind), e.Err.Error())
+}
+
+// Write writes the rows of the resources of the report of the plan file, with the costs over the report period,
+// followed by those of the unpriced resource changes.
+// Resource states that don't implement resources.Reporter are left out.
+func (c *CSVWriter) Write(planFile string, r *Report) error {
+	if !c.header {
+		if err := c.w.Write(csvHeader); err != nil {
+			return err
+		}
+		c.header = true
+	}
+
+	for _, s := range r.Resources() {
+		for _, row := range csvRows(planFile, r.Currency, s, r.Period) {
+			if err := c.w.Write(append(row, r.DataVersion)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, e := range r.Unpriced {
+		if err := c.w.Write(append(unpricedRow(planFile, r.Currency, e, r.Period), r.DataVersion)); err != nil {
+			return err
+		}
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
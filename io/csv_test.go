@@ -0,0 +1,67 @@
+package io
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/googleinterns/terraform-cost-estimation/resources"
+)
+
+func TestCSVWriter(t *testing.T) {
+	pricing := func(price float64) resources.PricingInfo {
+		return resources.PricingInfo{UsageUnit: "hour", HourlyUnitPrice: price, CurrencyType: "USD"}
+	}
+	unused := &resources.Address{Name: "ip", Region: "us-central1", NetworkTier: "PREMIUM",
+		UnusedPricing: pricing(0.01), InUsePricing: pricing(0.004)}
+	used := &resources.Address{Name: "ip", Region: "us-central1", NetworkTier: "PREMIUM", InUse: 1,
+		UnusedPricing: pricing(0.01), InUsePricing: pricing(0.004)}
+	global := &resources.Address{Name: "lb", Region: "global", Global: true, NetworkTier: "PREMIUM",
+		UnusedPricing: pricing(0.01)}
+
+	update := &resources.AddressState{Before: unused, After: used, Action: "update"}
+	update.AddAddress("google_compute_address.ip")
+	remove := &resources.AddressState{Before: global, Action: "delete"}
+	remove.AddAddress("google_compute_global_address.lb")
+	network := resources.NewResourceError("google_compute_network.net", "google_compute_network", resources.KindUnsupportedType,
+		fmt.Errorf("%w 'google_compute_network'", resources.ErrUnsupportedType))
+
+	tests := []struct {
+		name     string
+		comma    rune
+		expected string
+	}{
+		{"csv", ',', "" +
+			"plan_file,address,kind,name,action,component,usage_unit,currency,period,before_unit_cost,before_units,before_cost,after_unit_cost,after_units,after_cost,cost_change,unpriced_reason,error,data_version\n" +
+			"a.json,google_compute_address.ip,compute_address,ip,update,Static IP (unused),hour,USD,hour,0.01,1,0.01,0.01,0,0,-0.01,,,embedded-1f0c83a4d2b7\n" +
+			"a.json,google_compute_address.ip,compute_address,ip,update,Static IP (in use),hour,USD,hour,0.004,0,0,0.004,1,0.004,0.004,,,embedded-1f0c83a4d2b7\n" +
+			"b.json,google_compute_global_address.lb,compute_address,lb,delete,Static IP (unused),hour,USD,hour,0.01,1,0.01,,,,-0.01,,,embedded-1f0c83a4d2b7\n" +
+			"b.json,google_compute_network.net,google_compute_network,,,,,USD,hour,,,,,,,,unsupported_type,unsupported resource type 'google_compute_network',embedded-1f0c83a4d2b7\n"},
+		{"tsv", '\t', "" +
+			"plan_file\taddress\tkind\tname\taction\tcomponent\tusage_unit\tcurrency\tperiod\tbefore_unit_cost\tbefore_units\tbefore_cost\tafter_unit_cost\tafter_units\tafter_cost\tcost_change\tunpriced_reason\terror\tdata_version\n" +
+			"a.json\tgoogle_compute_address.ip\tcompute_address\tip\tupdate\tStatic IP (unused)\thour\tUSD\thour\t0.01\t1\t0.01\t0.01\t0\t0\t-0.01\t\t\tembedded-1f0c83a4d2b7\n" +
+			"a.json\tgoogle_compute_address.ip\tcompute_address\tip\tupdate\tStatic IP (in use)\thour\tUSD\thour\t0.004\t0\t0\t0.004\t1\t0.004\t0.004\t\t\tembedded-1f0c83a4d2b7\n" +
+			"b.json\tgoogle_compute_global_address.lb\tcompute_address\tlb\tdelete\tStatic IP (unused)\thour\tUSD\thour\t0.01\t1\t0.01\t\t\t\t-0.01\t\t\tembedded-1f0c83a4d2b7\n" +
+			"b.json\tgoogle_compute_network.net\tgoogle_compute_network\t\t\t\t\tUSD\thour\t\t\t\t\t\t\t\tunsupported_type\tunsupported resource type 'google_compute_network'\tembedded-1f0c83a4d2b7\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var b bytes.Buffer
+			w := NewCSVWriter(&b, test.comma)
+			a, _ := NewReport([]resources.ResourceState{update}, nil, resources.Hour, resources.Hour)
+			a.DataVersion = "embedded-1f0c83a4d2b7"
+			if err := w.Write("a.json", a); err != nil {
+				t.Fatal(err)
+			}
+			r, _ := NewReport([]resources.ResourceState{remove}, []*resources.ResourceError{network}, resources.Hour, resources.Hour)
+			r.DataVersion = "embedded-1f0c83a4d2b7"
+			if err := w.Write("b.json", r); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != test.expected {
+				t.Errorf("got:\n%s\nwant:\n%s", b.String(), test.expected)
+			}
+		})
+	}
+}
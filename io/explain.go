@@ -0,0 +1,90 @@
+package io
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/googleinterns/terraform-cost-estimation/io/js"
+	"github.com/googleinterns/terraform-cost-estimation/io/web"
+	"github.com/googleinterns/terraform-cost-estimation/resources"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// explainMatches returns the SKU matches recorded by the states in explain mode.
+func explainMatches(states []resources.ResourceState) (matches []resources.ComponentMatch) {
+	for _, s := range states {
+		if e, ok := s.(resources.Explainer); ok {
+			matches = append(matches, e.Explain()...)
+		}
+	}
+	return
+}
+
+func rejectedString(r resources.RejectedSKU) string {
+	return r.ID + " " + r.Description + " (" + r.Reason + ")"
+}
+
+func explainOut(matches []resources.ComponentMatch) (out []js.SKUMatchOut) {
+	for _, m := range matches {
+		o := js.SKUMatchOut{
+			Resource:    m.Resource,
+			Component:   m.Component,
+			State:       m.State,
+			SKUID:       m.Match.ID,
+			Description: m.Match.Description,
+			Regions:     m.Match.Regions,
+			UsageType:   m.Match.UsageType,
+			TierStart:   m.Match.TierStart,
+			Contains:    m.Match.Contains,
+			Omits:       m.Match.Omits,
+			Rejected:    []js.RejectedSKUOut{},
+		}
+		for _, r := range m.Match.Rejected {
+			o.Rejected = append(o.Rejected, js.RejectedSKUOut{SKUID: r.ID, Description: r.Description, Reason: r.Reason})
+		}
+		out = append(out, o)
+	}
+	return
+}
+
+func explainRows(matches []resources.ComponentMatch) (rows []web.ExplainRow) {
+	for _, m := range matches {
+		r := web.ExplainRow{
+			Resource:    m.Resource,
+			Component:   m.Component,
+			State:       m.State,
+			SKUID:       m.Match.ID,
+			Description: m.Match.Description,
+			Regions:     strings.Join(m.Match.Regions, ", "),
+			UsageType:   m.Match.UsageType,
+			Tier:        fmt.Sprintf("from %g", m.Match.TierStart),
+			Contains:    strings.Join(m.Match.Contains, ", "),
+			Omits:       strings.Join(m.Match.Omits, ", "),
+		}
+		for _, rej := range m.Match.Rejected {
+			r.Rejected = append(r.Rejected, rejectedString(rej))
+		}
+		rows = append(rows, r)
+	}
+	return
+}
+
+// GetExplainTable returns the table with the SKU used for each component and how it was matched.
+func GetExplainTable(matches []resources.ComponentMatch) *table.Table {
+	t := &table.Table{}
+	t.SetTitle("SKU matches")
+	t.AppendHeader(table.Row{"Resource", "Component", "State", "SKU", "Usage type", "Tier", "Filters", "Rejected candidates"})
+	for _, r := range explainRows(matches) {
+		sku := r.SKUID + "\n" + r.Description + "\n" + r.Regions
+		filters := "contains: " + r.Contains + "\nomits: " + r.Omits
+		t.AppendRow(table.Row{r.Resource, r.Component, r.State, sku, r.UsageType, r.Tier, filters, strings.Join(r.Rejected, "\n")})
+	}
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 4, WidthMax: 50},
+		{Number: 7, WidthMax: 40},
+		{Number: 8, WidthMax: 60},
+	})
+	t.SetStyle(table.StyleLight)
+	t.Style().Options.SeparateRows = true
+	return t
+}
@@ -0,0 +1,80 @@
+package js
+
+// SchemaVersion is the version of the json output described by the JSON Schema document schema_v2.json.
+const SchemaVersion = "2.4.0"
+
+// ReportV2 is the version 2 json output. Unlike JsonOutput, all the costs are numbers, missing values are
+// explicit nulls and the resources are listed with their Terraform addresses.
+type ReportV2 struct {
+	SchemaVersion string               `json:"schema_version"`
+	Currency      CurrencyV2           `json:"currency"`
+	Period        PeriodV2             `json:"period"`
+	CostChange    float64              `json:"cost_change"`
+	Periods       map[string]PeriodOut `json:"periods"`
+	Coverage      float64              `json:"coverage"`
+	Resources     []ResourceV2         `json:"resources"`
+	Unpriced      []UnpricedOut        `json:"unpriced_resources"`
+	Plans         []PlanV2             `json:"plans,omitempty"`
+	Snapshot      string               `json:"catalog_snapshot,omitempty"`
+	DataVersion   string               `json:"data_version,omitempty"`
+	Explain       []SKUMatchOut        `json:"explain,omitempty"`
+}
+
+// PlanV2 contains the subtotal of a plan file of an aggregate report: its cost change over the reporting period,
+// coverage and number of priced and unpriced resource changes.
+type PlanV2 struct {
+	Name       string  `json:"name"`
+	CostChange float64 `json:"cost_change"`
+	Coverage   float64 `json:"coverage"`
+	Resources  int     `json:"resources"`
+	Unpriced   int     `json:"unpriced_resources"`
+}
+
+// CurrencyV2 contains the ISO 4217 code of the currency of the costs and their unit over the reporting period (e.g. USD/month).
+type CurrencyV2 struct {
+	Code string `json:"code"`
+	Unit string `json:"unit"`
+}
+
+// PeriodV2 contains the name and number of hours of the reporting period.
+type PeriodV2 struct {
+	Name  string  `json:"name"`
+	Hours float64 `json:"hours"`
+}
+
+// ResourceV2 contains a resource change: its kind, addresses, attributes and costs over the reporting period.
+// Before and After are null when the resource does not exist (before creation or after deletion).
+// Plan is the plan file of the resource change in an aggregate report.
+type ResourceV2 struct {
+	Plan       string              `json:"plan,omitempty"`
+	Kind       string              `json:"kind"`
+	Addresses  []string            `json:"addresses"`
+	Action     string              `json:"action"`
+	Name       ChangeV2            `json:"name"`
+	Attributes map[string]ChangeV2 `json:"attributes"`
+	Before     *CostsV2            `json:"before"`
+	After      *CostsV2            `json:"after"`
+	CostChange float64             `json:"cost_change"`
+}
+
+// ChangeV2 contains the before and after value of a field, null if the resource does not exist or the value is unknown.
+// The values are strings, or numbers for the numeric attributes.
+type ChangeV2 struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// CostsV2 contains the costs of the billing components of a resource and their total.
+type CostsV2 struct {
+	Components []ComponentV2 `json:"components"`
+	TotalCost  float64       `json:"total_cost"`
+}
+
+// ComponentV2 contains the cost of a billing component. The usage unit is null if the component was not priced.
+type ComponentV2 struct {
+	Name      string  `json:"name"`
+	UnitCost  float64 `json:"unit_cost"`
+	Units     float64 `json:"units"`
+	UsageUnit *string `json:"usage_unit"`
+	TotalCost float64 `json:"total_cost"`
+}
@@ -1,16 +1,109 @@
 package js
 
+import "encoding/json"
+
 // JSONOut is a general interface of a JSON output.
 type JSONOut interface {
 	AddToJSONTableList(*JsonOutput)
 }
 
 // JsonOutput contains relevant information resources and cost changes in a file.
+// The outputs of the resources are kept in lists by name (e.g. instances_pricing_info),
+// which are rendered as top level fields.
 type JsonOutput struct {
-	Delta                   float64                    `json:"cost_change"`
-	PricingUnit             string                     `json:"pricing_unit"`
-	ComputeInstancesPricing []*ComputeInstanceStateOut `json:"instances_pricing_info"`
-	ComputeDisksPricing     []*ComputeDiskStateOut     `json:"disks_pricing_info"`
+	Delta       float64
+	PricingUnit string
+	Period      string
+	Periods     map[string]PeriodOut
+	Lists       map[string][]JSONOut
+	Explain     []SKUMatchOut
+	Coverage    float64
+	Unpriced    []UnpricedOut
+	Snapshot    string
+	DataVersion string
+}
+
+// Add appends the resource output to the list with the given name.
+func (out *JsonOutput) Add(list string, o JSONOut) {
+	if out.Lists == nil {
+		out.Lists = map[string][]JSONOut{}
+	}
+	out.Lists[list] = append(out.Lists[list], o)
+}
+
+// builtinLists are the resource lists of the first version of the output, always rendered (null if empty)
+// for compatibility.
+var builtinLists = []string{"instances_pricing_info", "disks_pricing_info"}
+
+// MarshalJSON renders the cost change, pricing unit, reporting periods, coverage, unpriced resources,
+// catalog snapshot (if any), class detail data version (if set) and resource lists as fields of one object.
+func (out JsonOutput) MarshalJSON() ([]byte, error) {
+	unpriced := out.Unpriced
+	if unpriced == nil {
+		unpriced = []UnpricedOut{}
+	}
+	m := map[string]interface{}{
+		"cost_change":        out.Delta,
+		"pricing_unit":       out.PricingUnit,
+		"period":             out.Period,
+		"periods":            out.Periods,
+		"coverage":           out.Coverage,
+		"unpriced_resources": unpriced,
+	}
+	for _, name := range builtinLists {
+		m[name] = nil
+	}
+	for name, l := range out.Lists {
+		m[name] = l
+	}
+	if out.Explain != nil {
+		m["explain"] = out.Explain
+	}
+	if out.Snapshot != "" {
+		m["catalog_snapshot"] = out.Snapshot
+	}
+	if out.DataVersion != "" {
+		m["data_version"] = out.DataVersion
+	}
+	return json.Marshal(m)
+}
+
+// PeriodOut contains the number of hours of a reporting period and the total cost change over it.
+type PeriodOut struct {
+	Hours float64 `json:"hours"`
+	Delta float64 `json:"cost_change"`
+}
+
+// UnpricedOut contains a resource change that could not be priced, so is left out of the cost change, and why.
+// Plan is the plan file of the resource change in an aggregate report.
+type UnpricedOut struct {
+	Plan    string `json:"plan,omitempty"`
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	Reason  string `json:"reason"`
+	Error   string `json:"error"`
+}
+
+// SKUMatchOut contains the SKU used to price a component of a resource and how it was chosen.
+type SKUMatchOut struct {
+	Resource    string           `json:"resource"`
+	Component   string           `json:"component"`
+	State       string           `json:"state"`
+	SKUID       string           `json:"sku_id"`
+	Description string           `json:"description"`
+	Regions     []string         `json:"service_regions"`
+	UsageType   string           `json:"usage_type"`
+	TierStart   float64          `json:"tier_start_usage_amount"`
+	Contains    []string         `json:"description_contains"`
+	Omits       []string         `json:"description_omits"`
+	Rejected    []RejectedSKUOut `json:"rejected_candidates"`
+}
+
+// RejectedSKUOut contains a candidate SKU rejected for a component and the reason why.
+type RejectedSKUOut struct {
+	SKUID       string `json:"sku_id"`
+	Description string `json:"description"`
+	Reason      string `json:"reason"`
 }
 
 // ComputeInstanceStateOut contains ComputeInstanceState information to be outputted.
@@ -26,7 +119,7 @@ type ComputeInstanceStateOut struct {
 }
 
 func (out *ComputeInstanceStateOut) AddToJSONTableList(json *JsonOutput) {
-	json.ComputeInstancesPricing = append(json.ComputeInstancesPricing, out)
+	json.Add("instances_pricing_info", out)
 }
 
 // ComputeDiskStateOut contains ComputeDiskState information to be outputted.
@@ -40,7 +133,114 @@ type ComputeDiskStateOut struct {
 }
 
 func (out *ComputeDiskStateOut) AddToJSONTableList(json *JsonOutput) {
-	json.ComputeDisksPricing = append(json.ComputeDisksPricing, out)
+	json.Add("disks_pricing_info", out)
+}
+
+// SQLInstanceStateOut contains SQLInstanceState information to be outputted.
+type SQLInstanceStateOut struct {
+	Name            Change                 `json:"name"`
+	ID              Change                 `json:"id"`
+	Region          Change                 `json:"region"`
+	DatabaseVersion Change                 `json:"database_version"`
+	Tier            Change                 `json:"tier"`
+	Availability    Change                 `json:"availability_type"`
+	DiskType        Change                 `json:"disk_type"`
+	MasterInstance  Change                 `json:"master_instance_name"`
+	Action          string                 `json:"action"`
+	Pricing         ComponentsStatePricing `json:"pricing_info"`
+}
+
+func (out *SQLInstanceStateOut) AddToJSONTableList(json *JsonOutput) {
+	json.Add("sql_instances_pricing_info", out)
+}
+
+// StorageBucketStateOut contains StorageBucketState information to be outputted.
+type StorageBucketStateOut struct {
+	Name         Change                 `json:"name"`
+	ID           Change                 `json:"id"`
+	Location     Change                 `json:"location"`
+	LocationType Change                 `json:"location_type"`
+	StorageClass Change                 `json:"storage_class"`
+	Lifecycle    Change                 `json:"lifecycle"`
+	Action       string                 `json:"action"`
+	Pricing      ComponentsStatePricing `json:"pricing_info"`
+}
+
+func (out *StorageBucketStateOut) AddToJSONTableList(json *JsonOutput) {
+	json.Add("storage_buckets_pricing_info", out)
+}
+
+// KubernetesClusterStateOut contains KubernetesClusterState information to be outputted.
+type KubernetesClusterStateOut struct {
+	Name      Change                 `json:"name"`
+	ID        Change                 `json:"id"`
+	Location  Change                 `json:"location"`
+	Mode      Change                 `json:"mode"`
+	NodePools Change                 `json:"node_pools"`
+	Action    string                 `json:"action"`
+	Pricing   ComponentsStatePricing `json:"pricing_info"`
+}
+
+func (out *KubernetesClusterStateOut) AddToJSONTableList(json *JsonOutput) {
+	json.Add("clusters_pricing_info", out)
+}
+
+// NodePoolStateOut contains NodePoolState information to be outputted.
+type NodePoolStateOut struct {
+	Name        Change                 `json:"name"`
+	Cluster     Change                 `json:"cluster"`
+	Zones       Change                 `json:"zones"`
+	MachineType Change                 `json:"machine_type"`
+	NodeCount   Change                 `json:"node_count"`
+	Action      string                 `json:"action"`
+	Pricing     ComponentsStatePricing `json:"pricing_info"`
+}
+
+func (out *NodePoolStateOut) AddToJSONTableList(json *JsonOutput) {
+	json.Add("node_pools_pricing_info", out)
+}
+
+// AddressStateOut contains AddressState information to be outputted.
+type AddressStateOut struct {
+	Name        Change                 `json:"name"`
+	ID          Change                 `json:"id"`
+	Region      Change                 `json:"region"`
+	Address     Change                 `json:"address"`
+	NetworkTier Change                 `json:"network_tier"`
+	InUse       Change                 `json:"in_use"`
+	Action      string                 `json:"action"`
+	Pricing     ComponentsStatePricing `json:"pricing_info"`
+}
+
+func (out *AddressStateOut) AddToJSONTableList(json *JsonOutput) {
+	json.Add("addresses_pricing_info", out)
+}
+
+// RouterNATStateOut contains RouterNATState information to be outputted.
+type RouterNATStateOut struct {
+	Name    Change                 `json:"name"`
+	Router  Change                 `json:"router"`
+	Region  Change                 `json:"region"`
+	Action  string                 `json:"action"`
+	Pricing ComponentsStatePricing `json:"pricing_info"`
+}
+
+func (out *RouterNATStateOut) AddToJSONTableList(json *JsonOutput) {
+	json.Add("router_nats_pricing_info", out)
+}
+
+// LoadBalancerStateOut contains LoadBalancerState information to be outputted.
+type LoadBalancerStateOut struct {
+	Name            Change                 `json:"name"`
+	Region          Change                 `json:"region"`
+	ForwardingRules Change                 `json:"forwarding_rules"`
+	Proxies         Change                 `json:"target_proxies"`
+	Action          string                 `json:"action"`
+	Pricing         ComponentsStatePricing `json:"pricing_info"`
+}
+
+func (out *LoadBalancerStateOut) AddToJSONTableList(json *JsonOutput) {
+	json.Add("load_balancers_pricing_info", out)
 }
 
 // InstanceStatePricing contains ComputeInstanceState pricing info to be outputted.
@@ -59,6 +259,19 @@ type DiskStatePricing struct {
 	Delta  float64      `json:"cost_change"`
 }
 
+// ComponentsStatePricing contains the pricing info of a state priced by several billing components.
+type ComponentsStatePricing struct {
+	Before *ComponentsPricing `json:"before"`
+	After  *ComponentsPricing `json:"after"`
+	Delta  float64            `json:"cost_change"`
+}
+
+// ComponentsPricing contains the pricing info of each billing component and their total cost.
+type ComponentsPricing struct {
+	Components map[string]Pricing `json:"components"`
+	TotalCost  float64            `json:"total_cost"`
+}
+
 // InstancePricing contains ComputeInstance pricing info to be outputted.
 type InstancePricing struct {
 	Cpu       Pricing `json:"cpu"`
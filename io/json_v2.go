@@ -0,0 +1,132 @@
+package io
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/googleinterns/terraform-cost-estimation/io/js"
+	"github.com/googleinterns/terraform-cost-estimation/resources"
+)
+
+// nullable returns a pointer to the string or nil if it is empty, so that unknown values are rendered as null.
+func nullable(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// nullableValue returns the attribute value, or nil if it is an empty string or missing, so that unknown values are
+// rendered as null. Numeric values are kept as numbers.
+func nullableValue(v interface{}) interface{} {
+	if s, ok := v.(string); ok && s == "" {
+		return nil
+	}
+	return v
+}
+
+// changeV2 returns the change of a field of the before and after resources, null where they don't exist.
+func changeV2(before, after *resources.ResourceReport, value func(r *resources.ResourceReport) interface{}) js.ChangeV2 {
+	var c js.ChangeV2
+	if before != nil {
+		c.Before = nullableValue(value(before))
+	}
+	if after != nil {
+		c.After = nullableValue(value(after))
+	}
+	return c
+}
+
+// costsV2 returns the costs of the resource components over the period or nil if the resource doesn't exist.
+func costsV2(r *resources.ResourceReport, p resources.Period) *js.CostsV2 {
+	if r == nil {
+		return nil
+	}
+
+	out := &js.CostsV2{Components: []js.ComponentV2{}, TotalCost: p.Cost(r.Total())}
+	for _, c := range r.Components {
+		out.Components = append(out.Components, js.ComponentV2{
+			Name:      c.Name,
+			UnitCost:  p.Cost(c.UnitCost),
+			Units:     c.Units,
+			UsageUnit: nullable(c.UsageUnit),
+			TotalCost: p.Cost(c.Total()),
+		})
+	}
+	return out
+}
+
+// resourceV2 returns the version 2 json output of the resource change with the costs over the period.
+func resourceV2(r resources.StateReport, p resources.Period) js.ResourceV2 {
+	out := js.ResourceV2{
+		Kind:       r.Kind,
+		Addresses:  append([]string{}, r.Addresses...),
+		Action:     r.Action,
+		Name:       changeV2(r.Before, r.After, func(r *resources.ResourceReport) interface{} { return r.Name }),
+		Attributes: map[string]js.ChangeV2{},
+		Before:     costsV2(r.Before, p),
+		After:      costsV2(r.After, p),
+		CostChange: p.Cost(r.After.Total() - r.Before.Total()),
+	}
+
+	for _, side := range []*resources.ResourceReport{r.Before, r.After} {
+		if side == nil {
+			continue
+		}
+		for name := range side.Attributes {
+			out.Attributes[name] = changeV2(r.Before, r.After, func(r *resources.ResourceReport) interface{} { return r.Attributes[name] })
+		}
+	}
+	return out
+}
+
+// reportV2 returns the version 2 json output of all resources of the report, with the costs over its period.
+func reportV2(r *Report) js.ReportV2 {
+	delta := getTotalDelta(r.States)
+	out := js.ReportV2{
+		SchemaVersion: js.SchemaVersion,
+		Currency:      js.CurrencyV2{Code: r.Currency, Unit: r.Period.Unit(r.Currency)},
+		Period:        js.PeriodV2{Name: r.Period.Name, Hours: r.Period.Hours},
+		CostChange:    r.Period.Cost(delta),
+		Periods:       map[string]js.PeriodOut{},
+		Coverage:      r.Coverage(),
+		Resources:     []js.ResourceV2{},
+		Unpriced:      unpricedOut(r.Unpriced),
+		Snapshot:      r.Snapshot,
+		DataVersion:   r.DataVersion,
+	}
+	for _, period := range []resources.Period{resources.Hour, r.Month, resources.Year} {
+		out.Periods[period.Name] = js.PeriodOut{Hours: period.Hours, Delta: period.Cost(delta)}
+	}
+	for _, s := range r.Resources() {
+		out.Resources = append(out.Resources, resourceV2(s, r.Period))
+	}
+	if matches := explainMatches(r.States); len(matches) > 0 {
+		out.Explain = explainOut(matches)
+	}
+	return out
+}
+
+// RenderJsonV2 returns the version 2 json output of all resources of the report, with the costs over its period.
+// The total cost change over the hourly, monthly (of the report month period) and yearly periods is listed under "periods".
+// The resource changes that could not be priced are listed under "unpriced_resources", next to the "coverage" percentage
+// of the priced ones, the date of the catalog snapshot used, if any, under "catalog_snapshot" and the version
+// of the class detail data under "data_version".
+// Resource states that don't implement resources.Reporter are left out.
+func RenderJsonV2(r *Report) (string, error) {
+	jsonString, err := json.Marshal(reportV2(r))
+	if err != nil {
+		return "", err
+	}
+	return string(jsonString), nil
+}
+
+// GenerateJsonV2Out writes the version 2 json output with the pricing information of the resources of the report over its period.
+func GenerateJsonV2Out(w io.Writer, r *Report) error {
+	jsonString, err := RenderJsonV2(r)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, jsonString)
+	return err
+}
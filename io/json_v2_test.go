@@ -0,0 +1,80 @@
+package io
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/googleinterns/terraform-cost-estimation/resources"
+	"github.com/googleinterns/terraform-cost-estimation/resources/classdetail/instance"
+)
+
+func TestRenderJsonV2(t *testing.T) {
+	month, _ := resources.Month(resources.Month730h)
+	pricing := resources.PricingInfo{UsageUnit: "hour", HourlyUnitPrice: 0.5, CurrencyType: "EUR"}
+	address := &resources.Address{Name: "ip", Region: "europe-west1", Global: true, NetworkTier: "PREMIUM", UnusedPricing: pricing}
+	state := &resources.AddressState{After: address, Action: "create"}
+	state.AddAddress("google_compute_global_address.ip")
+	unpriced := []*resources.ResourceError{resources.NewResourceError("google_compute_instance.vm", "google_compute_instance",
+		resources.KindInvalidConfig, instance.ErrUnknownMachineType)}
+
+	expected := `{
+		"schema_version": "2.4.0",
+		"currency": {"code": "EUR", "unit": "EUR/month"},
+		"period": {"name": "month", "hours": 730},
+		"cost_change": 365,
+		"periods": {
+			"hour": {"hours": 1, "cost_change": 0.5},
+			"month": {"hours": 730, "cost_change": 365},
+			"year": {"hours": 8760, "cost_change": 4380}
+		},
+		"coverage": 50,
+		"resources": [{
+			"kind": "compute_address",
+			"addresses": ["google_compute_global_address.ip"],
+			"action": "create",
+			"name": {"before": null, "after": "ip"},
+			"attributes": {
+				"id": {"before": null, "after": null},
+				"region": {"before": null, "after": "europe-west1"},
+				"address": {"before": null, "after": null},
+				"network_tier": {"before": null, "after": "PREMIUM"},
+				"in_use": {"before": null, "after": 0}
+			},
+			"before": null,
+			"after": {
+				"components": [{"name": "Static IP (unused)", "unit_cost": 365, "units": 1, "usage_unit": "hour", "total_cost": 365}],
+				"total_cost": 365
+			},
+			"cost_change": 365
+		}],
+		"unpriced_resources": [{
+			"address": "google_compute_instance.vm",
+			"type": "google_compute_instance",
+			"reason": "unknown_machine_type",
+			"error": "machine type not supported"
+		}],
+		"data_version": "embedded-1f0c83a4d2b7"
+	}`
+
+	report, err := NewReport([]resources.ResourceState{state}, unpriced, month, month)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report.DataVersion = "embedded-1f0c83a4d2b7"
+	actual, err := RenderJsonV2(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got, want interface{}
+	if err := json.Unmarshal([]byte(actual), &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RenderJsonV2() = %s, want %s", actual, expected)
+	}
+}
@@ -4,77 +4,124 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
-	"runtime"
+	"strings"
 
+	"github.com/googleinterns/terraform-cost-estimation/billing"
 	"github.com/googleinterns/terraform-cost-estimation/io/js"
 	"github.com/googleinterns/terraform-cost-estimation/io/web"
 	"github.com/googleinterns/terraform-cost-estimation/resources"
 	"github.com/jedib0t/go-pretty/v6/table"
-	"html/template"
 	"log"
 )
 
-// GetOutputWriter returns the output os.File (stdout/file) for a given output path or an error.
-func GetOutputWriter(outputPath string) (*os.File, error) {
-	if outputPath == "stdout" {
-		return os.Stdout, nil
-	}
-
-	f, err := os.Create(outputPath)
+// GenerateWebPage writes a self-contained html output with the hourly, monthly and yearly pricing information
+// of the resources of the report, which can be sorted and filtered by action, type and module.
+// The unpriced resource changes and the SKU matches recorded in explain mode are shown in sections of their own.
+func GenerateWebPage(w io.Writer, r *Report) error {
+	t, err := web.ParseTemplate()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return f, nil
-}
-
-// FinishOutput closes the file when the output is done and returns an error where is the case.
-// If the output file is Stdout, thenit is not closed and 2 newlines are printed so that different plan file outputs can be separated.
-func FinishOutput(outputFile *os.File) error {
-	if outputFile != os.Stdout {
-		return outputFile.Close()
-	}
-	fmt.Println("\n-----------------------------------------------------------------------------------------------------------------------------")
-	fmt.Printf("\n\n\n")
-	return nil
-}
-
-// GenerateWebPage generates a html output with the pricing information of the specified resources.
-func GenerateWebPage(f *os.File, res []resources.ResourceState) error {
-	// Get path of template relative to this file.
-	_, callerFile, _, _ := runtime.Caller(0)
-	t, err := template.ParseFiles(filepath.Dir(callerFile) + "/web/web_template.gohtml")
+	res, err := webResources(r.States, r.Month)
 	if err != nil {
 		return err
 	}
-
-	if err = t.Execute(f, mapToWebTables(res)); err != nil {
-		return err
+	page := web.Page{
+		Currency:    r.Currency,
+		Resources:   res,
+		Coverage:    coverageString(r.States, r.Unpriced),
+		Unpriced:    unpricedRows(r.Unpriced),
+		Snapshot:    r.Snapshot,
+		DataVersion: r.DataVersion,
+		Explain:     explainRows(explainMatches(r.States)),
 	}
+	return t.Execute(w, page)
+}
 
-	return nil
+// addressModule returns the module path of the resource address ("module.a.module.b"), "root" for the root module.
+func addressModule(address string) string {
+	parts := strings.Split(address, ".")
+	var module []string
+	for i := 0; i+1 < len(parts) && parts[i] == "module"; i += 2 {
+		module = append(module, parts[i], parts[i+1])
+	}
+	if len(module) == 0 {
+		return "root"
+	}
+	return strings.Join(module, ".")
 }
 
-func mapToWebTables(res []resources.ResourceState) (t []*web.PricingTypeTables) {
+// webResources returns the resources of the web page, with their pricing tables and total costs per period.
+// The name of the resources that don't implement resources.Reporter is taken from their tables.
+// It returns an error if the tables of a resource can't be built (e.g. its components are priced in different currencies).
+func webResources(res []resources.ResourceState, month resources.Period) ([]web.Resource, error) {
+	periods := map[string]resources.Period{"hourly": resources.Hour, "monthly": month, "yearly": resources.Year}
+	var out []web.Resource
 	for i, r := range res {
-		t = append(t, r.GetWebTables(i))
+		tables, err := r.GetWebTables(i, month)
+		if err != nil {
+			return nil, err
+		}
+		w := web.Resource{Index: i, Tables: tables, Costs: map[string]web.Costs{}}
+		w.Name = w.Tables.Hourly.Header[1]
+		before, after := 0.0, 0.0
+		if reporter, ok := r.(resources.Reporter); ok {
+			report := reporter.Report()
+			w.Kind, w.Action = report.Kind, report.Action
+			if report.After != nil {
+				w.Name = report.After.Name
+			} else if report.Before != nil {
+				w.Name = report.Before.Name
+			}
+			if len(report.Addresses) > 0 {
+				w.Module = addressModule(report.Addresses[0])
+			}
+			before, after = report.Before.Total(), report.After.Total()
+		} else {
+			after = r.GetDelta()
+		}
+		if w.Module == "" {
+			w.Module = "root"
+		}
+
+		for name, p := range periods {
+			w.Costs[name] = web.Costs{Before: p.Cost(before), After: p.Cost(after), Delta: p.Cost(after - before)}
+		}
+		out = append(out, w)
 	}
-	return
+	return out, nil
 }
 
-// RenderJson returns the string with json output struct for all resources.
-func RenderJson(states []resources.ResourceState) (string, error) {
+// RenderJson returns the string with json output struct for all resources of the report, with the costs over its period.
+// The total cost change over the hourly, monthly (of the report month period) and yearly periods is listed under "periods".
+// The resource changes that could not be priced are listed under "unpriced_resources", next to the "coverage"
+// percentage of the priced ones. The SKU matches recorded in explain mode are listed under "explain"
+// and the date of the catalog snapshot used, if any, under "catalog_snapshot". The version of the class detail data
+// is given under "data_version".
+func RenderJson(r *Report) (string, error) {
 	out := js.JsonOutput{}
-	out.Delta = getTotalDelta(states)
-	out.PricingUnit = "USD/hour"
-	for _, state := range states {
-		s, err := state.ToStateOut()
+	delta := getTotalDelta(r.States)
+	out.Delta = r.Period.Cost(delta)
+	out.PricingUnit = r.Period.Unit(r.Currency)
+	out.Period = r.Period.Name
+	out.Periods = map[string]js.PeriodOut{}
+	for _, period := range []resources.Period{resources.Hour, r.Month, resources.Year} {
+		out.Periods[period.Name] = js.PeriodOut{Hours: period.Hours, Delta: period.Cost(delta)}
+	}
+	for _, state := range r.States {
+		s, err := state.ToStateOut(r.Period)
 		if err == nil || s != nil {
 			s.AddToJSONTableList(&out)
 		}
 	}
+	out.Coverage = r.Coverage()
+	out.Unpriced = unpricedOut(r.Unpriced)
+	out.Snapshot = r.Snapshot
+	out.DataVersion = r.DataVersion
+	if matches := explainMatches(r.States); len(matches) > 0 {
+		out.Explain = explainOut(matches)
+	}
 	jsonString, err := json.Marshal(out)
 	if err != nil {
 		return "", err
@@ -82,54 +129,91 @@ func RenderJson(states []resources.ResourceState) (string, error) {
 	return string(jsonString), err
 }
 
-// GenerateJsonOut generates a json file with the pricing information of the specified resources.
-func GenerateJsonOut(f *os.File, res []resources.ResourceState) error {
-	jsonString, err := RenderJson(res)
+// GenerateJsonOut writes the json output with the pricing information of the resources of the report over its period.
+func GenerateJsonOut(w io.Writer, r *Report) error {
+	jsonString, err := RenderJson(r)
 	if err != nil {
-		return nil
-	}
-	if _, err = io.WriteString(f, jsonString); err != nil {
 		return err
 	}
-	return nil
+	_, err = io.WriteString(w, jsonString)
+	return err
 }
 
-// GetSummaryTable returns the table with brief cost changes info about all resources (Compute Instances and Compute Disks).
-func GetSummaryTable(states []resources.ResourceState) *table.Table {
+// GetSummaryTable returns the table with brief cost changes info about all resources of the report over its period,
+// with the coverage of the priced resource changes, the catalog snapshot used, if any, and the version
+// of the class detail data in its caption.
+func GetSummaryTable(r *Report) *table.Table {
 	t := &table.Table{}
 	autoMerge := table.RowConfig{AutoMerge: true}
 
-	dTotal := getTotalDelta(states)
-	t.SetTitle(fmt.Sprintf("The total cost change for all Resources is %.6f USD/hour.", dTotal))
-	h := "Pricing Information\n(USD/h)"
+	unit := r.Period.Unit(r.Currency)
+	t.SetTitle(fmt.Sprintf("The total cost change for all Resources is %.6f %s.", r.CostChange(), unit))
+	h := "Pricing Information\n(" + unit + ")"
 	t.AppendRow(table.Row{h, h, h, h, h}, autoMerge)
 	t.AppendRow(table.Row{"Name", "ID", "Type", "Action", "Delta"})
-	for _, s := range states {
-		if row, err := s.GetSummaryRow(); err == nil {
+	for _, s := range r.States {
+		if row, err := s.GetSummaryRow(r.Period); err == nil {
 			t.AppendRow(row)
 		} else {
 			log.Printf("Error: %v", err)
 		}
 	}
+	t.SetCaption("%s", coverageString(r.States, r.Unpriced)+snapshotString(r.Snapshot)+dataVersionString(r.DataVersion))
 	t.SetStyle(table.StyleLight)
 	t.Style().Options.SeparateRows = true
 	return t
 }
 
-// OutputPricing writes pricing information about each resource and summary.
-func OutputPricing(states []resources.ResourceState, f *os.File) {
-	f.Write([]byte(GetSummaryTable(states).Render() + "\n\n"))
-	f.Write([]byte("\n List of all Resources:\n\n"))
-	for _, s := range states {
+// OutputPricing writes pricing information about each resource of the report and summary over its period,
+// the resource changes that could not be priced and the SKU matches recorded in explain mode.
+func OutputPricing(w io.Writer, r *Report) error {
+	var b strings.Builder
+	b.WriteString(GetSummaryTable(r).Render() + "\n\n")
+	if len(r.Unpriced) > 0 {
+		b.WriteString(GetUnpricedTable(r.Unpriced).Render() + "\n\n")
+	}
+	b.WriteString("\n List of all Resources:\n\n")
+	for _, s := range r.States {
 		if s != nil {
-			t, err := s.ToTable()
+			t, err := s.ToTable(r.Period)
 			if err == nil {
-				f.Write([]byte(t.Render() + "\n\n\n"))
+				b.WriteString(t.Render() + "\n\n\n")
 			} else {
 				log.Printf("Error: %v", err)
 			}
 		}
 	}
+
+	if matches := explainMatches(r.States); len(matches) > 0 {
+		b.WriteString("\n Explanation of the SKU matches:\n\n")
+		b.WriteString(GetExplainTable(matches).Render() + "\n\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ReportCurrency returns the currency all the resources are priced in or an error if they are priced in different ones,
+// since their costs can't be added up in the same total.
+func ReportCurrency(states []resources.ResourceState) (string, error) {
+	currency := ""
+	for _, s := range states {
+		r, ok := s.(resources.CurrencyReporter)
+		if !ok {
+			continue
+		}
+		c, err := r.Currency()
+		if err != nil {
+			return "", err
+		}
+		if currency != "" && c != currency {
+			return "", fmt.Errorf("resources are priced in different currencies (%s, %s)", currency, c)
+		}
+		currency = c
+	}
+	if currency == "" {
+		currency = billing.DefaultCurrency
+	}
+	return currency, nil
 }
 
 // getTotalDelta returns the cost change of all resources.
@@ -0,0 +1,39 @@
+package io
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/googleinterns/terraform-cost-estimation/resources"
+)
+
+func TestRenderJson(t *testing.T) {
+	month, _ := resources.Month(resources.Month730h)
+	pricing := resources.PricingInfo{UsageUnit: "hour", HourlyUnitPrice: 0.5, CurrencyType: "USD"}
+	state := &resources.AddressState{After: &resources.Address{Name: "ip", Region: "europe-west1", UnusedPricing: pricing},
+		Action: "create"}
+	state.AddAddress("google_compute_address.ip")
+
+	report, err := NewReport([]resources.ResourceState{state}, nil, month, month)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := RenderJson(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(actual), &got); err != nil {
+		t.Fatal(err)
+	}
+	// The lists of the first version of the output are kept even if the plan has no instances or disks.
+	for _, key := range []string{"instances_pricing_info", "disks_pricing_info"} {
+		if v, ok := got[key]; !ok || v != nil {
+			t.Errorf("RenderJson() %s = %v (present %t), want null", key, v, ok)
+		}
+	}
+	if l, ok := got["addresses_pricing_info"].([]interface{}); !ok || len(l) != 1 {
+		t.Errorf("RenderJson() addresses_pricing_info = %v, want the address", got["addresses_pricing_info"])
+	}
+}
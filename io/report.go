@@ -0,0 +1,53 @@
+package io
+
+import (
+	"github.com/googleinterns/terraform-cost-estimation/resources"
+)
+
+// Report is the cost estimation of the resource changes of a plan, rendered by the output functions.
+// It holds the priced resource states in plan order and the resource changes that could not be priced.
+type Report struct {
+	// Currency is the ISO 4217 code of the currency all the costs are priced in.
+	Currency string
+	// Period is the reporting period of the costs, Month the one of the html monthly tables.
+	Period resources.Period
+	Month  resources.Period
+	// States are the priced resource states, Unpriced the resource changes missing from the totals.
+	States   []resources.ResourceState
+	Unpriced []*resources.ResourceError
+	// Snapshot is the date of the catalog snapshot the resources are priced with, empty for the current prices.
+	Snapshot string
+	// DataVersion is the version of the machine type, disk type and image details the resources are priced with.
+	DataVersion string
+}
+
+// NewReport returns the report of the priced states and unpriced resource changes with the costs
+// over the reporting period p, or an error if the states are priced in different currencies.
+func NewReport(states []resources.ResourceState, unpriced []*resources.ResourceError, p, month resources.Period) (*Report, error) {
+	currency, err := ReportCurrency(states)
+	if err != nil {
+		return nil, err
+	}
+	return &Report{Currency: currency, Period: p, Month: month, States: states, Unpriced: unpriced}, nil
+}
+
+// CostChange returns the total cost change of the priced resources over the reporting period.
+func (r *Report) CostChange() float64 {
+	return r.Period.Cost(getTotalDelta(r.States))
+}
+
+// Coverage returns the percentage of the resource changes that were priced.
+func (r *Report) Coverage() float64 {
+	return resources.Coverage(r.States, r.Unpriced)
+}
+
+// Resources returns the typed reports of the priced resource changes, in plan order, with hourly costs.
+// Resource states that don't implement resources.Reporter are left out.
+func (r *Report) Resources() (out []resources.StateReport) {
+	for _, s := range r.States {
+		if reporter, ok := s.(resources.Reporter); ok {
+			out = append(out, reporter.Report())
+		}
+	}
+	return
+}
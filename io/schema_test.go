@@ -0,0 +1,200 @@
+package io
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/googleinterns/terraform-cost-estimation/resources"
+	"github.com/googleinterns/terraform-cost-estimation/resources/classdetail/instance"
+)
+
+// schemaValidator validates json documents against the subset of JSON Schema draft-07 used by io/js/schema_v2.json.
+type schemaValidator struct {
+	root map[string]interface{}
+}
+
+// jsonType returns the JSON Schema type name of a decoded json value.
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// validate returns the first violation of the schema by the value at the given path, nil if there is none.
+func (s *schemaValidator) validate(schema map[string]interface{}, v interface{}, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/definitions/")
+		def, ok := s.root["definitions"].(map[string]interface{})[name].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: unknown reference %s", path, ref)
+		}
+		return s.validate(def, v, path)
+	}
+
+	if t, ok := schema["type"]; ok {
+		types, ok := t.([]interface{})
+		if !ok {
+			types = []interface{}{t}
+		}
+		found := false
+		for _, name := range types {
+			n, isNumber := v.(float64)
+			found = found || name == jsonType(v) || name == "integer" && isNumber && n == float64(int64(n))
+		}
+		if !found {
+			return fmt.Errorf("%s: %s is not of type %v", path, jsonType(v), t)
+		}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			found = found || reflect.DeepEqual(e, v)
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", path, v, enum)
+		}
+	}
+	if options, ok := schema["oneOf"].([]interface{}); ok {
+		matches := 0
+		for _, o := range options {
+			if s.validate(o.(map[string]interface{}), v, path) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("%s: value matches %d of the oneOf schemas", path, matches)
+		}
+	}
+
+	switch v := v.(type) {
+	case string:
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(v) {
+			return fmt.Errorf("%s: %q does not match %s", path, v, pattern)
+		}
+	case float64:
+		if min, ok := schema["minimum"].(float64); ok && v < min {
+			return fmt.Errorf("%s: %v is less than %v", path, v, min)
+		}
+		if max, ok := schema["maximum"].(float64); ok && v > max {
+			return fmt.Errorf("%s: %v is greater than %v", path, v, max)
+		}
+		if min, ok := schema["exclusiveMinimum"].(float64); ok && v <= min {
+			return fmt.Errorf("%s: %v is not greater than %v", path, v, min)
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := s.validate(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]interface{}:
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, ok := v[name.(string)]; !ok {
+					return fmt.Errorf("%s: missing required property %s", path, name)
+				}
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, value := range v {
+			if p, ok := properties[name].(map[string]interface{}); ok {
+				if err := s.validate(p, value, path+"."+name); err != nil {
+					return err
+				}
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					return fmt.Errorf("%s: unexpected property %s", path, name)
+				}
+			case map[string]interface{}:
+				if err := s.validate(additional, value, path+"."+name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateSchemaV2 validates the json document against io/js/schema_v2.json.
+func validateSchemaV2(t *testing.T, document string) error {
+	data, err := ioutil.ReadFile("js/schema_v2.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &schemaValidator{}
+	if err := json.Unmarshal(data, &s.root); err != nil {
+		t.Fatal(err)
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(document), &v); err != nil {
+		t.Fatal(err)
+	}
+	return s.validate(s.root, v, "$")
+}
+
+func TestSchemaV2(t *testing.T) {
+	unpriced := []*resources.ResourceError{resources.NewResourceError("google_compute_instance.vm", "google_compute_instance",
+		resources.KindInvalidConfig, instance.ErrUnknownMachineType)}
+	report := addressReport(t, "a", "EUR", 0.5, unpriced)
+	report.Snapshot = "2021-06-01"
+	report.DataVersion = "embedded-1f0c83a4d2b7"
+	single, err := RenderJsonV2(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := NewAggregate([]PlanReport{
+		{"a.json", addressReport(t, "a", "EUR", 0.5, nil)},
+		{"b.json", addressReport(t, "b", "EUR", 1, unpriced)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	aggregate, err := RenderJsonAggregate(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		document string
+		ok       bool
+	}{
+		{"json_v2", single, true},
+		{"aggregate", aggregate, true},
+		{"string_cost", strings.Replace(single, `"cost_change":365`, `"cost_change":"365"`, 1), false},
+		{"object_attribute", strings.Replace(single, `"after":"PREMIUM"`, `"after":{}`, 1), false},
+		{"unknown_reason", strings.Replace(single, `"unknown_machine_type"`, `"unknown"`, 1), false},
+		{"snapshot_format", strings.Replace(single, `"2021-06-01"`, `"June 2021"`, 1), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateSchemaV2(t, test.document)
+			if ok := err == nil; ok != test.ok {
+				t.Errorf("validateSchemaV2() error = %v, want ok %v", err, test.ok)
+			}
+		})
+	}
+}
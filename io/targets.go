@@ -0,0 +1,203 @@
+package io
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Formats are the output formats, in the order they are documented.
+var Formats = []string{"txt", "json", "json-v2", "html", "csv", "tsv"}
+
+// separator is written after each report written to stdout, so that the reports of several plan files can be told apart.
+const separator = "\n-----------------------------------------------------------------------------------------------------------------------------\n\n\n\n"
+
+// Target is an output of the reports: a format and a path, "stdout" for the standard output.
+type Target struct {
+	Format string
+	Path   string
+}
+
+func isFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Flat reports whether the target is a csv or tsv export, which collects the rows of all the plan files.
+func (t Target) Flat() bool {
+	return t.Format == "csv" || t.Format == "tsv"
+}
+
+// Stdout reports whether the target is the standard output.
+func (t Target) Stdout() bool {
+	return t.Path == "stdout"
+}
+
+// ParseTargets parses the comma separated list of format:path pairs (e.g. html:report.html,json:report.json,txt:stdout).
+// The targets given as a path only are written in the default format.
+func ParseTargets(spec, defaultFormat string) ([]Target, error) {
+	if !isFormat(defaultFormat) {
+		return nil, fmt.Errorf("unknown format %q, must be one of %s", defaultFormat, strings.Join(Formats, ", "))
+	}
+
+	var targets []Target
+	for _, s := range strings.Split(spec, ",") {
+		t := Target{Format: defaultFormat, Path: s}
+		if i := strings.Index(s, ":"); i >= 0 && isFormat(s[:i]) {
+			t = Target{Format: s[:i], Path: s[i+1:]}
+		}
+		if t.Path == "" {
+			return nil, fmt.Errorf("output %q has no path", s)
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+// AssignTargets returns the targets of each of the given number of plan files.
+// The targets of a format are matched to the plan files by position if there are as many as plan files.
+// Otherwise the format must have a single target, which is shared by all the plan files:
+// stdout, a csv or tsv export or, with a single plan file, any file.
+func AssignTargets(targets []Target, plans int) ([][]Target, error) {
+	byFormat := map[string][]Target{}
+	var formats []string
+	for _, t := range targets {
+		if byFormat[t.Format] == nil {
+			formats = append(formats, t.Format)
+		}
+		byFormat[t.Format] = append(byFormat[t.Format], t)
+	}
+
+	assigned := make([][]Target, plans)
+	for _, f := range formats {
+		l := byFormat[f]
+		switch {
+		case len(l) == plans:
+			for i, t := range l {
+				assigned[i] = append(assigned[i], t)
+			}
+		case len(l) == 1 && (plans == 1 || l[0].Stdout() || l[0].Flat()):
+			for i := range assigned {
+				assigned[i] = append(assigned[i], l[0])
+			}
+		default:
+			return nil, fmt.Errorf("%d %s outputs for %d plan files, must be one per plan file or a single shared one", len(l), f, plans)
+		}
+	}
+	return assigned, nil
+}
+
+// Render writes the report in the given format to w. The csv and tsv formats are written with a CSVWriter.
+func Render(w io.Writer, format string, r *Report) error {
+	switch format {
+	case "txt":
+		return OutputPricing(w, r)
+	case "json":
+		return GenerateJsonOut(w, r)
+	case "json-v2":
+		return GenerateJsonV2Out(w, r)
+	case "html":
+		return GenerateWebPage(w, r)
+	case "csv", "tsv":
+		c := NewCSVWriter(w, csvComma(format))
+		return c.Write("", r)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+func csvComma(format string) rune {
+	if format == "tsv" {
+		return '\t'
+	}
+	return ','
+}
+
+// Outputs writes the reports of one or more plan files to their targets. The file of a target is created on its first
+// report. Those shared by all the plan files (csv and tsv exports) stay open until Close, the others are closed
+// once written. The reports written to stdout are followed by a separator.
+type Outputs struct {
+	stdout io.Writer
+	files  map[string]*os.File
+	flat   map[Target]*CSVWriter
+}
+
+// NewOutputs returns the Outputs writing the stdout targets to the given writer.
+func NewOutputs(stdout io.Writer) *Outputs {
+	return &Outputs{stdout: stdout, files: map[string]*os.File{}, flat: map[Target]*CSVWriter{}}
+}
+
+// Write writes the report of the plan file to the target.
+func (o *Outputs) Write(t Target, planFile string, r *Report) error {
+	if t.Flat() {
+		return o.writeFlat(t, planFile, r)
+	}
+	return o.write(t, func(w io.Writer) error { return Render(w, t.Format, r) })
+}
+
+// WriteAggregate writes the aggregate report of the plan files to the target.
+func (o *Outputs) WriteAggregate(t Target, a *Aggregate) error {
+	if t.Flat() {
+		for _, p := range a.Plans {
+			if err := o.writeFlat(t, p.Name, p.Report); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return o.write(t, func(w io.Writer) error { return RenderAggregate(w, t.Format, a) })
+}
+
+// write renders a report to the target, followed by a separator on stdout, or to the file it creates and closes.
+func (o *Outputs) write(t Target, render func(w io.Writer) error) error {
+	if t.Stdout() {
+		if err := render(o.stdout); err != nil {
+			return err
+		}
+		_, err := io.WriteString(o.stdout, separator)
+		return err
+	}
+
+	f, err := os.Create(t.Path)
+	if err != nil {
+		return err
+	}
+	err = render(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (o *Outputs) writeFlat(t Target, planFile string, r *Report) error {
+	c, ok := o.flat[t]
+	if !ok {
+		w := o.stdout
+		if !t.Stdout() {
+			f, err := os.Create(t.Path)
+			if err != nil {
+				return err
+			}
+			o.files[t.Path] = f
+			w = f
+		}
+		c = NewCSVWriter(w, csvComma(t.Format))
+		o.flat[t] = c
+	}
+	return c.Write(planFile, r)
+}
+
+// Close closes the files of the shared targets.
+func (o *Outputs) Close() error {
+	var err error
+	for _, f := range o.files {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,59 @@
+package io
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParseTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		targets []Target
+		ok      bool
+	}{
+		{"paths", "a.txt,stdout", []Target{{"txt", "a.txt"}, {"txt", "stdout"}}, true},
+		{"pairs", "html:report.html,json:report.json,txt:stdout",
+			[]Target{{"html", "report.html"}, {"json", "report.json"}, {"txt", "stdout"}}, true},
+		{"json_v2", "json-v2:out.json", []Target{{"json-v2", "out.json"}}, true},
+		{"path_with_colon", `C:\out.txt`, []Target{{"txt", `C:\out.txt`}}, true},
+		{"no_path", "html:", nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			targets, err := ParseTargets(test.spec, "txt")
+			if (err == nil) != test.ok || !reflect.DeepEqual(targets, test.targets) {
+				t.Errorf("ParseTargets(%q) = %+v, %v; want %+v, ok %t", test.spec, targets, err, test.targets, test.ok)
+			}
+		})
+	}
+}
+
+func TestAssignTargets(t *testing.T) {
+	html1, html2 := Target{"html", "a.html"}, Target{"html", "b.html"}
+	stdout, csv := Target{"txt", "stdout"}, Target{"csv", "all.csv"}
+
+	tests := []struct {
+		name     string
+		targets  []Target
+		plans    int
+		assigned [][]Target
+		err      error
+	}{
+		{"single_plan", []Target{html1, stdout}, 1, [][]Target{{html1, stdout}}, nil},
+		{"by_position", []Target{html1, html2}, 2, [][]Target{{html1}, {html2}}, nil},
+		{"shared", []Target{html1, html2, stdout, csv}, 2, [][]Target{{html1, stdout, csv}, {html2, stdout, csv}}, nil},
+		{"overwritten", []Target{html1}, 2, nil, fmt.Errorf("1 html outputs for 2 plan files, must be one per plan file or a single shared one")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assigned, err := AssignTargets(test.targets, test.plans)
+			if !reflect.DeepEqual(err, test.err) || !reflect.DeepEqual(assigned, test.assigned) {
+				t.Errorf("AssignTargets(%+v, %d) = %+v, %v; want %+v, %v", test.targets, test.plans, assigned, err, test.assigned, test.err)
+			}
+		})
+	}
+}
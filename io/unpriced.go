@@ -0,0 +1,62 @@
+package io
+
+import (
+	"fmt"
+
+	"github.com/googleinterns/terraform-cost-estimation/io/js"
+	"github.com/googleinterns/terraform-cost-estimation/io/web"
+	"github.com/googleinterns/terraform-cost-estimation/resources"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// coverageString returns the sentence giving the coverage of the priced resource changes.
+func coverageString(states []resources.ResourceState, unpriced []*resources.ResourceError) string {
+	s := fmt.Sprintf("Coverage: %.1f%% of the resource changes are priced", resources.Coverage(states, unpriced))
+	if len(unpriced) > 0 {
+		s += fmt.Sprintf(", %d unpriced resources are missing from the totals", len(unpriced))
+	}
+	return s + "."
+}
+
+// snapshotString returns the sentence giving the catalog snapshot the resources are priced with, if any.
+func snapshotString(snapshot string) string {
+	if snapshot == "" {
+		return ""
+	}
+	return "\nPrices of the catalog snapshot of " + snapshot + "."
+}
+
+// dataVersionString returns the sentence giving the version of the class detail data the resources are priced with, if set.
+func dataVersionString(version string) string {
+	if version == "" {
+		return ""
+	}
+	return "\nClass detail data " + version + "."
+}
+
+func unpricedOut(unpriced []*resources.ResourceError) []js.UnpricedOut {
+	out := []js.UnpricedOut{}
+	for _, e := range unpriced {
+		out = append(out, js.UnpricedOut{Address: e.Address, Type: e.Type, Reason: string(e.Kind), Error: e.Err.Error()})
+	}
+	return out
+}
+
+func unpricedRows(unpriced []*resources.ResourceError) (rows []web.UnpricedRow) {
+	for _, e := range unpriced {
+		rows = append(rows, web.UnpricedRow{Address: e.Address, Type: e.Type, Reason: string(e.Kind), Error: e.Err.Error()})
+	}
+	return
+}
+
+// GetUnpricedTable returns the table with the resource changes that could not be priced and why.
+func GetUnpricedTable(unpriced []*resources.ResourceError) *table.Table {
+	t := &table.Table{}
+	t.SetTitle("Unpriced resources")
+	t.AppendHeader(table.Row{"Address", "Type", "Reason", "Error"})
+	for _, e := range unpriced {
+		t.AppendRow(table.Row{e.Address, e.Type, e.Kind, e.Err.Error()})
+	}
+	t.SetStyle(table.StyleLight)
+	return t
+}
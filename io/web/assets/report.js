@@ -0,0 +1,195 @@
+// Sorting, filtering, period selection and the cost chart of the report.
+// The resources and their costs per period are given by the page in the report variable,
+// with the plan files of an aggregate report.
+(function () {
+    var resources = report.resources || [];
+    var plans = report.plans || [];
+    var units = {hourly: "hour", monthly: "month", yearly: "year"};
+    var period = "hourly";
+    var form = document.getElementById("filters");
+    var list = document.getElementById("resources");
+    var elements = {};
+    list.querySelectorAll(".resource").forEach(function (e) {
+        elements[e.dataset.index] = e;
+    });
+
+    function unit() {
+        return report.currency + "/" + units[period];
+    }
+
+    function format(x) {
+        return x.toFixed(6) + " " + unit();
+    }
+
+    // The plan filter is only shown in aggregate reports.
+    var fields = ["action", "kind", "module"];
+    if (form.elements.plan) {
+        fields.unshift("plan");
+    }
+
+    // Fill the filter options with the distinct values of the resources.
+    fields.forEach(function (field) {
+        var values = [];
+        resources.forEach(function (r) {
+            if (values.indexOf(r[field]) < 0) {
+                values.push(r[field]);
+            }
+        });
+        values.sort().forEach(function (v) {
+            form.elements[field].add(new Option(v || "unknown", v));
+        });
+    });
+
+    function visible() {
+        var name = form.elements.name.value.toLowerCase();
+        return resources.filter(function (r) {
+            return fields.every(function (field) {
+                return !form.elements[field].value || form.elements[field].value === r[field];
+            }) && r.name.toLowerCase().indexOf(name) >= 0;
+        });
+    }
+
+    function sortKey(r, key) {
+        switch (key) {
+        case "delta":
+        case "after":
+            return r.costs[period][key];
+        default:
+            return r[key];
+        }
+    }
+
+    function sorted(shown) {
+        var key = form.elements.sort.value;
+        var sign = form.elements.descending.checked ? -1 : 1;
+        return shown.slice().sort(function (a, b) {
+            var x = sortKey(a, key), y = sortKey(b, key);
+            if (x < y) {
+                return -sign;
+            }
+            if (x > y) {
+                return sign;
+            }
+            return a.index - b.index;
+        });
+    }
+
+    // drawChart draws the before and after total cost of the shown resources by type.
+    function drawChart(shown) {
+        var svg = document.getElementById("chart");
+        var ns = "http://www.w3.org/2000/svg";
+        var totals = {};
+        var kinds = [];
+        shown.forEach(function (r) {
+            var kind = r.kind || "unknown";
+            if (!totals[kind]) {
+                totals[kind] = {before: 0, after: 0};
+                kinds.push(kind);
+            }
+            totals[kind].before += r.costs[period].before;
+            totals[kind].after += r.costs[period].after;
+        });
+
+        var max = 0;
+        kinds.forEach(function (k) {
+            max = Math.max(max, totals[k].before, totals[k].after);
+        });
+
+        var row = 36, label = 160, width = 800;
+        svg.innerHTML = "";
+        svg.setAttribute("viewBox", "0 0 " + width + " " + Math.max(row * kinds.length, row));
+        kinds.forEach(function (k, i) {
+            var text = document.createElementNS(ns, "text");
+            text.setAttribute("x", 0);
+            text.setAttribute("y", i * row + row / 2 + 4);
+            text.textContent = k;
+            svg.appendChild(text);
+
+            ["before", "after"].forEach(function (side, j) {
+                var w = max > 0 ? (width - label - 160) * totals[k][side] / max : 0;
+                var bar = document.createElementNS(ns, "rect");
+                bar.setAttribute("class", side);
+                bar.setAttribute("x", label);
+                bar.setAttribute("y", i * row + 4 + j * 14);
+                bar.setAttribute("width", w);
+                bar.setAttribute("height", 12);
+                svg.appendChild(bar);
+
+                var value = document.createElementNS(ns, "text");
+                value.setAttribute("x", label + w + 4);
+                value.setAttribute("y", i * row + 14 + j * 14);
+                value.textContent = totals[k][side].toFixed(2);
+                svg.appendChild(value);
+            });
+        });
+        document.querySelector(".chart .unit").textContent = unit();
+    }
+
+    // drawPlans fills the plans table with the cost change of the shown resources of each plan and their total.
+    // The coverage of all the plans is given in the summary.
+    function drawPlans(shown) {
+        var body = document.getElementById("plans");
+        if (!body) {
+            return;
+        }
+        var deltas = {};
+        var total = 0;
+        shown.forEach(function (r) {
+            deltas[r.plan] = (deltas[r.plan] || 0) + r.costs[period].delta;
+            total += r.costs[period].delta;
+        });
+
+        body.innerHTML = "";
+        var count = 0, unpriced = 0;
+
+        function addRow(cells, className) {
+            var tr = body.insertRow();
+            tr.className = className || "";
+            cells.forEach(function (c) {
+                tr.insertCell().textContent = c;
+            });
+        }
+        plans.forEach(function (p) {
+            count += p.resources;
+            unpriced += p.unpriced;
+            addRow([p.name, p.resources, p.unpriced, p.coverage.toFixed(1) + "%", format(deltas[p.name] || 0)]);
+        });
+        addRow(["Total", count, unpriced, "", format(total)], "total");
+    }
+
+    function update() {
+        var shown = visible();
+        var delta = 0;
+        Object.keys(elements).forEach(function (i) {
+            elements[i].hidden = true;
+        });
+        sorted(shown).forEach(function (r) {
+            var e = elements[r.index];
+            e.hidden = false;
+            list.appendChild(e);
+            delta += r.costs[period].delta;
+        });
+        list.querySelectorAll(".period").forEach(function (e) {
+            e.hidden = e.dataset.period !== period;
+        });
+        document.querySelectorAll(".periods button").forEach(function (b) {
+            b.classList.toggle("active", b.dataset.period === period);
+        });
+        document.getElementById("total-delta").textContent = format(delta);
+        drawChart(shown);
+        drawPlans(shown);
+    }
+
+    document.querySelectorAll(".periods button").forEach(function (b) {
+        b.addEventListener("click", function () {
+            period = b.dataset.period;
+            update();
+        });
+    });
+    form.addEventListener("input", update);
+    form.addEventListener("change", update);
+    form.addEventListener("submit", function (e) {
+        e.preventDefault();
+    });
+    update();
+})();
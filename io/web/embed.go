@@ -0,0 +1,33 @@
+package web
+
+import (
+	"embed"
+	"html/template"
+)
+
+// files holds the page template and the stylesheet and script inlined in it,
+// so that the binary works anywhere and the page is a single self-contained file.
+//
+//go:embed web_template.gohtml assets
+var files embed.FS
+
+// ParseTemplate returns the template of the web page.
+// The assets are inlined with {{css "name"}} and {{js "name"}}.
+func ParseTemplate() (*template.Template, error) {
+	asset := func(name string) (string, error) {
+		b, err := files.ReadFile("assets/" + name)
+		return string(b), err
+	}
+
+	funcs := template.FuncMap{
+		"css": func(name string) (template.CSS, error) {
+			s, err := asset(name)
+			return template.CSS(s), err
+		},
+		"js": func(name string) (template.JS, error) {
+			s, err := asset(name)
+			return template.JS(s), err
+		},
+	}
+	return template.New("web_template.gohtml").Funcs(funcs).ParseFS(files, "web_template.gohtml")
+}
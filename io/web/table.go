@@ -33,6 +33,7 @@ func (t *Table) AddComputeInstanceGeneralInfo(name, ID, action, machineType, zon
 }
 
 // AddComputeInstancePricing fills the table with the pricing information section for all billing components.
+// The price unit is the currency and period of the costs, e.g. USD/hour.
 func (t *Table) AddComputeInstancePricing(priceUnit string, cpuCostPerUnit1, cpuCostPerUnit2 float64, cpuUnits1, cpuUnits2 int,
 	memCostPerUnit1, memCostPerUnit2, memUnits1, memUnits2 float64) {
 
@@ -43,7 +44,7 @@ func (t *Table) AddComputeInstancePricing(priceUnit string, cpuCostPerUnit1, cpu
 	dCPU := cpuTot2 - cpuTot1
 	dMem := memTot2 - memTot1
 
-	f1 := func(x float64) string { return fmt.Sprintf("%.6f USD/%s", x, priceUnit) }
+	f1 := func(x float64) string { return fmt.Sprintf("%.6f %s", x, priceUnit) }
 	f2 := func(x float64) string { return fmt.Sprintf("%.2f", x) }
 	f3 := func(x int) string { return fmt.Sprintf("%d", x) }
 
@@ -68,8 +69,9 @@ func (t *Table) AddComputeDiskGeneralInfo(name, id, action, diskType, zones, ima
 }
 
 // AddComputeDiskPricing fills the table with the pricing information section for all billing components.
+// The price unit is the currency and period of the costs, e.g. USD/hour.
 func (t *Table) AddComputeDiskPricing(priceUnit string, costPerUnit1, costPerUnit2 float64, units1, units2 int64, delta float64) {
-	f1 := func(x float64) string { return fmt.Sprintf("%.6f USD/%s", x, priceUnit) }
+	f1 := func(x float64) string { return fmt.Sprintf("%.6f %s", x, priceUnit) }
 	f2 := func(x int64) string { return fmt.Sprintf("%d", x) }
 
 	tot1 := costPerUnit1 * float64(units1)
@@ -80,3 +82,107 @@ func (t *Table) AddComputeDiskPricing(priceUnit string, costPerUnit1, costPerUni
 	}
 	t.Total = [3]string{f1(tot1), f1(tot2), f1(delta)}
 }
+
+// ComponentPricing holds the before and after pricing of a single billing component of a resource.
+type ComponentPricing struct {
+	Name         string
+	CostPerUnit1 float64
+	Units1       float64
+	CostPerUnit2 float64
+	Units2       float64
+}
+
+// AddGeneralInfo fills the table with the name and the general information rows about the resource change.
+func (t *Table) AddGeneralInfo(name string, rows [][2]string) {
+	t.Header = [2]string{"Name", name}
+	t.GeneralRows = rows
+}
+
+// AddComponentsPricing fills the table with the pricing information section for the given billing components.
+// The price unit is the currency and period of the costs, e.g. USD/hour.
+func (t *Table) AddComponentsPricing(priceUnit string, components []ComponentPricing) {
+	f1 := func(x float64) string { return fmt.Sprintf("%.6f %s", x, priceUnit) }
+	f2 := func(x float64) string { return fmt.Sprintf("%.2f", x) }
+
+	var tot1, tot2 float64
+	t.PricingInfo = nil
+	for _, c := range components {
+		cTot1 := c.CostPerUnit1 * c.Units1
+		cTot2 := c.CostPerUnit2 * c.Units2
+		tot1 += cTot1
+		tot2 += cTot2
+		t.PricingInfo = append(t.PricingInfo, [8]string{c.Name, f1(c.CostPerUnit1), f2(c.Units1), f1(cTot1),
+			f1(c.CostPerUnit2), f2(c.Units2), f1(cTot2), f1(cTot2 - cTot1)})
+	}
+	t.Total = [3]string{f1(tot1), f1(tot2), f1(tot2 - tot1)}
+}
+
+// Costs holds the before and after total cost of a resource over a period and their difference.
+type Costs struct {
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+	Delta  float64 `json:"delta"`
+}
+
+// Resource holds the pricing tables of a resource change and the fields it can be sorted and filtered by.
+// The module is the address of the Terraform module of the resource ("root" for the root module)
+// and the plan is its plan file in an aggregate report.
+type Resource struct {
+	Index  int                `json:"index"`
+	Plan   string             `json:"plan,omitempty"`
+	Name   string             `json:"name"`
+	Kind   string             `json:"kind"`
+	Action string             `json:"action"`
+	Module string             `json:"module"`
+	Costs  map[string]Costs   `json:"costs"`
+	Tables *PricingTypeTables `json:"-"`
+}
+
+// Page holds the pricing tables of all the resources, the currency of their costs, the coverage
+// of the priced resource changes, the unpriced ones and the rows of the explain section, if any.
+// The plans are the plan files of an aggregate report, whose subtotals are shown in a section of their own,
+// the snapshot is the date of the catalog snapshot the resources are priced with, if any, and the data version
+// the version of the machine type, disk type and image details.
+type Page struct {
+	Currency    string
+	Snapshot    string
+	DataVersion string
+	Resources   []Resource
+	Coverage    string
+	Unpriced    []UnpricedRow
+	Plans       []PlanRow
+	Explain     []ExplainRow
+}
+
+// PlanRow holds a plan file of an aggregate report, shown with the cost change of its resources in the plans section.
+type PlanRow struct {
+	Name      string  `json:"name"`
+	Resources int     `json:"resources"`
+	Unpriced  int     `json:"unpriced"`
+	Coverage  float64 `json:"coverage"`
+}
+
+// UnpricedRow holds a resource change that could not be priced, shown in the unpriced resources section.
+// Plan is its plan file in an aggregate report.
+type UnpricedRow struct {
+	Plan    string
+	Address string
+	Type    string
+	Reason  string
+	Error   string
+}
+
+// ExplainRow holds the SKU match of a billing component, shown in the explain section.
+type ExplainRow struct {
+	Resource    string
+	Component   string
+	State       string
+	SKUID       string
+	Description string
+	Regions     string
+	UsageType   string
+	Tier        string
+	Contains    string
+	Omits       string
+	Rejected    []string
+}
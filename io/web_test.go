@@ -0,0 +1,56 @@
+package io
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/googleinterns/terraform-cost-estimation/resources"
+)
+
+func TestAddressModule(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		expected string
+	}{
+		{"root", "google_compute_instance.vm", "root"},
+		{"module", "module.net.google_compute_address.ip", "module.net"},
+		{"nested_module", "module.a.module.b[\"x\"].google_compute_disk.d", "module.a.module.b[\"x\"]"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := addressModule(test.address); actual != test.expected {
+				t.Errorf("addressModule(%q) = %q, want %q", test.address, actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateWebPage(t *testing.T) {
+	month, _ := resources.Month(resources.Month730h)
+	pricing := resources.PricingInfo{UsageUnit: "hour", HourlyUnitPrice: 0.5, CurrencyType: "EUR"}
+	address := &resources.Address{Name: "ip", Region: "europe-west1", Global: true, NetworkTier: "PREMIUM", UnusedPricing: pricing}
+	state := &resources.AddressState{After: address, Action: "create"}
+	state.AddAddress("module.net.google_compute_global_address.ip")
+
+	report, err := NewReport([]resources.ResourceState{state}, nil, resources.Hour, month)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := GenerateWebPage(&b, report); err != nil {
+		t.Fatal(err)
+	}
+	page := b.String()
+	for _, s := range []string{"Coverage: 100.0%", `"module":"module.net"`, `"kind":"compute_address"`, `"monthly":{"before":0,"after":365,"delta":365}`, "drawChart"} {
+		if !strings.Contains(page, s) {
+			t.Errorf("GenerateWebPage() output does not contain %s", s)
+		}
+	}
+	for _, s := range []string{`src="http`, `href="http`} {
+		if strings.Contains(page, s) {
+			t.Errorf("GenerateWebPage() output loads external resource %s", s)
+		}
+	}
+}
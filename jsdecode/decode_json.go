@@ -5,17 +5,27 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
+	"reflect"
+	"strings"
 
+	"github.com/googleinterns/terraform-cost-estimation/registry"
 	resources "github.com/googleinterns/terraform-cost-estimation/resources"
 	cd "github.com/googleinterns/terraform-cost-estimation/resources/classdetail"
+	"github.com/googleinterns/terraform-cost-estimation/usage"
 	tfjson "github.com/hashicorp/terraform-json"
 )
 
-// ComputeInstanceType and ComputeDiskType are the supported by this package types of ResourceChange and Resource.
+// The resource types supported by this package for ResourceChange and Resource.
 const (
 	ComputeDiskType     = "google_compute_disk"
 	ComputeInstanceType = "google_compute_instance"
+	SQLInstanceType     = "google_sql_database_instance"
+	StorageBucketType   = "google_storage_bucket"
+	ClusterType         = "google_container_cluster"
+	NodePoolType        = "google_container_node_pool"
+	AddressType         = "google_compute_address"
+	GlobalAddressType   = "google_compute_global_address"
+	RouterNATType       = "google_compute_router_nat"
 )
 
 // Possible actions in resource changes.
@@ -46,6 +56,100 @@ type UsageType struct {
 	IsPreemptible bool `json:"preemptible,omitempty"`
 }
 
+// SQLInstanceInfo contains the information about a Cloud SQL database instance in json plan file.
+type SQLInstanceInfo struct {
+	Name               string        `json:"name,omitempty"`
+	ID                 string        `json:"id,omitempty"`
+	DatabaseVersion    string        `json:"database_version,omitempty"`
+	Region             string        `json:"region,omitempty"`
+	MasterInstanceName string        `json:"master_instance_name,omitempty"`
+	Settings           []SQLSettings `json:"settings,omitempty"`
+}
+
+// SQLSettings contains the settings of a Cloud SQL database instance that affect its pricing.
+type SQLSettings struct {
+	Tier             string `json:"tier,omitempty"`
+	AvailabilityType string `json:"availability_type,omitempty"`
+	DiskType         string `json:"disk_type,omitempty"`
+	DiskSizeGiB      int64  `json:"disk_size,omitempty"`
+}
+
+// StorageBucketInfo contains the information about a Cloud Storage bucket in json plan file.
+type StorageBucketInfo struct {
+	Name          string          `json:"name,omitempty"`
+	ID            string          `json:"id,omitempty"`
+	Location      string          `json:"location,omitempty"`
+	StorageClass  string          `json:"storage_class,omitempty"`
+	LifecycleRule []LifecycleRule `json:"lifecycle_rule,omitempty"`
+}
+
+// LifecycleRule contains a lifecycle rule of a Cloud Storage bucket.
+type LifecycleRule struct {
+	Action []struct {
+		Type         string `json:"type,omitempty"`
+		StorageClass string `json:"storage_class,omitempty"`
+	} `json:"action,omitempty"`
+	Condition []struct {
+		Age int `json:"age,omitempty"`
+	} `json:"condition,omitempty"`
+}
+
+// NodeConfigInfo contains the configuration of the nodes of a GKE cluster or node pool in json plan file.
+type NodeConfigInfo struct {
+	MachineType string `json:"machine_type,omitempty"`
+	DiskType    string `json:"disk_type,omitempty"`
+	DiskSizeGiB int64  `json:"disk_size_gb,omitempty"`
+	Preemptible bool   `json:"preemptible,omitempty"`
+	Spot        bool   `json:"spot,omitempty"`
+}
+
+// NodePoolInfo contains the information about a GKE node pool in json plan file.
+// It is used both for google_container_node_pool resources and node_pool blocks of clusters.
+type NodePoolInfo struct {
+	Name             string   `json:"name,omitempty"`
+	Cluster          string   `json:"cluster,omitempty"`
+	Location         string   `json:"location,omitempty"`
+	NodeLocations    []string `json:"node_locations,omitempty"`
+	NodeCount        int      `json:"node_count,omitempty"`
+	InitialNodeCount int      `json:"initial_node_count,omitempty"`
+	Autoscaling      []struct {
+		MinNodeCount int `json:"min_node_count,omitempty"`
+		MaxNodeCount int `json:"max_node_count,omitempty"`
+	} `json:"autoscaling,omitempty"`
+	NodeConfig []NodeConfigInfo `json:"node_config,omitempty"`
+}
+
+// ClusterInfo contains the information about a GKE cluster in json plan file.
+type ClusterInfo struct {
+	Name                  string           `json:"name,omitempty"`
+	ID                    string           `json:"id,omitempty"`
+	Location              string           `json:"location,omitempty"`
+	EnableAutopilot       bool             `json:"enable_autopilot,omitempty"`
+	NodeLocations         []string         `json:"node_locations,omitempty"`
+	InitialNodeCount      int              `json:"initial_node_count,omitempty"`
+	RemoveDefaultNodePool bool             `json:"remove_default_node_pool,omitempty"`
+	NodeConfig            []NodeConfigInfo `json:"node_config,omitempty"`
+	NodePool              []NodePoolInfo   `json:"node_pool,omitempty"`
+}
+
+// AddressInfo contains the information about a regional or global static IP address in json plan file.
+type AddressInfo struct {
+	Name        string   `json:"name,omitempty"`
+	ID          string   `json:"id,omitempty"`
+	Address     string   `json:"address,omitempty"`
+	AddressType string   `json:"address_type,omitempty"`
+	Region      string   `json:"region,omitempty"`
+	NetworkTier string   `json:"network_tier,omitempty"`
+	Users       []string `json:"users,omitempty"`
+}
+
+// RouterNATInfo contains the information about a Cloud NAT gateway in json plan file.
+type RouterNATInfo struct {
+	Name   string `json:"name,omitempty"`
+	Router string `json:"router,omitempty"`
+	Region string `json:"region,omitempty"`
+}
+
 // ExtractPlanStruct extracts tfjson.Plan struct from file in provided path if it is possible.
 func ExtractPlanStruct(reader io.Reader) (*tfjson.Plan, error) {
 	bytes, err := ioutil.ReadAll(reader)
@@ -102,55 +206,234 @@ func toComputeDisk(details *cd.ResourceDetail, resource interface{}) (*resources
 	return resources.NewComputeDisk(details, r.Name, r.InstanceID, r.DiskType, zones, r.Image, r.Snapshot, r.SizeGiB)
 }
 
-// toInstanceState returns the pointer to the struct with states of the certain resource of ComputeInstance type.
-func toInstanceState(details *cd.ResourceDetail, change *tfjson.Change) (*resources.ComputeInstanceState, error) {
-	before, err := toComputeInstance(details, change.Before)
+// toSQLInstance extracts SQLInstance from the interface that contains information about the resource.
+func toSQLInstance(details *cd.ResourceDetail, resource interface{}) (*resources.SQLInstance, error) {
+	if resource == nil {
+		return nil, nil
+	}
+
+	jsonString, err := json.Marshal(resource)
 	if err != nil {
 		return nil, err
 	}
-	after, err := toComputeInstance(details, change.After)
+
+	var r *SQLInstanceInfo
+	if err := json.Unmarshal(jsonString, &r); err != nil || r == nil {
+		return nil, err
+	}
+
+	if len(r.Settings) == 0 {
+		return nil, fmt.Errorf("no settings specified for database instance %s", r.Name)
+	}
+	s := r.Settings[0]
+
+	return resources.NewSQLInstance(details, r.Name, r.ID, r.DatabaseVersion, s.Tier, r.Region, s.AvailabilityType,
+		r.MasterInstanceName, s.DiskType, s.DiskSizeGiB)
+}
+
+// toStorageBucket extracts StorageBucket from the interface that contains information about the resource.
+func toStorageBucket(u usage.Values, resource interface{}) (*resources.StorageBucket, error) {
+	if resource == nil {
+		return nil, nil
+	}
+
+	jsonString, err := json.Marshal(resource)
 	if err != nil {
 		return nil, err
 	}
-	if before == nil && after == nil {
+
+	var r *StorageBucketInfo
+	if err := json.Unmarshal(jsonString, &r); err != nil || r == nil {
+		return nil, err
+	}
+
+	var rules []resources.LifecycleRule
+	for _, l := range r.LifecycleRule {
+		// Only rules conditioned by the object age can be reflected in the estimation.
+		if len(l.Action) == 0 || len(l.Condition) == 0 || l.Condition[0].Age <= 0 {
+			continue
+		}
+		rules = append(rules, resources.LifecycleRule{Action: l.Action[0].Type, StorageClass: l.Action[0].StorageClass,
+			AgeDays: l.Condition[0].Age})
+	}
+
+	return resources.NewStorageBucket(r.Name, r.ID, r.Location, r.StorageClass, rules, u)
+}
+
+func toNodeConfig(info []NodeConfigInfo) resources.NodeConfig {
+	if len(info) == 0 {
+		return resources.NodeConfig{}
+	}
+	c := info[0]
+	return resources.NodeConfig{MachineType: c.MachineType, DiskType: c.DiskType, DiskSizeGiB: c.DiskSizeGiB,
+		Preemptible: c.Preemptible || c.Spot}
+}
+
+// newNodePool builds a NodePool from its json information, the cluster location being used if the pool has none.
+func newNodePool(details *cd.ResourceDetail, u usage.Values, p NodePoolInfo, location string,
+	nodeLocations []string) (*resources.NodePool, error) {
+
+	if p.Location != "" {
+		location = p.Location
+	}
+	if len(p.NodeLocations) > 0 {
+		nodeLocations = p.NodeLocations
+	}
+
+	nodeCount := p.NodeCount
+	if nodeCount == 0 {
+		nodeCount = p.InitialNodeCount
+	}
+
+	autoscaling := len(p.Autoscaling) > 0
+	var min, max int
+	if autoscaling {
+		min, max = p.Autoscaling[0].MinNodeCount, p.Autoscaling[0].MaxNodeCount
+	}
+
+	return resources.NewNodePool(details, p.Name, p.Cluster, location, nodeLocations, nodeCount, autoscaling, min, max,
+		toNodeConfig(p.NodeConfig), u)
+}
+
+// toNodePool extracts NodePool from the interface that contains information about the resource.
+func toNodePool(details *cd.ResourceDetail, u usage.Values, resource interface{}) (*resources.NodePool, error) {
+	if resource == nil {
 		return nil, nil
 	}
 
-	action, err := initAction(change.Actions)
+	jsonString, err := json.Marshal(resource)
 	if err != nil {
 		return nil, err
 	}
-	return &resources.ComputeInstanceState{
-		Before: before,
-		After:  after,
-		Action: action,
-	}, nil
+
+	var r *NodePoolInfo
+	if err := json.Unmarshal(jsonString, &r); err != nil || r == nil {
+		return nil, err
+	}
+
+	return newNodePool(details, u, *r, "", nil)
+}
+
+// separateNodePools returns the node pools of the plan managed as google_container_node_pool resources,
+// in their before and after states.
+func separateNodePools(plan *tfjson.Plan) []NodePoolInfo {
+	if plan == nil {
+		return nil
+	}
+
+	var pools []NodePoolInfo
+	for _, rc := range plan.ResourceChanges {
+		if rc.Type != NodePoolType || rc.Change == nil {
+			continue
+		}
+		for _, v := range []interface{}{rc.Change.Before, rc.Change.After} {
+			jsonString, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+			var p *NodePoolInfo
+			if err := json.Unmarshal(jsonString, &p); err == nil && p != nil && p.Name != "" {
+				pools = append(pools, *p)
+			}
+		}
+	}
+	return pools
+}
+
+// managedSeparately reports whether the node pool of the cluster is one of the separately managed pools:
+// a pool with the same name whose cluster is the cluster name or ID, or unknown.
+func managedSeparately(pool NodePoolInfo, cluster string, separate []NodePoolInfo) bool {
+	for _, s := range separate {
+		if s.Name == pool.Name && (s.Cluster == "" || s.Cluster == cluster || strings.HasSuffix(s.Cluster, "/clusters/"+cluster)) {
+			return true
+		}
+	}
+	return false
 }
 
-// toDiskState returns the pointer to the struct with states of the certain
-// resource of ComputeInstance type.
-func toDiskState(details *cd.ResourceDetail, change *tfjson.Change) (*resources.ComputeDiskState, error) {
-	before, err := toComputeDisk(details, change.Before)
+// toKubernetesCluster extracts KubernetesCluster from the interface that contains information about the resource.
+// The default node pool is included unless it is removed or the cluster has node_pool blocks.
+// The node_pool attribute of an existing cluster also lists the pools managed as separate google_container_node_pool
+// resources, which are priced on their own and left out of the cluster.
+func toKubernetesCluster(details *cd.ResourceDetail, u usage.Values, resource interface{},
+	separate []NodePoolInfo) (*resources.KubernetesCluster, error) {
+	if resource == nil {
+		return nil, nil
+	}
+
+	jsonString, err := json.Marshal(resource)
 	if err != nil {
 		return nil, err
 	}
-	after, err := toComputeDisk(details, change.After)
+
+	var r *ClusterInfo
+	if err := json.Unmarshal(jsonString, &r); err != nil || r == nil {
+		return nil, err
+	}
+
+	var pools []*resources.NodePool
+	if !r.EnableAutopilot {
+		infos := r.NodePool
+		if len(infos) == 0 && !r.RemoveDefaultNodePool && r.InitialNodeCount > 0 {
+			infos = []NodePoolInfo{{Name: "default-pool", NodeCount: r.InitialNodeCount, NodeConfig: r.NodeConfig}}
+		}
+
+		for _, info := range infos {
+			if managedSeparately(info, r.Name, separate) {
+				continue
+			}
+			info.Cluster = r.Name
+			p, err := newNodePool(details, u, info, r.Location, r.NodeLocations)
+			if err != nil {
+				return nil, err
+			}
+			pools = append(pools, p)
+		}
+	}
+
+	return resources.NewKubernetesCluster(r.Name, r.ID, r.Location, r.EnableAutopilot, pools, u)
+}
+
+// toAddress extracts Address from the interface that contains information about the resource.
+// Internal addresses are free of charge, so nil is returned for them.
+func toAddress(u usage.Values, global bool, resource interface{}) (*resources.Address, error) {
+	if resource == nil {
+		return nil, nil
+	}
+
+	jsonString, err := json.Marshal(resource)
 	if err != nil {
 		return nil, err
 	}
-	if before == nil && after == nil {
+
+	var r *AddressInfo
+	if err := json.Unmarshal(jsonString, &r); err != nil || r == nil {
+		return nil, err
+	}
+	if r.AddressType == "INTERNAL" {
 		return nil, nil
 	}
 
-	action, err := initAction(change.Actions)
+	return resources.NewAddress(r.Name, r.ID, r.Address, r.Region, r.NetworkTier, global, r.Users, u)
+}
+
+// toRouterNAT extracts RouterNAT from the interface that contains information about the resource.
+func toRouterNAT(u usage.Values, resource interface{}) (*resources.RouterNAT, error) {
+	if resource == nil {
+		return nil, nil
+	}
+
+	jsonString, err := json.Marshal(resource)
 	if err != nil {
 		return nil, err
 	}
-	return &resources.ComputeDiskState{
-		Before: before,
-		After:  after,
-		Action: action,
-	}, nil
+
+	var r *RouterNATInfo
+	if err := json.Unmarshal(jsonString, &r); err != nil || r == nil {
+		return nil, err
+	}
+
+	return resources.NewRouterNAT(r.Name, r.Router, r.Region, u)
 }
 
 // initAction extracts an action in the change.
@@ -173,26 +456,125 @@ func initAction(actions tfjson.Actions) (string, error) {
 	return action, nil
 }
 
-// GetResources extracts all resources of ComputeInstance and ComputeDisk type and their before and after states from plan file.
-func GetResources(details *cd.ResourceDetail, plan *tfjson.Plan) []resources.ResourceState {
+// toState decodes the before and after values of the resource change with the pricer and builds its state.
+// Nil is returned if neither value is priced.
+func toState(p *registry.Pricer, ctx *registry.Context, change *tfjson.Change) (resources.ResourceState, error) {
+	before, err := p.Decode(ctx, change.Before)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := p.Decode(ctx, change.After)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNil(before) && isNil(after) {
+		return nil, nil
+	}
+
+	action, err := initAction(change.Actions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the resources that would be applied are checked, the existing ones are priced as they are.
+	if p.Check != nil && !isNil(after) && (action == ActionCreate || action == ActionUpdate || action == ActionReplace) {
+		if err := p.Check(ctx, after); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.NewState(before, after, action), nil
+}
+
+// isNil returns whether the decoded value is nil, including nil pointers converted to interface{}.
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	r := reflect.ValueOf(v)
+	return r.Kind() == reflect.Ptr && r.IsNil()
+}
+
+// isUnchanged returns whether the change is a no-op of an existing resource. The resources of a state
+// (no-op changes from nothing) are not left unchanged, since their cost is estimated.
+func isUnchanged(change *tfjson.Change) bool {
+	return change != nil && change.Actions.NoOp() && change.Before != nil
+}
+
+// CatalogDependencies returns the billing catalogs needed to price the resources of the plan file.
+func CatalogDependencies(plan *tfjson.Plan) []string {
+	var types []string
+	for _, c := range plan.ResourceChanges {
+		types = append(types, c.Type)
+	}
+	return registry.Catalogs(types)
+}
+
+// GetResources extracts all resources of the registered types and their before and after states from plan file.
+// Usage assumptions are used for the resources priced by usage (e.g. storage buckets) and can be nil.
+// The resource changes that could not be decoded (including those of unsupported types) are returned as errors
+// next to the states, in plan order. Data sources, resources of free types (see registry.RegisterFree) and
+// unchanged resources of unsupported types are not priced and left out.
+func GetResources(details *cd.ResourceDetail, assumptions *usage.Assumptions,
+	plan *tfjson.Plan) ([]resources.ResourceState, []*resources.ResourceError) {
 	var states []resources.ResourceState
-	var r resources.ResourceState
-	var err error
+	var errs []*resources.ResourceError
+	var groupers []registry.Grouper
+	groups := map[*registry.Pricer]registry.Grouper{}
+
 	for _, resourceChange := range plan.ResourceChanges {
-		switch resourceChange.Type {
-		case ComputeInstanceType:
-			r, err = toInstanceState(details, resourceChange.Change)
-		case ComputeDiskType:
-			r, err = toDiskState(details, resourceChange.Change)
-		default:
-			log.Printf("Unsupported resource type: %v", resourceChange.Type)
+		if resourceChange.Mode == tfjson.DataResourceMode {
+			continue
 		}
+
+		p, ok := registry.Lookup(resourceChange.Type)
+		if !ok {
+			// Free resources and the resources left unchanged by the plan don't change the cost.
+			if registry.IsFree(resourceChange.Type) || isUnchanged(resourceChange.Change) {
+				continue
+			}
+			err := fmt.Errorf("%w '%s'", resources.ErrUnsupportedType, resourceChange.Type)
+			errs = append(errs, resources.NewResourceError(resourceChange.Address, resourceChange.Type, resources.KindUnsupportedType, err))
+			continue
+		}
+
+		ctx := &registry.Context{
+			Details: details,
+			Usage:   assumptions.Get(resourceChange.Address, resourceChange.Type),
+			Address: resourceChange.Address,
+			Plan:    plan,
+		}
+
+		if p.NewGrouper != nil {
+			g, ok := groups[p]
+			if !ok {
+				g = p.NewGrouper(plan)
+				groups[p] = g
+				groupers = append(groupers, g)
+			}
+			if err := g.Add(ctx, resourceChange); err != nil {
+				errs = append(errs, resources.NewResourceError(resourceChange.Address, resourceChange.Type, resources.KindInvalidConfig, err))
+			}
+			continue
+		}
+
+		r, err := toState(p, ctx, resourceChange.Change)
 		if err != nil {
-			log.Printf("Error: %v", err)
+			errs = append(errs, resources.NewResourceError(resourceChange.Address, resourceChange.Type, resources.KindInvalidConfig, err))
 		} else if r != nil {
+			if a, ok := r.(resources.Addressable); ok {
+				a.AddAddress(resourceChange.Address)
+			}
 			states = append(states, r)
 		}
-		r, err = nil, nil
 	}
-	return states
+
+	for _, g := range groupers {
+		s, e := g.States()
+		states = append(states, s...)
+		errs = append(errs, e...)
+	}
+	return states, errs
 }
@@ -8,12 +8,14 @@ import (
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/googleinterns/terraform-cost-estimation/registry"
 	resources "github.com/googleinterns/terraform-cost-estimation/resources"
 	cd "github.com/googleinterns/terraform-cost-estimation/resources/classdetail"
+	"github.com/googleinterns/terraform-cost-estimation/usage"
 	tfjson "github.com/hashicorp/terraform-json"
 )
 
-func TesttoComputeInstance(t *testing.T) {
+func TestToComputeInstance(t *testing.T) {
 	classDetails, err := cd.NewResourceDetail()
 	if err != nil {
 		t.Fatal(err.Error())
@@ -63,6 +65,10 @@ func TesttoComputeInstance(t *testing.T) {
 	}
 }
 
+func addresses(a ...string) resources.ResourceAddresses {
+	return resources.ResourceAddresses{Addresses: a}
+}
+
 func readResource(filePath string) (interface{}, error) {
 	f, err := os.Open(filePath)
 	if err != nil {
@@ -82,7 +88,7 @@ func readResource(filePath string) (interface{}, error) {
 	return res, nil
 }
 
-func TesttoInstanceState(t *testing.T) {
+func TestToInstanceState(t *testing.T) {
 	classDetails, err := cd.NewResourceDetail()
 	if err != nil {
 		t.Fatal(err.Error())
@@ -110,8 +116,8 @@ func TesttoInstanceState(t *testing.T) {
 		Action: "create",
 	}
 
-	var actual *resources.ComputeInstanceState
-	actual, err = toInstanceState(classDetails, plan.ResourceChanges[0].Change)
+	ctx := &registry.Context{Details: classDetails, Plan: plan}
+	actual, err := toState(computeInstancePricer, ctx, plan.ResourceChanges[0].Change)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -144,14 +150,243 @@ func TestGetResources(t *testing.T) {
 	after, _ := resources.NewComputeInstance(classDetails, "5889159656940809264", "test", "n1-standard-2", "us-central1-a", "OnDemand")
 	expected := []resources.ResourceState{
 		&resources.ComputeInstanceState{
-			Before: before,
-			After:  after,
-			Action: "update",
+			Before:            before,
+			After:             after,
+			Action:            "update",
+			ResourceAddresses: addresses("google_compute_instance.default"),
 		},
 	}
 
-	actual := GetResources(classDetails, plan)
+	actual, _ := GetResources(classDetails, nil, plan)
 	if !reflect.DeepEqual(expected, actual) {
 		t.Errorf("expected:\n\n%s\n\ngot:\n\n%s", spew.Sdump(expected), spew.Sdump(actual))
 	}
 }
+
+func TestGetResourcesSQLInstances(t *testing.T) {
+	classDetails, err := cd.NewResourceDetail()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	f, err := os.Open("../testdata/sql-instance/tfplan.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	plan, err := ExtractPlanStruct(f)
+	if err != nil || plan == nil {
+		t.Fatal(err)
+	}
+
+	primary, _ := resources.NewSQLInstance(classDetails, "test-primary", "", "POSTGRES_12", "db-custom-2-7680",
+		"us-central1", "REGIONAL", "", "PD_SSD", 100)
+	replica, _ := resources.NewSQLInstance(classDetails, "test-replica", "", "POSTGRES_12", "db-custom-2-7680",
+		"us-central1", "ZONAL", "test-primary", "PD_SSD", 100)
+	expected := []resources.ResourceState{
+		&resources.SQLInstanceState{After: primary, Action: "create",
+			ResourceAddresses: addresses("google_sql_database_instance.primary")},
+		&resources.SQLInstanceState{After: replica, Action: "create",
+			ResourceAddresses: addresses("google_sql_database_instance.replica")},
+	}
+
+	actual, _ := GetResources(classDetails, nil, plan)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected:\n\n%s\n\ngot:\n\n%s", spew.Sdump(expected), spew.Sdump(actual))
+	}
+}
+
+func TestGetResourcesClusters(t *testing.T) {
+	classDetails, err := cd.NewResourceDetail()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	pool := func(name string, nodes int) map[string]interface{} {
+		return map[string]interface{}{"name": name, "node_count": nodes,
+			"node_config": []interface{}{map[string]interface{}{"machine_type": "e2-medium"}}}
+	}
+	// The prior state of the cluster also lists the pool managed as a separate resource.
+	cluster := func(nodes int) map[string]interface{} {
+		return map[string]interface{}{"name": "test-cluster", "location": "us-central1-a",
+			"node_pool": []interface{}{pool("inline", nodes), pool("separate", 3)}}
+	}
+	separate := pool("separate", 3)
+	separate["cluster"] = "projects/p/locations/us-central1-a/clusters/test-cluster"
+	separate["location"] = "us-central1-a"
+	plan := &tfjson.Plan{ResourceChanges: []*tfjson.ResourceChange{
+		{Address: "google_container_cluster.gke", Mode: tfjson.ManagedResourceMode, Type: ClusterType,
+			Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionUpdate}, Before: cluster(1), After: cluster(2)}},
+		{Address: "google_container_node_pool.separate", Mode: tfjson.ManagedResourceMode, Type: NodePoolType,
+			Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionNoop}, Before: separate, After: separate}},
+	}}
+
+	actual, unpriced := GetResources(classDetails, nil, plan)
+	if len(actual) != 2 || len(unpriced) != 0 {
+		t.Fatalf("GetResources() = %s, %v; want the cluster and the node pool", spew.Sdump(actual), unpriced)
+	}
+	s, ok := actual[0].(*resources.KubernetesClusterState)
+	if !ok {
+		t.Fatalf("GetResources()[0] = %s, want the cluster", spew.Sdump(actual[0]))
+	}
+	for _, c := range []*resources.KubernetesCluster{s.Before, s.After} {
+		if len(c.NodePools) != 1 || c.NodePools[0].Name != "inline" {
+			t.Errorf("cluster node pools = %s, want only the inline pool", spew.Sdump(c.NodePools))
+		}
+	}
+	if p, ok := actual[1].(*resources.NodePoolState); !ok || p.After == nil || p.After.Name != "separate" {
+		t.Errorf("GetResources()[1] = %s, want the separate node pool", spew.Sdump(actual[1]))
+	}
+}
+
+func TestGetResourcesNetwork(t *testing.T) {
+	classDetails, err := cd.NewResourceDetail()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	f, err := os.Open("../testdata/network/tfplan.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	plan, err := ExtractPlanStruct(f)
+	if err != nil || plan == nil {
+		t.Fatal(err)
+	}
+
+	assumptions := &usage.Assumptions{Resources: map[string]usage.Values{
+		"google_compute_address.external": {"in_use": 1, "egress_gib.internet_emea": 10},
+		"google_compute_router_nat.nat":   {"vm_count": 4, "egress_gib.internet_americas": 50},
+	}}
+
+	external, _ := resources.NewAddress("test-external", "", "", "us-central1", "", false, nil,
+		usage.Values{"in_use": 1, "egress_gib.internet_emea": 10})
+	global, _ := resources.NewAddress("test-lb", "", "", "", "", true, nil, nil)
+	nat, _ := resources.NewRouterNAT("test-nat", "", "us-central1",
+		usage.Values{"vm_count": 4, "egress_gib.internet_americas": 50})
+	expected := []resources.ResourceState{
+		&resources.AddressState{After: external, Action: "create", ResourceAddresses: addresses("google_compute_address.external")},
+		&resources.AddressState{After: global, Action: "create", ResourceAddresses: addresses("google_compute_global_address.lb")},
+		&resources.RouterNATState{After: nat, Action: "create", ResourceAddresses: addresses("google_compute_router_nat.nat")},
+	}
+
+	actual, _ := GetResources(classDetails, assumptions, plan)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected:\n\n%s\n\ngot:\n\n%s", spew.Sdump(expected), spew.Sdump(actual))
+	}
+}
+
+func TestGetResourcesLoadBalancers(t *testing.T) {
+	classDetails, err := cd.NewResourceDetail()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	f, err := os.Open("../testdata/load-balancer/tfplan.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	plan, err := ExtractPlanStruct(f)
+	if err != nil || plan == nil {
+		t.Fatal(err)
+	}
+
+	assumptions := &usage.Assumptions{
+		Defaults:  map[string]usage.Values{GlobalForwardingRuleType: {"data_processed_gib": 10}},
+		Resources: map[string]usage.Values{"google_compute_global_forwarding_rule.https": {"data_processed_gib": 90}},
+	}
+
+	http, _ := resources.NewForwardingRule("test-http-rule", "", "", "EXTERNAL", true, usage.Values{"data_processed_gib": 10})
+	https, _ := resources.NewForwardingRule("test-https-rule", "", "", "EXTERNAL", true, usage.Values{"data_processed_gib": 90})
+	internal, _ := resources.NewForwardingRule("test-internal-rule", "", "us-central1", "INTERNAL", false, nil)
+	web, _ := resources.NewLoadBalancer("google_compute_url_map.web", []*resources.ForwardingRule{http, https},
+		[]string{"google_compute_target_http_proxy.http", "google_compute_target_https_proxy.https"})
+	ilb, _ := resources.NewLoadBalancer("google_compute_region_backend_service.internal",
+		[]*resources.ForwardingRule{internal}, nil)
+	expected := []resources.ResourceState{
+		&resources.LoadBalancerState{After: web, Action: "create", ResourceAddresses: addresses(
+			"google_compute_global_forwarding_rule.http", "google_compute_global_forwarding_rule.https")},
+		&resources.LoadBalancerState{After: ilb, Action: "create",
+			ResourceAddresses: addresses("google_compute_forwarding_rule.internal")},
+	}
+
+	actual, _ := GetResources(classDetails, assumptions, plan)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected:\n\n%s\n\ngot:\n\n%s", spew.Sdump(expected), spew.Sdump(actual))
+	}
+}
+
+func TestGetResourcesUnpriced(t *testing.T) {
+	classDetails, err := cd.NewResourceDetail()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	instance := func(machineType, zone string) *tfjson.Change {
+		return &tfjson.Change{
+			Actions: tfjson.Actions{tfjson.ActionCreate},
+			After:   map[string]interface{}{"name": "vm", "machine_type": machineType, "zone": zone},
+		}
+	}
+	created := &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionCreate}, After: map[string]interface{}{}}
+	unchanged := &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionNoop}, Before: map[string]interface{}{}, After: map[string]interface{}{}}
+	// The zone of a running instance is not checked, even if the class details don't offer its machine type there.
+	running := func(action tfjson.Action, after map[string]interface{}) *tfjson.Change {
+		return &tfjson.Change{
+			Actions: tfjson.Actions{action},
+			Before:  map[string]interface{}{"name": "vm", "machine_type": "c2-standard-8", "zone": "europe-west6-a"},
+			After:   after,
+		}
+	}
+	plan := &tfjson.Plan{ResourceChanges: []*tfjson.ResourceChange{
+		{Address: "google_redis_instance.cache", Mode: tfjson.ManagedResourceMode, Type: "google_redis_instance", Change: created},
+		{Address: "google_redis_instance.unchanged", Mode: tfjson.ManagedResourceMode, Type: "google_redis_instance", Change: unchanged},
+		{Address: "google_compute_network.net", Mode: tfjson.ManagedResourceMode, Type: "google_compute_network", Change: created},
+		{Address: "google_project_iam_member.viewer", Mode: tfjson.ManagedResourceMode, Type: "google_project_iam_member", Change: created},
+		{Address: "random_id.suffix", Mode: tfjson.ManagedResourceMode, Type: "random_id", Change: created},
+		{Address: "data.google_compute_image.img", Mode: tfjson.DataResourceMode, Type: "google_compute_image",
+			Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionRead}}},
+		{Address: "module.a.google_compute_instance.vm", Mode: tfjson.ManagedResourceMode, Type: "google_compute_instance",
+			Change: instance("n1-standard-3", "us-central1-a")},
+		{Address: "google_compute_instance.zone", Mode: tfjson.ManagedResourceMode, Type: "google_compute_instance",
+			Change: instance("n1-standard-1", "uscentral1")},
+		{Address: "google_compute_instance.ok", Mode: tfjson.ManagedResourceMode, Type: "google_compute_instance",
+			Change: instance("n1-standard-1", "us-central1-a")},
+		{Address: "google_compute_instance.unavailable", Mode: tfjson.ManagedResourceMode, Type: "google_compute_instance",
+			Change: instance("c2-standard-8", "europe-west6-a")},
+		{Address: "google_compute_instance.deleted", Mode: tfjson.ManagedResourceMode, Type: "google_compute_instance",
+			Change: running(tfjson.ActionDelete, nil)},
+		{Address: "google_compute_instance.resized", Mode: tfjson.ManagedResourceMode, Type: "google_compute_instance",
+			Change: running(tfjson.ActionUpdate, map[string]interface{}{"name": "vm", "machine_type": "n1-standard-2", "zone": "europe-west6-a"})},
+	}}
+
+	expected := []struct {
+		address      string
+		resourceType string
+		kind         resources.ErrorKind
+	}{
+		{"google_redis_instance.cache", "google_redis_instance", resources.KindUnsupportedType},
+		{"module.a.google_compute_instance.vm", "google_compute_instance", resources.KindUnknownMachineType},
+		{"google_compute_instance.zone", "google_compute_instance", resources.KindInvalidZone},
+		{"google_compute_instance.unavailable", "google_compute_instance", resources.KindInvalidZone},
+	}
+
+	states, errs := GetResources(classDetails, nil, plan)
+	if len(states) != 3 {
+		t.Errorf("GetResources() got %d states, want 3", len(states))
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("GetResources() got errors %v, want %d", errs, len(expected))
+	}
+	for i, e := range expected {
+		if errs[i].Address != e.address || errs[i].Type != e.resourceType || errs[i].Kind != e.kind {
+			t.Errorf("GetResources() error %d = %s %s %s, want %s %s %s",
+				i, errs[i].Address, errs[i].Type, errs[i].Kind, e.address, e.resourceType, e.kind)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package jsdecode
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/googleinterns/terraform-cost-estimation/registry"
+	resources "github.com/googleinterns/terraform-cost-estimation/resources"
+	"github.com/googleinterns/terraform-cost-estimation/usage"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// The forwarding rule resource types, which are grouped by load balancer.
+const (
+	ForwardingRuleType       = "google_compute_forwarding_rule"
+	GlobalForwardingRuleType = "google_compute_global_forwarding_rule"
+)
+
+// targetProxyTypes are the target proxy resource types. They are not charged,
+// but link the forwarding rules to the URL map or backend service of their load balancer.
+var targetProxyTypes = map[string]bool{
+	"google_compute_target_http_proxy":         true,
+	"google_compute_target_https_proxy":        true,
+	"google_compute_target_tcp_proxy":          true,
+	"google_compute_target_ssl_proxy":          true,
+	"google_compute_target_grpc_proxy":         true,
+	"google_compute_region_target_http_proxy":  true,
+	"google_compute_region_target_https_proxy": true,
+	"google_compute_region_target_tcp_proxy":   true,
+}
+
+// ForwardingRuleInfo contains the information about a regional or global forwarding rule in json plan file.
+type ForwardingRuleInfo struct {
+	Name                string `json:"name,omitempty"`
+	ID                  string `json:"id,omitempty"`
+	Region              string `json:"region,omitempty"`
+	LoadBalancingScheme string `json:"load_balancing_scheme,omitempty"`
+	Target              string `json:"target,omitempty"`
+	BackendService      string `json:"backend_service,omitempty"`
+}
+
+var addressIndex = regexp.MustCompile(`\[[^\]]*\]`)
+
+// configAddress returns the address of a resource in the configuration, without count and for_each indexes.
+func configAddress(address string) string {
+	return addressIndex.ReplaceAllString(address, "")
+}
+
+// referencedResource returns the address of the resource referenced by an expression reference
+// (e.g. google_compute_url_map.default.id) or "" if it does not reference a managed resource.
+func referencedResource(ref string) string {
+	parts := strings.Split(configAddress(ref), ".")
+	if len(parts) < 2 {
+		return ""
+	}
+	switch parts[0] {
+	case "var", "local", "module", "data", "path", "count", "each", "self", "terraform":
+		return ""
+	default:
+		return parts[0] + "." + parts[1]
+	}
+}
+
+// resourceReferences maps the configuration address of each resource to the resources referenced
+// by its target, backend_service and url_map arguments.
+func resourceReferences(plan *tfjson.Plan) map[string]map[string]string {
+	refs := map[string]map[string]string{}
+	if plan.Config == nil {
+		return refs
+	}
+
+	var walk func(m *tfjson.ConfigModule, prefix string)
+	walk = func(m *tfjson.ConfigModule, prefix string) {
+		if m == nil {
+			return
+		}
+		for _, r := range m.Resources {
+			for _, arg := range []string{"target", "backend_service", "url_map"} {
+				e, ok := r.Expressions[arg]
+				if !ok || e == nil {
+					continue
+				}
+				for _, ref := range e.References {
+					if res := referencedResource(ref); res != "" {
+						if refs[prefix+r.Address] == nil {
+							refs[prefix+r.Address] = map[string]string{}
+						}
+						refs[prefix+r.Address][arg] = prefix + res
+						break
+					}
+				}
+			}
+		}
+		for name, call := range m.ModuleCalls {
+			walk(call.Module, prefix+"module."+name+".")
+		}
+	}
+	walk(plan.Config.RootModule, "")
+	return refs
+}
+
+// loadBalancerGroup holds the forwarding rules of a load balancer before and after the changes.
+type loadBalancerGroup struct {
+	name      string
+	before    []*resources.ForwardingRule
+	after     []*resources.ForwardingRule
+	proxies   []string
+	actions   []string
+	addresses []string
+}
+
+// loadBalancerGroups groups the forwarding rule changes by load balancer, keeping the order of the plan.
+type loadBalancerGroups struct {
+	refs   map[string]map[string]string
+	index  map[string]int
+	groups []*loadBalancerGroup
+}
+
+func newLoadBalancerGroups(plan *tfjson.Plan) registry.Grouper {
+	return &loadBalancerGroups{refs: resourceReferences(plan), index: map[string]int{}}
+}
+
+// loadBalancerKey returns the address of the resource identifying the load balancer of the forwarding rule:
+// the URL map or backend service behind its target proxy, the target itself or the rule if its target is unknown.
+func (g *loadBalancerGroups) loadBalancerKey(address string, info *ForwardingRuleInfo) (key, proxy string) {
+	refs := g.refs[configAddress(address)]
+	target := refs["target"]
+	if target == "" {
+		target = refs["backend_service"]
+	}
+
+	switch {
+	case target != "":
+		parts := strings.Split(target, ".")
+		if !targetProxyTypes[parts[len(parts)-2]] {
+			return target, ""
+		}
+		if up := g.refs[target]["url_map"]; up != "" {
+			return up, target
+		}
+		if up := g.refs[target]["backend_service"]; up != "" {
+			return up, target
+		}
+		return target, target
+	case info != nil && info.Target != "":
+		return info.Target, ""
+	case info != nil && info.BackendService != "":
+		return info.BackendService, ""
+	default:
+		return configAddress(address), ""
+	}
+}
+
+func toForwardingRule(u usage.Values, global bool, resource interface{}) (*resources.ForwardingRule, *ForwardingRuleInfo, error) {
+	if resource == nil {
+		return nil, nil, nil
+	}
+
+	jsonString, err := json.Marshal(resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var r *ForwardingRuleInfo
+	if err := json.Unmarshal(jsonString, &r); err != nil || r == nil {
+		return nil, nil, err
+	}
+
+	rule, err := resources.NewForwardingRule(r.Name, r.ID, r.Region, r.LoadBalancingScheme, global, u)
+	return rule, r, err
+}
+
+// Add decodes the forwarding rule change and adds it to the group of its load balancer.
+// Target proxy changes are ignored, since proxies are found through the references of the forwarding rules.
+func (g *loadBalancerGroups) Add(ctx *registry.Context, change *tfjson.ResourceChange) error {
+	if targetProxyTypes[change.Type] {
+		return nil
+	}
+
+	global := change.Type == GlobalForwardingRuleType
+	before, beforeInfo, err := toForwardingRule(ctx.Usage, global, change.Change.Before)
+	if err != nil {
+		return err
+	}
+
+	after, afterInfo, err := toForwardingRule(ctx.Usage, global, change.Change.After)
+	if err != nil {
+		return err
+	}
+
+	if before == nil && after == nil {
+		return nil
+	}
+
+	action, err := initAction(change.Change.Actions)
+	if err != nil {
+		return err
+	}
+
+	info := afterInfo
+	if info == nil {
+		info = beforeInfo
+	}
+	key, proxy := g.loadBalancerKey(change.Address, info)
+
+	i, ok := g.index[key]
+	if !ok {
+		i = len(g.groups)
+		g.index[key] = i
+		g.groups = append(g.groups, &loadBalancerGroup{name: key})
+	}
+	lb := g.groups[i]
+
+	if before != nil {
+		lb.before = append(lb.before, before)
+	}
+	if after != nil {
+		lb.after = append(lb.after, after)
+	}
+	if proxy != "" && !contains(lb.proxies, proxy) {
+		lb.proxies = append(lb.proxies, proxy)
+	}
+	lb.actions = append(lb.actions, action)
+	lb.addresses = append(lb.addresses, change.Address)
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// groupAction returns the action performed on a load balancer, which is the action performed on
+// all its forwarding rules or update if they differ.
+func groupAction(actions []string) string {
+	for _, a := range actions[1:] {
+		if a != actions[0] {
+			return ActionUpdate
+		}
+	}
+	return actions[0]
+}
+
+// States returns the states of the load balancers.
+// A load balancer that can't be built gets an error for each of its resource changes.
+func (g *loadBalancerGroups) States() (states []resources.ResourceState, errs []*resources.ResourceError) {
+	for _, lb := range g.groups {
+		state := &resources.LoadBalancerState{Action: groupAction(lb.actions)}
+		state.Addresses = lb.addresses
+
+		var err error
+		if len(lb.before) > 0 {
+			state.Before, err = resources.NewLoadBalancer(lb.name, lb.before, lb.proxies)
+		}
+		if err == nil && len(lb.after) > 0 {
+			state.After, err = resources.NewLoadBalancer(lb.name, lb.after, lb.proxies)
+		}
+		if err != nil {
+			for _, a := range lb.addresses {
+				errs = append(errs, resources.NewResourceError(a, resources.AddressType(a), resources.KindInvalidConfig, err))
+			}
+			continue
+		}
+		states = append(states, state)
+	}
+	return
+}
@@ -0,0 +1,181 @@
+package jsdecode
+
+import (
+	"sort"
+
+	"github.com/googleinterns/terraform-cost-estimation/billing"
+	"github.com/googleinterns/terraform-cost-estimation/registry"
+	resources "github.com/googleinterns/terraform-cost-estimation/resources"
+)
+
+// The pricers of the resource types supported by this module.
+var (
+	computeInstancePricer = &registry.Pricer{
+		Types:    []string{ComputeInstanceType},
+		Catalogs: []string{billing.ComputeEngine},
+		Decode: func(ctx *registry.Context, v interface{}) (interface{}, error) {
+			return toComputeInstance(ctx.Details, v)
+		},
+		Check: func(ctx *registry.Context, after interface{}) error {
+			return after.(*resources.ComputeInstance).CheckZone(ctx.Details)
+		},
+		NewState: func(before, after interface{}, action string) resources.ResourceState {
+			s := &resources.ComputeInstanceState{Action: action}
+			s.Before, _ = before.(*resources.ComputeInstance)
+			s.After, _ = after.(*resources.ComputeInstance)
+			return s
+		},
+	}
+
+	computeDiskPricer = &registry.Pricer{
+		Types:    []string{ComputeDiskType},
+		Catalogs: []string{billing.ComputeEngine},
+		Decode: func(ctx *registry.Context, v interface{}) (interface{}, error) {
+			return toComputeDisk(ctx.Details, v)
+		},
+		NewState: func(before, after interface{}, action string) resources.ResourceState {
+			s := &resources.ComputeDiskState{Action: action}
+			s.Before, _ = before.(*resources.ComputeDisk)
+			s.After, _ = after.(*resources.ComputeDisk)
+			return s
+		},
+	}
+
+	sqlInstancePricer = &registry.Pricer{
+		Types:    []string{SQLInstanceType},
+		Catalogs: []string{billing.CloudSQL},
+		Decode: func(ctx *registry.Context, v interface{}) (interface{}, error) {
+			return toSQLInstance(ctx.Details, v)
+		},
+		NewState: func(before, after interface{}, action string) resources.ResourceState {
+			s := &resources.SQLInstanceState{Action: action}
+			s.Before, _ = before.(*resources.SQLInstance)
+			s.After, _ = after.(*resources.SQLInstance)
+			return s
+		},
+	}
+
+	storageBucketPricer = &registry.Pricer{
+		Types:    []string{StorageBucketType},
+		Catalogs: []string{billing.CloudStorage},
+		Decode: func(ctx *registry.Context, v interface{}) (interface{}, error) {
+			return toStorageBucket(ctx.Usage, v)
+		},
+		NewState: func(before, after interface{}, action string) resources.ResourceState {
+			s := &resources.StorageBucketState{Action: action}
+			s.Before, _ = before.(*resources.StorageBucket)
+			s.After, _ = after.(*resources.StorageBucket)
+			return s
+		},
+	}
+
+	clusterPricer = &registry.Pricer{
+		Types:    []string{ClusterType},
+		Catalogs: []string{billing.KubernetesEngine, billing.ComputeEngine},
+		Decode: func(ctx *registry.Context, v interface{}) (interface{}, error) {
+			return toKubernetesCluster(ctx.Details, ctx.Usage, v, separateNodePools(ctx.Plan))
+		},
+		Check: func(ctx *registry.Context, after interface{}) error {
+			return after.(*resources.KubernetesCluster).CheckZones(ctx.Details)
+		},
+		NewState: func(before, after interface{}, action string) resources.ResourceState {
+			s := &resources.KubernetesClusterState{Action: action}
+			s.Before, _ = before.(*resources.KubernetesCluster)
+			s.After, _ = after.(*resources.KubernetesCluster)
+			return s
+		},
+	}
+
+	nodePoolPricer = &registry.Pricer{
+		Types:    []string{NodePoolType},
+		Catalogs: []string{billing.ComputeEngine},
+		Decode: func(ctx *registry.Context, v interface{}) (interface{}, error) {
+			return toNodePool(ctx.Details, ctx.Usage, v)
+		},
+		Check: func(ctx *registry.Context, after interface{}) error {
+			return after.(*resources.NodePool).CheckZones(ctx.Details)
+		},
+		NewState: func(before, after interface{}, action string) resources.ResourceState {
+			s := &resources.NodePoolState{Action: action}
+			s.Before, _ = before.(*resources.NodePool)
+			s.After, _ = after.(*resources.NodePool)
+			return s
+		},
+	}
+
+	addressPricer = &registry.Pricer{
+		Types:    []string{AddressType},
+		Catalogs: []string{billing.Network},
+		Decode: func(ctx *registry.Context, v interface{}) (interface{}, error) {
+			return toAddress(ctx.Usage, false, v)
+		},
+		NewState: newAddressState,
+	}
+
+	globalAddressPricer = &registry.Pricer{
+		Types:    []string{GlobalAddressType},
+		Catalogs: []string{billing.Network},
+		Decode: func(ctx *registry.Context, v interface{}) (interface{}, error) {
+			return toAddress(ctx.Usage, true, v)
+		},
+		NewState: newAddressState,
+	}
+
+	routerNATPricer = &registry.Pricer{
+		Types:    []string{RouterNATType},
+		Catalogs: []string{billing.Network},
+		Decode: func(ctx *registry.Context, v interface{}) (interface{}, error) {
+			return toRouterNAT(ctx.Usage, v)
+		},
+		NewState: func(before, after interface{}, action string) resources.ResourceState {
+			s := &resources.RouterNATState{Action: action}
+			s.Before, _ = before.(*resources.RouterNAT)
+			s.After, _ = after.(*resources.RouterNAT)
+			return s
+		},
+	}
+
+	loadBalancerPricer = &registry.Pricer{
+		Types:      append([]string{ForwardingRuleType, GlobalForwardingRuleType}, proxyTypes()...),
+		Catalogs:   []string{billing.Network},
+		NewGrouper: newLoadBalancerGroups,
+	}
+)
+
+// freeTypes are the resource types that are not charged, or whose cost is that of other resources
+// (e.g. the URL maps and backend services of load balancers are priced by their forwarding rules).
+var freeTypes = []string{
+	"google_compute_network", "google_compute_subnetwork", "google_compute_firewall", "google_compute_route",
+	"google_compute_router", "google_compute_network_peering", "google_compute_instance_template",
+	"google_compute_url_map", "google_compute_backend_service", "google_compute_backend_bucket",
+	"google_compute_health_check", "google_compute_http_health_check", "google_compute_https_health_check",
+	"google_compute_project_metadata", "google_compute_project_metadata_item", "google_project_service",
+	"google_service_account", "google_service_account_key", "google_kms_key_ring", "google_project_iam_custom_role",
+	"*_iam_member", "*_iam_binding", "*_iam_policy",
+	"random_*", "null_resource", "time_*", "tls_*", "local_file",
+}
+
+func newAddressState(before, after interface{}, action string) resources.ResourceState {
+	s := &resources.AddressState{Action: action}
+	s.Before, _ = before.(*resources.Address)
+	s.After, _ = after.(*resources.Address)
+	return s
+}
+
+func proxyTypes() []string {
+	var types []string
+	for t := range targetProxyTypes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func init() {
+	for _, p := range []*registry.Pricer{computeInstancePricer, computeDiskPricer, sqlInstancePricer,
+		storageBucketPricer, clusterPricer, nodePoolPricer, addressPricer, globalAddressPricer, routerNATPricer,
+		loadBalancerPricer} {
+		registry.MustRegister(p)
+	}
+	registry.RegisterFree(freeTypes...)
+}
@@ -5,31 +5,56 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"strings"
+	"os"
+	"runtime"
+	"time"
 
 	"github.com/googleinterns/terraform-cost-estimation/billing"
+	"github.com/googleinterns/terraform-cost-estimation/estimator"
 	"github.com/googleinterns/terraform-cost-estimation/io"
-	"github.com/googleinterns/terraform-cost-estimation/jsdecode"
 	res "github.com/googleinterns/terraform-cost-estimation/resources"
 	cd "github.com/googleinterns/terraform-cost-estimation/resources/classdetail"
+	"github.com/googleinterns/terraform-cost-estimation/usage"
 )
 
 var (
-	output = flag.String("output", "stdout", `Write the cost estimations to the given paths.
-If set to 'stdout', all the outputs will be shown in the command line.
-Multiple output file names must be delimited by ','.
-Mixed file names and stdout values are allowed.`)
-	format = flag.String("format", "txt", `Write the pricing information in the specified format.
-Can be set to: txt, json, html.`)
+	output = flag.String("output", "stdout", `Write the cost estimations to the given targets, delimited by ','.
+Each target is a path or a format:path pair (e.g. html:report.html,json:report.json,txt:stdout).
+If the path is 'stdout', the output will be shown in the command line.
+The targets of a format are matched to the plan files by position if there are as many as plan files,
+otherwise a single stdout, csv or tsv target is shared by all of them.`)
+	format = flag.String("format", "txt", `Write the pricing information in the specified format, for the targets given without one.
+Can be set to: txt, json, json-v2, html, csv, tsv.
+csv and tsv write one row per billing component; a single output file collects the rows of all the plan files.
+json-v2 is the schema-versioned json output described by io/js/schema_v2.json.`)
+	explain = flag.Bool("explain", false, `Add a section showing the SKU used to price each component: its ID, description,
+service regions, usage type and tier, the description filters applied and the rejected candidates.`)
+	period = flag.String("period", "hour", `Report the costs over the given period.
+Can be set to: hour, month, year.`)
+	month = flag.String("month", res.Month30d, `Count a month as the given number of hours or days.
+Can be set to: 730h (the average month), 30d.`)
+	currency = flag.String("currency", billing.DefaultCurrency, `Price the resources in the given ISO 4217 currency (e.g. EUR, JPY).
+The prices are converted by the Cloud Billing Catalog API at its current exchange rate.`)
+	usageFile = flag.String("usage", "", `Read the usage assumptions (stored data, operations, egress etc.) from the given JSON file.
+Resources priced by usage are estimated with zero usage if omitted.`)
+	snapshots = flag.String("snapshots", "", `Keep dated snapshots of the billing catalog in the given directory.
+The SKUs loaded from the Cloud Billing Catalog API are saved to the snapshot of the current date,
+unless the estimate is run as of a past date with -as-of.`)
+	asOf = flag.String("as-of", "", `Price the resources with the latest catalog snapshot on or before the given date (YYYY-MM-DD)
+of the -snapshots directory instead of the current prices. The reports give the date of the snapshot used.`)
+	classDetails = flag.String("class-details", "", `Read machine types, disk types and images from the data files of the given directory
+(instance/machine_types.json, instance/machine_type_zones.json, disk/compute_disk_types.json, image/compute_images.json)
+in addition to the data embedded in the binary, e.g. to add private images or newly launched machine types. The reports give the data version.`)
+	classDetailsMode = flag.String("class-details-mode", "overlay", `Combine the -class-details data files with the embedded data as given.
+Can be set to: overlay (the entries of the files replace those of the same name), replace (the files replace the embedded ones).`)
+	strict = flag.Bool("strict", false, `Fail (exit status 1) if any resource change of a plan file could not be priced.
+The outputs are still written, with the unpriced resources listed next to the priced ones.`)
+	aggregate = flag.Bool("aggregate", false, `Write a single report covering all the plan files, with the subtotal of each plan, the grand total
+and the resources labelled with their plan file. Each format then has a single target.`)
+	workers = flag.Int("workers", runtime.NumCPU(), `Decode the plan files and price the resources with at most the given number of workers.
+The outputs are written in the order of the plan files whatever the number of workers.`)
 )
 
-func minInt(x, y int) int {
-	if x < y {
-		return x
-	}
-	return y
-}
-
 func main() {
 	flag.Usage = func() {
 		fmt.Fprintf(flag.CommandLine.Output(), "Usage: go run main.go [OPTIONS] FILE\n\n")
@@ -44,62 +69,134 @@ func main() {
 		log.Fatal("Error: No input file.")
 	}
 
-	outputs := strings.Split(*output, ",")
-	if *output != "stdout" {
-		if len(outputs) != len(flag.Args()) {
-			log.Fatal("Error: Input and output files number differ.")
-		}
+	monthPeriod, err := res.Month(*month)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
 	}
-
-	catalog, err := billing.NewComputeEngineCatalog(context.Background())
+	reportPeriod, err := res.NewPeriod(*period, *month)
 	if err != nil {
 		log.Fatalf("Error: %v", err)
 	}
 
-	classDetails, err := cd.NewResourceDetail()
+	targets, err := io.ParseTargets(*output, *format)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	plans := len(flag.Args())
+	if *aggregate {
+		plans = 1
+	}
+	assigned, err := io.AssignTargets(targets, plans)
 	if err != nil {
-		log.Fatalf("Error: %+v", err)
+		log.Fatalf("Error: %v", err)
 	}
 
-	for i, inputName := range flag.Args() {
-		plan, err := io.GetPlan(inputName)
+	opts := []estimator.Option{
+		estimator.WithCurrency(*currency),
+		estimator.WithPeriod(reportPeriod),
+		estimator.WithMonth(monthPeriod),
+		estimator.WithWorkers(*workers),
+		estimator.WithExplain(*explain),
+	}
+	switch {
+	case *asOf != "":
+		if *snapshots == "" {
+			log.Fatal("Error: -as-of requires the -snapshots directory.")
+		}
+		date, err := time.Parse(billing.SnapshotDate, *asOf)
 		if err != nil {
-			log.Fatalf("Error: %v", err)
+			log.Fatalf("Error: invalid -as-of date %q, must be YYYY-MM-DD", *asOf)
 		}
-
-		resources := jsdecode.GetResources(classDetails, plan)
-
-		finalResources := []res.ResourceState{}
-		for _, r := range resources {
-			if err = r.CompletePricingInfo(catalog); err != nil {
-				log.Printf("In file %s got error: %v", inputName, err)
-				continue
-			}
-			finalResources = append(finalResources, r)
+		opts = append(opts, estimator.WithSnapshot(billing.NewSnapshotStore(*snapshots), date))
+	case *snapshots != "":
+		opts = append(opts, estimator.WithRecording(billing.NewSnapshotStore(*snapshots)))
+	}
+	if *classDetails != "" {
+		modes := map[string]cd.DataMode{"overlay": cd.Overlay, "replace": cd.Replace}
+		mode, ok := modes[*classDetailsMode]
+		if !ok {
+			log.Fatalf("Error: invalid -class-details-mode %q, must be overlay or replace", *classDetailsMode)
 		}
-
-		outputName := outputs[minInt(i, len(outputs)-1)]
-		fout, err := io.GetOutputWriter(outputName)
+		details, err := cd.LoadResourceDetail(*classDetails, mode)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		opts = append(opts, estimator.WithClassDetails(details))
+	}
+	if *usageFile != "" {
+		assumptions, err := usage.ReadFile(*usageFile)
 		if err != nil {
 			log.Fatalf("Error: %v", err)
 		}
+		opts = append(opts, estimator.WithUsage(assumptions))
+	}
 
-		switch {
-		case *format == "json":
-			if err = io.GenerateJsonOut(fout, finalResources); err != nil {
-				log.Printf("Error: %v", err)
+	e, err := estimator.New(context.Background(), opts...)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	reports, errs := e.EstimateFiles(flag.Args()...)
+
+	// The outputs are written in the order of the input files, the errors of a file don't stop the others.
+	outputs := io.NewOutputs(os.Stdout)
+	failed := false
+	if *aggregate {
+		// The aggregate report covers the files that could be estimated, the others are reported below.
+		var planReports []io.PlanReport
+		for i, inputName := range flag.Args() {
+			if errs[i] == nil {
+				planReports = append(planReports, io.PlanReport{Name: inputName, Report: reports[i]})
 			}
-		case *format == "html":
-			if err = io.GenerateWebPage(fout, finalResources); err != nil {
-				log.Printf("Error: %v", err)
+		}
+		if len(planReports) > 0 {
+			if err := writeAggregate(outputs, assigned[0], planReports); err != nil {
+				log.Printf("Error: aggregate report: %v", err)
+				failed = true
+			}
+		}
+	} else {
+		for i, inputName := range flag.Args() {
+			if errs[i] != nil {
+				continue
+			}
+			for _, t := range assigned[i] {
+				if err := outputs.Write(t, inputName, reports[i]); err != nil && errs[i] == nil {
+					errs[i] = fmt.Errorf("output %s: %v", t.Path, err)
+				}
 			}
-		case *format == "txt":
-			io.OutputPricing(finalResources, fout)
-		default:
 		}
+	}
+	for i := range flag.Args() {
+		if errs[i] == nil && *strict && len(reports[i].Unpriced) > 0 {
+			errs[i] = fmt.Errorf("%d resource changes could not be priced (strict mode)", len(reports[i].Unpriced))
+		}
+	}
 
-		if err = io.FinishOutput(fout); err != nil {
-			log.Fatalf("Error: %v", err)
+	if err := outputs.Close(); err != nil {
+		log.Printf("Error: %v", err)
+		failed = true
+	}
+	for i, inputName := range flag.Args() {
+		if errs[i] != nil {
+			log.Printf("Error: file %s: %v", inputName, errs[i])
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// writeAggregate writes the aggregate report of the plans to the targets.
+func writeAggregate(outputs *io.Outputs, targets []io.Target, plans []io.PlanReport) error {
+	a, err := io.NewAggregate(plans)
+	if err != nil {
+		return err
+	}
+	for _, t := range targets {
+		if err := outputs.WriteAggregate(t, a); err != nil {
+			return fmt.Errorf("output %s: %v", t.Path, err)
 		}
 	}
+	return nil
 }
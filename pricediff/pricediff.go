@@ -0,0 +1,127 @@
+// Package pricediff compares the prices of a Compute Engine machine type or disk type in two billing catalogs,
+// e.g. two dated catalog snapshots, billing component by billing component.
+package pricediff
+
+import (
+	"fmt"
+
+	"github.com/googleinterns/terraform-cost-estimation/billing"
+	"github.com/googleinterns/terraform-cost-estimation/resources"
+	cd "github.com/googleinterns/terraform-cost-estimation/resources/classdetail"
+)
+
+// Item is a machine type or disk type priced in a zone. The usage type of a machine type defaults to OnDemand
+// and the size of a disk type to its default size.
+type Item struct {
+	MachineType string
+	DiskType    string
+	Zone        string
+	UsageType   string
+	SizeGiB     int64
+}
+
+// String returns the name of the item and its zone.
+func (item Item) String() string {
+	if item.DiskType != "" {
+		return item.DiskType + " in " + item.Zone
+	}
+	return item.MachineType + " in " + item.Zone
+}
+
+// state returns the state creating the item.
+func (item Item) state(details *cd.ResourceDetail) (resources.ResourceState, error) {
+	switch {
+	case item.MachineType != "" && item.DiskType != "":
+		return nil, fmt.Errorf("a machine type or a disk type must be given, not both")
+	case item.MachineType != "":
+		usageType := item.UsageType
+		if usageType == "" {
+			usageType = "OnDemand"
+		}
+		instance, err := resources.NewComputeInstance(details, "", item.MachineType, item.MachineType, item.Zone, usageType)
+		if err != nil {
+			return nil, err
+		}
+		if err := instance.CheckZone(details); err != nil {
+			return nil, err
+		}
+		return &resources.ComputeInstanceState{After: instance, Action: "create"}, nil
+	case item.DiskType != "":
+		disk, err := resources.NewComputeDisk(details, item.DiskType, "", item.DiskType, []string{item.Zone}, "", "", item.SizeGiB)
+		if err != nil {
+			return nil, err
+		}
+		return &resources.ComputeDiskState{After: disk, Action: "create"}, nil
+	}
+	return nil, fmt.Errorf("a machine type or a disk type must be given")
+}
+
+// Price returns the hourly cost of the billing components of the item priced with the catalog.
+func Price(details *cd.ResourceDetail, catalog *billing.Catalog, item Item) ([]resources.ComponentCost, error) {
+	s, err := item.state(details)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.CompletePricingInfo(catalog); err != nil {
+		return nil, err
+	}
+	return s.(resources.Reporter).Report().After.Components, nil
+}
+
+// Change is the change of the hourly unit cost of a billing component of an item between two catalogs.
+type Change struct {
+	Component string  `json:"component"`
+	UsageUnit string  `json:"usage_unit"`
+	Currency  string  `json:"currency"`
+	Units     float64 `json:"units"`
+	Before    float64 `json:"before_unit_cost"`
+	After     float64 `json:"after_unit_cost"`
+}
+
+// Delta returns the change of the hourly cost of all the units of the component.
+func (c Change) Delta() float64 {
+	return (c.After - c.Before) * c.Units
+}
+
+// Percent returns the relative change of the unit cost in percent, 0 if the component was free.
+func (c Change) Percent() float64 {
+	if c.Before == 0 {
+		return 0
+	}
+	return 100 * (c.After - c.Before) / c.Before
+}
+
+// Diff returns the changes of the hourly unit costs of the item components from the before catalog
+// to the after one, in the order of the components.
+func Diff(details *cd.ResourceDetail, before, after *billing.Catalog, item Item) ([]Change, error) {
+	b, err := Price(details, before, item)
+	if err != nil {
+		return nil, fmt.Errorf("before: %w", err)
+	}
+	a, err := Price(details, after, item)
+	if err != nil {
+		return nil, fmt.Errorf("after: %w", err)
+	}
+	if len(a) != len(b) {
+		return nil, fmt.Errorf("the item has %d billing components before and %d after", len(b), len(a))
+	}
+
+	var changes []Change
+	for i := range a {
+		if a[i].Name != b[i].Name {
+			return nil, fmt.Errorf("billing component %s before is %s after", b[i].Name, a[i].Name)
+		}
+		if a[i].Currency != b[i].Currency {
+			return nil, fmt.Errorf("%s is priced in %s before and %s after", a[i].Name, b[i].Currency, a[i].Currency)
+		}
+		changes = append(changes, Change{
+			Component: a[i].Name,
+			UsageUnit: a[i].UsageUnit,
+			Currency:  a[i].Currency,
+			Units:     a[i].Units,
+			Before:    b[i].UnitCost,
+			After:     a[i].UnitCost,
+		})
+	}
+	return changes, nil
+}
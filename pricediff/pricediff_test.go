@@ -0,0 +1,83 @@
+package pricediff
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/googleinterns/terraform-cost-estimation/billing"
+	cd "github.com/googleinterns/terraform-cost-estimation/resources/classdetail"
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+	"google.golang.org/genproto/googleapis/type/money"
+)
+
+// testSKU builds a Compute Engine SKU of us-central1 with one tier rate of the given price in nanos.
+func testSKU(description, group, unit string, nanos int32) *billingpb.Sku {
+	return &billingpb.Sku{
+		Description:    description,
+		Category:       &billingpb.Category{ResourceFamily: "Compute", ResourceGroup: group, UsageType: "OnDemand"},
+		ServiceRegions: []string{"us-central1"},
+		PricingInfo: []*billingpb.PricingInfo{{PricingExpression: &billingpb.PricingExpression{
+			UsageUnitDescription: unit,
+			TieredRates:          []*billingpb.PricingExpression_TierRate{{UnitPrice: &money.Money{CurrencyCode: "USD", Nanos: nanos}}},
+		}}},
+	}
+}
+
+func testCatalog(core, ram int32) *billing.Catalog {
+	return billing.NewCatalogFromSKUs(map[string][]*billingpb.Sku{
+		billing.ComputeEngineService: {
+			testSKU("N1 Predefined Instance Core running in Americas", "N1Standard", "hour", core),
+			testSKU("N1 Predefined Instance Ram running in Americas", "N1Standard", "gibibyte hour", ram),
+		},
+	})
+}
+
+func TestDiff(t *testing.T) {
+	details, err := cd.NewResourceDetail()
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, after := testCatalog(40000000, 4000000), testCatalog(50000000, 4000000)
+
+	tests := []struct {
+		name     string
+		item     Item
+		expected []Change
+		ok       bool
+	}{
+		{"machine_type", Item{MachineType: "n1-standard-1", Zone: "us-central1-a"}, []Change{
+			{Component: "CPU", UsageUnit: "hour", Currency: "USD", Units: 1, Before: 0.04, After: 0.05},
+			{Component: "RAM", UsageUnit: "gibibyte", Currency: "USD", Units: 3.75, Before: 0.004, After: 0.004},
+		}, true},
+		{"unknown_machine_type", Item{MachineType: "n1-unknown-1", Zone: "us-central1-a"}, nil, false},
+		{"no_sku", Item{MachineType: "n1-standard-1", Zone: "europe-west1-b"}, nil, false},
+		{"no_item", Item{Zone: "us-central1-a"}, nil, false},
+		{"both", Item{MachineType: "n1-standard-1", DiskType: "pd-ssd", Zone: "us-central1-a"}, nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := Diff(details, before, after, test.item)
+			if ok := err == nil; ok != test.ok {
+				t.Fatalf("Diff(%v) error = %v, want ok %v", test.item, err, test.ok)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Diff(%v) = %+v, want %+v", test.item, actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestChange(t *testing.T) {
+	c := Change{Units: 2, Before: 0.04, After: 0.05}
+	if d := c.Delta(); math.Abs(d-0.02) > 1e-12 {
+		t.Errorf("Delta() = %v, want 0.02", d)
+	}
+	if p := c.Percent(); math.Abs(p-25) > 1e-9 {
+		t.Errorf("Percent() = %v, want 25", p)
+	}
+	if p := (Change{After: 1}).Percent(); p != 0 {
+		t.Errorf("Percent() of a free component = %v, want 0", p)
+	}
+}
@@ -0,0 +1,5 @@
+// Package registry holds the pricers of the supported Terraform resource types. Each pricer registers
+// a decoder of the resource values in plan files, the billing catalogs it needs and a factory of the
+// resources.ResourceState used for pricing and output. Pricers of other resource types can be registered
+// from outside this module before the plan files are decoded.
+package registry
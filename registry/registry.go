@@ -0,0 +1,150 @@
+package registry
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/googleinterns/terraform-cost-estimation/resources"
+	cd "github.com/googleinterns/terraform-cost-estimation/resources/classdetail"
+	"github.com/googleinterns/terraform-cost-estimation/usage"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// Context holds the information available when decoding a resource change.
+type Context struct {
+	Details *cd.ResourceDetail
+	Usage   usage.Values
+	Address string
+	Plan    *tfjson.Plan
+}
+
+// Decoder converts the before or after value of a resource change to the priced resource.
+// It returns nil if the value is nil or the resource is not charged.
+type Decoder func(ctx *Context, value interface{}) (interface{}, error)
+
+// StateFactory builds the state of a resource change from the decoded before and after resources,
+// at least one of which is not nil, and the action performed.
+type StateFactory func(before, after interface{}, action string) resources.ResourceState
+
+// Grouper collects the changes of the resources reported together (e.g. forwarding rules by load balancer).
+type Grouper interface {
+	// Add decodes the resource change and adds it to its group.
+	Add(ctx *Context, change *tfjson.ResourceChange) error
+	// States returns the states of the groups after all the changes were added,
+	// and the errors of the changes of the groups that could not be built.
+	States() ([]resources.ResourceState, []*resources.ResourceError)
+}
+
+// Checker validates the decoded after value of a resource change that would be applied
+// (e.g. that its zone offers its machine type).
+type Checker func(ctx *Context, after interface{}) error
+
+// Pricer prices the resources of one or more Terraform resource types.
+// Each resource change is decoded with Decode and its state built with NewState,
+// unless NewGrouper is set, in which case all the changes of the pricer types in a plan go to one Grouper.
+// Check is optional and only runs on the after values of create, update and replace changes,
+// so that existing resources are priced even if the class details no longer allow them.
+type Pricer struct {
+	Types      []string
+	Catalogs   []string
+	Decode     Decoder
+	Check      Checker
+	NewState   StateFactory
+	NewGrouper func(plan *tfjson.Plan) Grouper
+}
+
+var (
+	mu     sync.RWMutex
+	byType = map[string]*Pricer{}
+	free   = map[string]bool{}
+)
+
+// Register adds the pricer for its resource types.
+// Registering a type twice is an error, so that built-in pricers are not replaced by mistake.
+func Register(p *Pricer) error {
+	if len(p.Types) == 0 {
+		return fmt.Errorf("pricer has no resource types")
+	}
+	if p.NewGrouper == nil && (p.Decode == nil || p.NewState == nil) {
+		return fmt.Errorf("pricer needs either a decoder and a state factory or a grouper")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, t := range p.Types {
+		if _, ok := byType[t]; ok {
+			return fmt.Errorf("resource type '" + t + "' is already registered")
+		}
+	}
+	for _, t := range p.Types {
+		byType[t] = p
+	}
+	return nil
+}
+
+// MustRegister adds the pricer for its resource types and panics if it can't be registered.
+func MustRegister(p *Pricer) {
+	if err := Register(p); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the pricer of the resource type.
+func Lookup(resourceType string) (*Pricer, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	p, ok := byType[resourceType]
+	return p, ok
+}
+
+// RegisterFree marks resource types as free of charge (e.g. networks or IAM bindings), so that their changes are
+// neither priced nor reported as unpriced. A type ending in '*' marks all the types with that prefix (e.g. random_*),
+// one starting with '*' all the types with that suffix (e.g. *_iam_member).
+func RegisterFree(types ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, t := range types {
+		free[t] = true
+	}
+}
+
+// IsFree returns whether the resource type is marked as free of charge and has no registered pricer.
+func IsFree(resourceType string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	if _, ok := byType[resourceType]; ok {
+		return false
+	}
+	if free[resourceType] {
+		return true
+	}
+	for t := range free {
+		switch {
+		case strings.HasSuffix(t, "*") && strings.HasPrefix(resourceType, strings.TrimSuffix(t, "*")),
+			strings.HasPrefix(t, "*") && strings.HasSuffix(resourceType, strings.TrimPrefix(t, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+// Catalogs returns the billing catalogs needed by the pricers of the resource types, without duplicates.
+// Unsupported resource types are ignored.
+func Catalogs(resourceTypes []string) []string {
+	var catalogs []string
+	seen := map[string]bool{}
+	for _, t := range resourceTypes {
+		p, ok := Lookup(t)
+		if !ok {
+			continue
+		}
+		for _, c := range p.Catalogs {
+			if !seen[c] {
+				seen[c] = true
+				catalogs = append(catalogs, c)
+			}
+		}
+	}
+	return catalogs
+}
@@ -0,0 +1,84 @@
+package registry
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/googleinterns/terraform-cost-estimation/resources"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func decodeNothing(*Context, interface{}) (interface{}, error) { return nil, nil }
+
+func noState(before, after interface{}, action string) resources.ResourceState { return nil }
+
+type noGroups struct{}
+
+func (noGroups) Add(*Context, *tfjson.ResourceChange) error { return nil }
+func (noGroups) States() ([]resources.ResourceState, []*resources.ResourceError) {
+	return nil, nil
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name   string
+		pricer *Pricer
+		err    error
+	}{
+		{"no_types", &Pricer{Decode: decodeNothing, NewState: noState}, fmt.Errorf("pricer has no resource types")},
+		{"no_factory", &Pricer{Types: []string{"test_a"}, Decode: decodeNothing},
+			fmt.Errorf("pricer needs either a decoder and a state factory or a grouper")},
+		{"decoder", &Pricer{Types: []string{"test_a"}, Catalogs: []string{"network"}, Decode: decodeNothing,
+			NewState: noState}, nil},
+		{"grouper", &Pricer{Types: []string{"test_b", "test_c"}, Catalogs: []string{"network", "cloud-sql"},
+			NewGrouper: func(*tfjson.Plan) Grouper { return noGroups{} }}, nil},
+		{"duplicate", &Pricer{Types: []string{"test_d", "test_a"}, Decode: decodeNothing, NewState: noState},
+			fmt.Errorf("resource type 'test_a' is already registered")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := Register(test.pricer); !reflect.DeepEqual(err, test.err) {
+				t.Errorf("Register(%+v) = %+v; want %+v", test.pricer, err, test.err)
+			}
+		})
+	}
+
+	if _, ok := Lookup("test_d"); ok {
+		t.Errorf("Lookup(test_d) found the type of a pricer that failed to register")
+	}
+	if p, ok := Lookup("test_c"); !ok || p.Types[0] != "test_b" {
+		t.Errorf("Lookup(test_c) = %+v, %t; want the grouper pricer", p, ok)
+	}
+
+	catalogs := Catalogs([]string{"test_a", "unsupported", "test_b", "test_c"})
+	if expected := []string{"network", "cloud-sql"}; !reflect.DeepEqual(catalogs, expected) {
+		t.Errorf("Catalogs() = %+v; want %+v", catalogs, expected)
+	}
+}
+
+func TestIsFree(t *testing.T) {
+	MustRegister(&Pricer{Types: []string{"test_free_priced"}, Decode: decodeNothing, NewState: noState})
+	RegisterFree("test_free_network", "test_random_*", "*_test_iam_member", "test_free_priced")
+
+	tests := []struct {
+		name         string
+		resourceType string
+		expected     bool
+	}{
+		{"exact", "test_free_network", true},
+		{"prefix", "test_random_id", true},
+		{"suffix", "google_project_test_iam_member", true},
+		{"not_free", "test_free_subnetwork", false},
+		{"registered_pricer", "test_free_priced", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := IsFree(test.resourceType); actual != test.expected {
+				t.Errorf("IsFree(%s) = %t; want %t", test.resourceType, actual, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	billing "github.com/googleinterns/terraform-cost-estimation/billing"
+	conv "github.com/googleinterns/terraform-cost-estimation/memconverter"
+	cd "github.com/googleinterns/terraform-cost-estimation/resources/classdetail"
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+	"google.golang.org/genproto/googleapis/type/money"
+)
+
+var benchmarkRegions = []string{
+	"asia-east1", "asia-east2", "asia-northeast1", "asia-northeast2", "asia-northeast3", "asia-south1",
+	"asia-southeast1", "asia-southeast2", "australia-southeast1", "europe-north1", "europe-west1", "europe-west2",
+	"europe-west3", "europe-west4", "europe-west6", "northamerica-northeast1", "southamerica-east1",
+	"us-central1", "us-east1", "us-east4", "us-west1", "us-west2", "us-west3", "us-west4",
+}
+
+var benchmarkMachineTypes = []string{
+	"n1-standard-2", "n1-highmem-8", "custom-4-8192", "n2-standard-4", "n2-custom-2-4096", "n2d-standard-8",
+	"e2-standard-2", "e2-custom-2-4096", "c2-standard-8",
+}
+
+// benchmarkSKU builds a single region SKU with one tier rate.
+func benchmarkSKU(description, family, group, usageType, unit, region string) *billingpb.Sku {
+	return &billingpb.Sku{
+		Description:    description,
+		Category:       &billingpb.Category{ResourceFamily: family, ResourceGroup: group, UsageType: usageType},
+		ServiceRegions: []string{region},
+		PricingInfo: []*billingpb.PricingInfo{{PricingExpression: &billingpb.PricingExpression{
+			UsageUnitDescription: unit,
+			TieredRates:          []*billingpb.PricingExpression_TierRate{{UnitPrice: &money.Money{Nanos: 1000000}}},
+		}}},
+	}
+}
+
+// benchmarkCatalog builds a Compute Engine catalog with core, RAM, committed use and disk SKUs
+// of several machine families in every benchmark region, padded with licensing SKUs.
+func benchmarkCatalog() *billing.Catalog {
+	type family struct{ name, group, custom string }
+	families := []family{
+		{"N1 Predefined Instance", "N1Standard", "Custom Instance"},
+		{"N2 Instance", "CPU", "N2 Custom Instance"},
+		{"N2D AMD Instance", "CPU", "N2D AMD Custom Instance"},
+		{"E2 Instance", "CPU", ""},
+		{"Compute optimized", "CPU", ""},
+		{"Memory-optimized Instance", "CPU", ""},
+	}
+
+	var skus []*billingpb.Sku
+	for _, r := range benchmarkRegions {
+		for _, f := range families {
+			ramGroup := "RAM"
+			if f.group == "N1Standard" {
+				ramGroup = "N1Standard"
+			}
+			for _, prefix := range []string{"", "Preemptible "} {
+				usageType := "OnDemand"
+				if prefix != "" {
+					usageType = "Preemptible"
+				}
+				skus = append(skus,
+					benchmarkSKU(prefix+f.name+" Core running in "+r, "Compute", f.group, usageType, "hour", r),
+					benchmarkSKU(prefix+f.name+" Ram running in "+r, "Compute", ramGroup, usageType, "gibibyte hour", r))
+				if f.custom != "" {
+					skus = append(skus,
+						benchmarkSKU(prefix+f.custom+" Core running in "+r, "Compute", "CPU", usageType, "hour", r),
+						benchmarkSKU(prefix+f.custom+" Ram running in "+r, "Compute", "RAM", usageType, "gibibyte hour", r),
+						benchmarkSKU(prefix+f.custom+" Extended Ram running in "+r, "Compute", "RAM", usageType, "gibibyte hour", r))
+				}
+			}
+		}
+
+		for _, years := range []string{"1", "3"} {
+			for _, f := range []string{"", "N2 ", "N2D ", "E2 ", "Compute optimized "} {
+				skus = append(skus,
+					benchmarkSKU("Commitment v1: "+f+"Cpu in "+r+" for "+years+" Year", "Compute", "CPU", "Commit"+years+"Yr", "hour", r),
+					benchmarkSKU("Commitment v1: "+f+"Ram in "+r+" for "+years+" Year", "Compute", "RAM", "Commit"+years+"Yr", "gibibyte hour", r))
+			}
+		}
+
+		skus = append(skus,
+			benchmarkSKU("Storage PD Capacity in "+r, "Storage", "PDStandard", "OnDemand", "gibibyte month", r),
+			benchmarkSKU("Regional Storage PD Capacity in "+r, "Storage", "PDStandard", "OnDemand", "gibibyte month", r),
+			benchmarkSKU("SSD backed PD Capacity in "+r, "Storage", "SSD", "OnDemand", "gibibyte month", r),
+			benchmarkSKU("Regional SSD backed PD Capacity in "+r, "Storage", "SSD", "OnDemand", "gibibyte month", r))
+
+		for i := 0; i < 100; i++ {
+			skus = append(skus, benchmarkSKU(fmt.Sprintf("Licensing Fee for image %d on VM in %s", i, r),
+				"License", "SQLServer", "OnDemand", "hour", r))
+		}
+	}
+	return billing.NewCatalogFromSKUs(map[string][]*billingpb.Sku{billing.ComputeEngineService: skus})
+}
+
+// benchmarkPlan builds n compute instances and n disks spread over the benchmark regions and machine types.
+func benchmarkPlan(b *testing.B, n int) ([]*ComputeInstance, []*ComputeDisk) {
+	details, err := cd.NewResourceDetail()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var instances []*ComputeInstance
+	var disks []*ComputeDisk
+	for i := 0; i < n; i++ {
+		zones := details.RegionZones(benchmarkRegions[i%len(benchmarkRegions)])
+		zone := zones[0]
+		machineType := benchmarkMachineTypes[i%len(benchmarkMachineTypes)]
+		// Use a zone of the region offering the machine type, or an N1 machine type if there is none.
+		for _, z := range zones {
+			if details.CheckMachineZone(machineType, z) == nil {
+				zone = z
+				break
+			}
+		}
+		if details.CheckMachineZone(machineType, zone) != nil {
+			machineType = "n1-standard-2"
+		}
+		usageType := []string{"OnDemand", "Preemptible", "Commit1Yr"}[(i/len(benchmarkMachineTypes))%3]
+		// Committed use discounts don't have custom machine SKUs.
+		if usageType == "Commit1Yr" && strings.Contains(machineType, "custom") {
+			usageType = "OnDemand"
+		}
+
+		instance, err := NewComputeInstance(details, "", fmt.Sprintf("vm-%d", i), machineType, zone, usageType)
+		if err != nil {
+			b.Fatal(err)
+		}
+		instances = append(instances, instance)
+
+		disk, err := NewComputeDisk(details, fmt.Sprintf("disk-%d", i), "", []string{"pd-standard", "pd-ssd"}[i%2],
+			[]string{zone}, "", "", 100)
+		if err != nil {
+			b.Fatal(err)
+		}
+		disks = append(disks, disk)
+	}
+	return instances, disks
+}
+
+// linearPricingInfo fills the pricing information of the instance by filtering all the SKUs of its usage type.
+// It is the lookup used before the SKU index, kept as the benchmark baseline.
+func linearPricingInfo(instance *ComputeInstance, c *billing.ComputeEngineCatalog) error {
+	cores, err := c.GetCoreSKUs(instance.UsageType)
+	if err != nil {
+		return err
+	}
+	mem, err := c.GetRAMSKUs(instance.UsageType)
+	if err != nil {
+		return err
+	}
+
+	filteredCores, err := filterSKUs(cores, instance.Region, instance.Description)
+	if err != nil {
+		return err
+	}
+	filteredRAM, err := filterSKUs(mem, instance.Region, instance.Description)
+	if err != nil {
+		return err
+	}
+
+	if err := instance.Cores.completePricingInfo(filteredCores); err != nil {
+		return err
+	}
+	return instance.Memory.completePricingInfo(filteredRAM)
+}
+
+// linearDiskPricingInfo fills the pricing information of the disk by filtering all the SKUs of its resource group.
+func linearDiskPricingInfo(disk *ComputeDisk, c *billing.ComputeEngineCatalog) error {
+	skus, err := c.DiskSKUs(disk.Type)
+	if err != nil {
+		return err
+	}
+	filtered, err := filterSKUs(skus, disk.Region, disk.Description)
+	if err != nil {
+		return err
+	}
+
+	disk.UnitPricing.fillMonthlyBase(filtered[0], func(tr *billingpb.PricingExpression_TierRate) bool {
+		return int64(tr.StartUsageAmount) <= disk.SizeGiB
+	}, hourlyToMonthly)
+	_, err = conv.Convert("gib", 0, disk.UnitPricing.UsageUnit)
+	return err
+}
+
+func BenchmarkComputeInstancePricing(b *testing.B) {
+	catalog := benchmarkCatalog()
+	c, err := catalog.ComputeEngine()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, n := range []int{100, 1000, 10000} {
+		instances, _ := benchmarkPlan(b, n)
+
+		b.Run(fmt.Sprintf("linear/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, instance := range instances {
+					if err := linearPricingInfo(instance, c); err != nil {
+						b.Fatal(instance.MachineType, ": ", err)
+					}
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("indexed/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, instance := range instances {
+					if err := instance.CompletePricingInfo(catalog); err != nil {
+						b.Fatal(instance.MachineType, ": ", err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkComputeDiskPricing(b *testing.B) {
+	catalog := benchmarkCatalog()
+	c, err := catalog.ComputeEngine()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, n := range []int{100, 1000, 10000} {
+		_, disks := benchmarkPlan(b, n)
+
+		b.Run(fmt.Sprintf("linear/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, disk := range disks {
+					if err := linearDiskPricingInfo(disk, c); err != nil {
+						b.Fatal(disk.Type, ": ", err)
+					}
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("indexed/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, disk := range disks {
+					if err := disk.completePricingInfo(catalog); err != nil {
+						b.Fatal(disk.Type, ": ", err)
+					}
+				}
+			}
+		})
+	}
+}
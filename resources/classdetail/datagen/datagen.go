@@ -0,0 +1,260 @@
+// Package datagen regenerates the data files of the classdetail packages (machine_types.json, machine_type_zones.json,
+// compute_disk_types.json and compute_images.json) from exports of the Compute Engine API: the responses
+// of machineTypes.aggregatedList, diskTypes.aggregatedList and images.list. The generated data is validated
+// and can be compared with the current files to review what changed.
+package datagen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/googleinterns/terraform-cost-estimation/resources/classdetail/instance"
+)
+
+// readPages reads the API responses of the file: a single response or an array of responses (pages).
+func readPages(path string, page func(data []byte) error) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var pages []json.RawMessage
+	if err := json.Unmarshal(data, &pages); err != nil {
+		pages = []json.RawMessage{data}
+	}
+	for _, p := range pages {
+		if err := page(p); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// lastSegment returns the name of the resource of an API URL (e.g. the zone of .../zones/us-central1-a).
+func lastSegment(url string) string {
+	return url[strings.LastIndex(url, "/")+1:]
+}
+
+type machineTypeExport struct {
+	Name      string
+	GuestCpus int
+	MemoryMb  int
+	Zone      string
+}
+
+// MachineTypes reads the machineTypes.aggregatedList exports and returns the machine types by name
+// and the sorted zones offering each of them. A machine type must have the same number of vCPUs and memory
+// in every zone.
+func MachineTypes(paths ...string) (map[string]instance.ComputeInstanceInfo, map[string][]string, error) {
+	out := map[string]instance.ComputeInstanceInfo{}
+	offered := map[string]map[string]bool{}
+	for _, path := range paths {
+		err := readPages(path, func(data []byte) error {
+			var resp struct {
+				Items map[string]struct{ MachineTypes []machineTypeExport }
+			}
+			if err := json.Unmarshal(data, &resp); err != nil {
+				return err
+			}
+			for scope, items := range resp.Items {
+				for _, m := range items.MachineTypes {
+					if m.Name == "" || m.GuestCpus <= 0 || m.MemoryMb <= 0 {
+						return fmt.Errorf("invalid machine type %q in %s: %d vCPUs, %d MB", m.Name, scope, m.GuestCpus, m.MemoryMb)
+					}
+					info := instance.ComputeInstanceInfo{CoreNumber: m.GuestCpus, MemoryGiB: math.Round(float64(m.MemoryMb)/1024*100) / 100}
+					if old, ok := out[m.Name]; ok && old != info {
+						return fmt.Errorf("machine type %s has %+v in %s and %+v in another zone", m.Name, info, scope, old)
+					}
+					out[m.Name] = info
+
+					zone := lastSegment(m.Zone)
+					if zone == "" {
+						zone = strings.TrimPrefix(scope, "zones/")
+					}
+					if offered[m.Name] == nil {
+						offered[m.Name] = map[string]bool{}
+					}
+					offered[m.Name][zone] = true
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(out) == 0 {
+		return nil, nil, fmt.Errorf("found no machine types in %s", strings.Join(paths, ", "))
+	}
+
+	zones := map[string][]string{}
+	for name, found := range offered {
+		for z := range found {
+			zones[name] = append(zones[name], z)
+		}
+		sort.Strings(zones[name])
+	}
+	return out, zones, nil
+}
+
+// DiskType is a disk type of a zone or region, in the format of compute_disk_types.json.
+type DiskType struct {
+	CreationTimestamp string `json:"creationTimestamp"`
+	DefaultDiskSizeGb string `json:"defaultDiskSizeGb"`
+	Description       string `json:"description"`
+	Kind              string `json:"kind"`
+	Name              string `json:"name"`
+	Region            string `json:"region,omitempty"`
+	SelfLink          string `json:"selfLink"`
+	ValidDiskSize     string `json:"validDiskSize"`
+	Zone              string `json:"zone,omitempty"`
+}
+
+// Location returns the zone or region of the disk type.
+func (d DiskType) Location() string {
+	if d.Zone != "" {
+		return d.Zone
+	}
+	return d.Region
+}
+
+// sizes returns the default, minimum and maximum size of the disk type in GB.
+func (d DiskType) sizes() (def, min, max int64, err error) {
+	if def, err = strconv.ParseInt(d.DefaultDiskSizeGb, 10, 64); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid default size %q", d.DefaultDiskSizeGb)
+	}
+	parts := strings.Split(d.ValidDiskSize, "-")
+	if len(parts) != 2 || !strings.HasSuffix(parts[0], "GB") || !strings.HasSuffix(parts[1], "GB") {
+		return 0, 0, 0, fmt.Errorf("invalid size interval %q", d.ValidDiskSize)
+	}
+	if min, err = strconv.ParseInt(strings.TrimSuffix(parts[0], "GB"), 10, 64); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid size interval %q", d.ValidDiskSize)
+	}
+	if max, err = strconv.ParseInt(strings.TrimSuffix(parts[1], "GB"), 10, 64); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid size interval %q", d.ValidDiskSize)
+	}
+	if min > max || def < min || def > max {
+		return 0, 0, 0, fmt.Errorf("default size %d out of the size interval %q", def, d.ValidDiskSize)
+	}
+	return
+}
+
+// DiskTypes reads the diskTypes.aggregatedList exports and returns the disk types of every zone and region,
+// sorted by location and name. The zones, regions and self links are shortened to their names.
+func DiskTypes(paths ...string) ([]DiskType, error) {
+	byKey := map[string]DiskType{}
+	for _, path := range paths {
+		err := readPages(path, func(data []byte) error {
+			var resp struct {
+				Items map[string]struct{ DiskTypes []DiskType }
+			}
+			if err := json.Unmarshal(data, &resp); err != nil {
+				return err
+			}
+			for scope, items := range resp.Items {
+				for _, d := range items.DiskTypes {
+					d.Zone, d.Region = lastSegment(d.Zone), lastSegment(d.Region)
+					d.SelfLink = d.Location() + "/diskTypes/" + d.Name
+					if d.Name == "" || d.Location() == "" {
+						return fmt.Errorf("disk type %q in %s has no name or location", d.Name, scope)
+					}
+					if _, _, _, err := d.sizes(); err != nil {
+						return fmt.Errorf("disk type %s in %s: %v", d.Name, d.Location(), err)
+					}
+					byKey[d.Location()+"/"+d.Name] = d
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(byKey) == 0 {
+		return nil, fmt.Errorf("found no disk types in %s", strings.Join(paths, ", "))
+	}
+
+	out := make([]DiskType, 0, len(byKey))
+	for _, d := range byKey {
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Location() != out[j].Location() {
+			return out[i].Location() < out[j].Location()
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
+
+// Image is a compute image, in the format of compute_images.json.
+type Image struct {
+	CreationTimestamp string `json:"creationTimestamp"`
+	Image             string `json:"image"`
+	Family            string `json:"family"`
+	DiskSizeGib       int64  `json:"diskSizeGib"`
+}
+
+type imageExport struct {
+	Name              string
+	Family            string
+	DiskSizeGb        string
+	CreationTimestamp string
+	Deprecated        *struct{ State string }
+}
+
+// Images reads the images.list exports (e.g. one per image project) and returns the images sorted by name.
+// Obsolete and deleted images are left out, since they can't be used to create disks.
+func Images(paths ...string) ([]Image, error) {
+	byName := map[string]Image{}
+	for _, path := range paths {
+		err := readPages(path, func(data []byte) error {
+			var resp struct{ Items []imageExport }
+			if err := json.Unmarshal(data, &resp); err != nil {
+				return err
+			}
+			for _, img := range resp.Items {
+				if img.Deprecated != nil && (img.Deprecated.State == "OBSOLETE" || img.Deprecated.State == "DELETED") {
+					continue
+				}
+				size, err := strconv.ParseInt(img.DiskSizeGb, 10, 64)
+				if img.Name == "" || err != nil || size <= 0 {
+					return fmt.Errorf("invalid image %q of disk size %q", img.Name, img.DiskSizeGb)
+				}
+				if _, err := time.Parse(time.RFC3339, img.CreationTimestamp); err != nil {
+					return fmt.Errorf("image %s has an invalid creation timestamp %q", img.Name, img.CreationTimestamp)
+				}
+				byName[img.Name] = Image{CreationTimestamp: img.CreationTimestamp, Image: img.Name, Family: img.Family, DiskSizeGib: size}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(byName) == 0 {
+		return nil, fmt.Errorf("found no images in %s", strings.Join(paths, ", "))
+	}
+
+	out := make([]Image, 0, len(byName))
+	for _, img := range byName {
+		out = append(out, img)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Image < out[j].Image })
+	return out, nil
+}
+
+// Marshal returns the indented json of the data, as written to the data files.
+func Marshal(v interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,151 @@
+package datagen
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	cd "github.com/googleinterns/terraform-cost-estimation/resources/classdetail"
+	"github.com/googleinterns/terraform-cost-estimation/resources/classdetail/instance"
+)
+
+func TestMachineTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected map[string]instance.ComputeInstanceInfo
+		zones    map[string][]string
+		ok       bool
+	}{
+		{"aggregated_list", "testdata/machine_types.json", map[string]instance.ComputeInstanceInfo{
+			"f1-micro":      {CoreNumber: 1, MemoryGiB: 0.6},
+			"n1-standard-1": {CoreNumber: 1, MemoryGiB: 3.75},
+			"n1-highcpu-2":  {CoreNumber: 2, MemoryGiB: 1.8},
+		}, map[string][]string{
+			"f1-micro":      {"us-central1-a"},
+			"n1-standard-1": {"europe-west1-b", "us-central1-a"},
+			"n1-highcpu-2":  {"europe-west1-b"},
+		}, true},
+		{"inconsistent_zones", "testdata/machine_types_inconsistent.json", nil, nil, false},
+		{"invalid_export", "testdata/images.json", nil, nil, false},
+		{"missing_file", "testdata/none.json", nil, nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, zones, err := MachineTypes(test.path)
+			if (err == nil) != test.ok || !reflect.DeepEqual(actual, test.expected) || !reflect.DeepEqual(zones, test.zones) {
+				t.Errorf("MachineTypes(%q) = %+v, %v, %v; want %+v, %v, ok %t",
+					test.path, actual, zones, err, test.expected, test.zones, test.ok)
+			}
+		})
+	}
+}
+
+func TestDiskTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected []DiskType
+		ok       bool
+	}{
+		{"aggregated_list_pages", "testdata/disk_types.json", []DiskType{
+			{CreationTimestamp: "1969-12-31T16:00:00.000-08:00", DefaultDiskSizeGb: "100", Description: "SSD Persistent Disk",
+				Kind: "compute#diskType", Name: "pd-ssd", Region: "us-central1", SelfLink: "us-central1/diskTypes/pd-ssd",
+				ValidDiskSize: "10GB-65536GB"},
+			{CreationTimestamp: "1969-12-31T16:00:00.000-08:00", DefaultDiskSizeGb: "500", Description: "Standard Persistent Disk",
+				Kind: "compute#diskType", Name: "pd-standard", SelfLink: "us-central1-a/diskTypes/pd-standard",
+				ValidDiskSize: "10GB-65536GB", Zone: "us-central1-a"},
+		}, true},
+		{"default_size_out_of_interval", "testdata/disk_types_invalid.json", nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := DiskTypes(test.path)
+			if (err == nil) != test.ok || !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("DiskTypes(%q) = %+v, %v; want %+v, ok %t", test.path, actual, err, test.expected, test.ok)
+			}
+		})
+	}
+}
+
+func TestImages(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected []Image
+		ok       bool
+	}{
+		{"without_obsolete", "testdata/images.json", []Image{
+			{CreationTimestamp: "2020-09-02T10:42:38.697-07:00", Image: "centos-7-v20200902", Family: "centos-7", DiskSizeGib: 20},
+			{CreationTimestamp: "2020-09-10T10:12:18.386-07:00", Image: "debian-10-buster-v20200910", Family: "debian-10", DiskSizeGib: 10},
+		}, true},
+		{"invalid_timestamp", "testdata/images_invalid.json", nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := Images(test.path)
+			if (err == nil) != test.ok || !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Images(%q) = %+v, %v; want %+v, ok %t", test.path, actual, err, test.expected, test.ok)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := map[string]instance.ComputeInstanceInfo{
+		"n1-standard-1": {CoreNumber: 1, MemoryGiB: 3.75},
+		"n1-highcpu-2":  {CoreNumber: 2, MemoryGiB: 1.8},
+		"g1-small":      {CoreNumber: 1, MemoryGiB: 1.7},
+	}
+	after := map[string]instance.ComputeInstanceInfo{
+		"n1-standard-1": {CoreNumber: 1, MemoryGiB: 3.75},
+		"n1-highcpu-2":  {CoreNumber: 2, MemoryGiB: 2},
+		"e2-micro":      {CoreNumber: 2, MemoryGiB: 1},
+	}
+	expected := []Change{
+		{Kind: Added, Key: "e2-micro", After: "2 vCPUs, 1.00 GiB"},
+		{Kind: Removed, Key: "g1-small", Before: "1 vCPUs, 1.70 GiB"},
+		{Kind: Changed, Key: "n1-highcpu-2", Before: "2 vCPUs, 1.80 GiB", After: "2 vCPUs, 2.00 GiB"},
+	}
+	if actual := DiffMachineTypes(before, after); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("DiffMachineTypes() = %+v, want %+v", actual, expected)
+	}
+	if actual := DiffMachineTypes(after, after); len(actual) != 0 {
+		t.Errorf("DiffMachineTypes() of equal machine types = %+v, want no changes", actual)
+	}
+
+	zones := DiffMachineTypeZones(map[string][]string{"c2-standard-8": {"us-central1-a", "us-central1-b"}},
+		map[string][]string{"c2-standard-8": {"us-central1-b", "us-central1-c"}})
+	expectedZones := []Change{
+		{Kind: Removed, Key: "us-central1-a/c2-standard-8", Before: "offered"},
+		{Kind: Added, Key: "us-central1-c/c2-standard-8", After: "offered"},
+	}
+	if !reflect.DeepEqual(zones, expectedZones) {
+		t.Errorf("DiffMachineTypeZones() = %+v, want %+v", zones, expectedZones)
+	}
+
+	lines := []string{"+ e2-micro: 2 vCPUs, 1.00 GiB", "- g1-small: 1 vCPUs, 1.70 GiB", "~ n1-highcpu-2: 2 vCPUs, 1.80 GiB -> 2 vCPUs, 2.00 GiB"}
+	for i, c := range expected {
+		if c.String() != lines[i] {
+			t.Errorf("Change.String() = %q, want %q", c.String(), lines[i])
+		}
+	}
+}
+
+func TestCurrentDataFiles(t *testing.T) {
+	// The current data files must read back in the formats of the generated ones.
+	var disks []DiskType
+	if err := ReadFile(filepath.Join("..", cd.DiskTypesFile), &disks); err != nil || len(disks) == 0 {
+		t.Fatalf("ReadFile(%s) = %d disk types, %v", cd.DiskTypesFile, len(disks), err)
+	}
+	if changes := DiffDiskTypes(disks, disks); len(changes) != 0 {
+		t.Errorf("DiffDiskTypes() of equal disk types = %+v, want no changes", changes)
+	}
+	var images []Image
+	if err := ReadFile(filepath.Join("..", cd.ImagesFile), &images); err != nil || len(images) == 0 {
+		t.Fatalf("ReadFile(%s) = %d images, %v", cd.ImagesFile, len(images), err)
+	}
+}
@@ -0,0 +1,125 @@
+package datagen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/googleinterns/terraform-cost-estimation/resources/classdetail/instance"
+)
+
+// The kinds of changes of a data file entry.
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change is a change of an entry of a data file: a machine type, a disk type of a location or an image.
+// Before is empty for added entries and After for removed ones.
+type Change struct {
+	Kind   string `json:"change"`
+	Key    string `json:"key"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// String returns the change as a line of a diff.
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("+ %s: %s", c.Key, c.After)
+	case Removed:
+		return fmt.Sprintf("- %s: %s", c.Key, c.Before)
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", c.Key, c.Before, c.After)
+	}
+}
+
+// diff returns the changes between the entries described by key, sorted by key.
+func diff(before, after map[string]string) []Change {
+	changes := []Change{}
+	for k, b := range before {
+		if a, ok := after[k]; !ok {
+			changes = append(changes, Change{Kind: Removed, Key: k, Before: b})
+		} else if a != b {
+			changes = append(changes, Change{Kind: Changed, Key: k, Before: b, After: a})
+		}
+	}
+	for k, a := range after {
+		if _, ok := before[k]; !ok {
+			changes = append(changes, Change{Kind: Added, Key: k, After: a})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// DiffMachineTypes returns the changes of the machine types, sorted by name.
+func DiffMachineTypes(before, after map[string]instance.ComputeInstanceInfo) []Change {
+	describe := func(m map[string]instance.ComputeInstanceInfo) map[string]string {
+		d := map[string]string{}
+		for name, info := range m {
+			d[name] = fmt.Sprintf("%d vCPUs, %.2f GiB", info.CoreNumber, info.MemoryGiB)
+		}
+		return d
+	}
+	return diff(describe(before), describe(after))
+}
+
+// DiffMachineTypeZones returns the changes of the zones offering the machine types, one per zone and machine type,
+// sorted by zone and machine type.
+func DiffMachineTypeZones(before, after map[string][]string) []Change {
+	describe := func(m map[string][]string) map[string]string {
+		d := map[string]string{}
+		for name, zones := range m {
+			for _, z := range zones {
+				d[z+"/"+name] = "offered"
+			}
+		}
+		return d
+	}
+	return diff(describe(before), describe(after))
+}
+
+// DiffDiskTypes returns the changes of the disk types, sorted by location and name.
+func DiffDiskTypes(before, after []DiskType) []Change {
+	describe := func(l []DiskType) map[string]string {
+		d := map[string]string{}
+		for _, t := range l {
+			d[t.Location()+"/"+t.Name] = fmt.Sprintf("default %sGB, valid %s", t.DefaultDiskSizeGb, t.ValidDiskSize)
+		}
+		return d
+	}
+	return diff(describe(before), describe(after))
+}
+
+// DiffImages returns the changes of the images, sorted by name.
+func DiffImages(before, after []Image) []Change {
+	describe := func(l []Image) map[string]string {
+		d := map[string]string{}
+		for _, img := range l {
+			d[img.Image] = fmt.Sprintf("family %s, %d GiB", img.Family, img.DiskSizeGib)
+		}
+		return d
+	}
+	return diff(describe(before), describe(after))
+}
+
+// ReadFile unmarshals the data file at the path into v. A missing file leaves v unchanged,
+// so that all the entries of a new file are added.
+func ReadFile(path string, v interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
@@ -1,47 +1,162 @@
 package classdetail
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
 	"github.com/googleinterns/terraform-cost-estimation/resources/classdetail/disk"
 	"github.com/googleinterns/terraform-cost-estimation/resources/classdetail/image"
 	"github.com/googleinterns/terraform-cost-estimation/resources/classdetail/instance"
 )
 
+// The paths of the data files in a data directory, in the layout of the classdetail source directory.
+var (
+	MachineTypesFile = filepath.Join("instance", instance.DataFile)
+	MachineZonesFile = filepath.Join("instance", instance.ZonesDataFile)
+	DiskTypesFile    = filepath.Join("disk", disk.DataFile)
+	ImagesFile       = filepath.Join("image", image.DataFile)
+)
+
+// DataMode is how the data files of a data directory are combined with the embedded ones.
+type DataMode int
+
+const (
+	// Overlay adds the entries of the data files to the embedded ones, replacing those of the same name
+	// (and zone/region for disk types), e.g. to add private images or newly launched machine types.
+	Overlay DataMode = iota
+	// Replace uses the data files instead of the embedded ones.
+	Replace
+)
+
 // ResourceDetail holds information about resource details.
 // Disk type information is stored first by disk type, then by zone/region.
 type ResourceDetail struct {
 	diskInfo     map[string]map[string]*disk.Disk
 	imageInfo    *image.ImageInfo
 	instanceInfo map[string]instance.ComputeInstanceInfo
+	machineZones map[string][]string
+	version      string
 }
 
-// NewResourceDetail builds a ResourceDetail object.
+// NewResourceDetail builds a ResourceDetail object from the data embedded in the binary.
 func NewResourceDetail() (*ResourceDetail, error) {
-	rd := &ResourceDetail{}
+	return LoadResourceDetail("", Overlay)
+}
+
+// LoadResourceDetail builds a ResourceDetail object from the embedded data combined with the data files of dir
+// (instance/machine_types.json, instance/machine_type_zones.json, disk/compute_disk_types.json and
+// image/compute_images.json) as given by mode.
+// The embedded data is used for the files missing from dir, which must have at least one of them.
+// An empty dir uses the embedded data only.
+func LoadResourceDetail(dir string, mode DataMode) (*ResourceDetail, error) {
+	embedded := map[string][]byte{
+		MachineTypesFile: instance.EmbeddedData(),
+		MachineZonesFile: instance.EmbeddedZonesData(),
+		DiskTypesFile:    disk.EmbeddedData(),
+		ImagesFile:       image.EmbeddedData(),
+	}
+	files := map[string][][]byte{}
+	external := map[string][]byte{}
+	for name, data := range embedded {
+		files[name] = [][]byte{data}
+		if dir == "" {
+			continue
+		}
+		ext, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		external[name] = ext
+		if mode == Replace {
+			files[name] = [][]byte{ext}
+		} else {
+			files[name] = append(files[name], ext)
+		}
+	}
+	if dir != "" && len(external) == 0 {
+		return nil, fmt.Errorf("no class detail data files in %s", dir)
+	}
+
+	rd := &ResourceDetail{version: dataVersion(embedded, external, mode)}
 
 	// Initialize disk information.
-	d, err := disk.ReadDiskInfo()
+	d, err := disk.ParseDiskInfo(files[DiskTypesFile]...)
 	if err != nil {
 		return nil, err
 	}
 	rd.diskInfo = d
 
 	// Initialize image information.
-	i, err := image.ReadComputeImagesInfo()
+	i, err := image.ParseComputeImagesInfo(files[ImagesFile]...)
 	if err != nil {
 		return nil, err
 	}
 	rd.imageInfo = i
 
 	// Initialize compute instance machine type information.
-	m, err := instance.ReadMachineTypes()
+	m, err := instance.ParseMachineTypes(files[MachineTypesFile]...)
 	if err != nil {
 		return nil, err
 	}
 	rd.instanceInfo = m
 
+	// Initialize the zones offering each machine type.
+	z, err := instance.ParseMachineTypeZones(files[MachineZonesFile]...)
+	if err != nil {
+		return nil, err
+	}
+	rd.machineZones = z
+
 	return rd, nil
 }
 
+// dataHash returns the short hash of the content of the data files.
+func dataHash(files map[string][]byte) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s\x00%d\x00", filepath.ToSlash(name), len(files[name]))
+		h.Write(files[name])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// dataVersion returns the version of the data: the hash of the embedded data files, if any is used,
+// followed by the hash of the external ones, e.g. embedded-1f0c83a4d2b7+overlay-6be02a9153cd.
+func dataVersion(embedded, external map[string][]byte, mode DataMode) string {
+	if len(external) == 0 {
+		return "embedded-" + dataHash(embedded)
+	}
+	if mode == Replace && len(external) == len(embedded) {
+		return "custom-" + dataHash(external)
+	}
+	kind := "overlay-"
+	if mode == Replace {
+		kind = "custom-"
+	}
+	return "embedded-" + dataHash(embedded) + "+" + kind + dataHash(external)
+}
+
+// Version returns the version of the data the details are read from, reported with the cost estimations.
+// It changes with the content of the embedded and external data files.
+func (rd *ResourceDetail) Version() string {
+	return rd.version
+}
+
 // DiskDetails returns default, minimum and maximum size (in GiB) of a disk type running in the specific zone or region.
 func (rd *ResourceDetail) DiskDetails(diskType, zone, region string) (int64, int64, int64, error) {
 	return disk.Details(rd.diskInfo, diskType, zone, region)
@@ -57,7 +172,33 @@ func (rd *ResourceDetail) MachineDetails(machineType string) (coreNum int, memGi
 	return instance.GetMachineDetails(rd.instanceInfo, machineType)
 }
 
+// CheckMachineZone returns an error listing nearby zones offering the machine type if it is not offered in the zone.
+func (rd *ResourceDetail) CheckMachineZone(machineType, zone string) error {
+	return instance.CheckZone(rd.machineZones, machineType, zone)
+}
+
+// CheckCustomShape returns an error and the closest valid shape (empty if there is none) if the custom
+// machine type breaks the rules of its family.
+func (rd *ResourceDetail) CheckCustomShape(machineType string) (closest string, err error) {
+	err = instance.CheckCustomShape(machineType)
+	var shapeErr *instance.CustomShapeError
+	if errors.As(err, &shapeErr) {
+		closest = shapeErr.Closest
+	}
+	return closest, err
+}
+
 // MachineFractionalCore returns the fractional core value of a compute instance type.
 func (rd *ResourceDetail) MachineFractionalCore(machineType string) float64 {
 	return instance.GetMachineFractionalCore(machineType)
 }
+
+// SQLTierDetails returns the number of cores, amount of memory (in GiB) and whether a Cloud SQL tier is shared core.
+func (rd *ResourceDetail) SQLTierDetails(tier string) (coreNum int, memGiB float64, sharedCore bool, err error) {
+	return instance.GetSQLTierDetails(rd.instanceInfo, tier)
+}
+
+// RegionZones returns the sorted zones of a region.
+func (rd *ResourceDetail) RegionZones(region string) []string {
+	return disk.RegionZones(rd.diskInfo, region)
+}
@@ -0,0 +1,60 @@
+package classdetail
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadResourceDetail(t *testing.T) {
+	embedded, err := NewResourceDetail()
+	if err != nil {
+		t.Fatalf("NewResourceDetail() failed: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		dir         string
+		mode        DataMode
+		machineType string
+		memGiB      float64
+		image       string
+		imageSize   int64
+		diskType    string
+		ok          bool
+		version     string
+	}{
+		{"embedded", "", Overlay, "n1-standard-1", 3.75, "debian-10", 10, "pd-ssd", true, "embedded-"},
+		{"overlay_new_machine_type", "testdata/overlay", Overlay, "n4-standard-2", 8, "debian-10", 10, "pd-ssd", true, "+overlay-"},
+		{"overlay_replaced_machine_type", "testdata/overlay", Overlay, "n1-standard-1", 4, "private-app", 30, "pd-ssd", true, "+overlay-"},
+		{"replace_without_embedded_machine_types", "testdata/overlay", Replace, "n1-standard-2", 0, "debian-10", 0, "pd-ssd", false, "+custom-"},
+		{"replace_keeps_embedded_disk_types", "testdata/overlay", Replace, "n4-standard-2", 8, "private-app", 30, "pd-ssd", true, "+custom-"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rd, err := LoadResourceDetail(test.dir, test.mode)
+			if err != nil {
+				t.Fatalf("LoadResourceDetail(%q) failed: %v", test.dir, err)
+			}
+
+			_, mem, err1 := rd.MachineDetails(test.machineType)
+			size, err2 := rd.ImageSize(test.image)
+			_, _, _, err3 := rd.DiskDetails(test.diskType, "us-central1-a", "")
+			ok := err1 == nil && err2 == nil && err3 == nil
+			if ok != test.ok || ok && (mem != test.memGiB || size != test.imageSize) {
+				t.Errorf("details of %s, %s, %s = %f GiB, %d GiB (%v, %v, %v); want %f GiB, %d GiB, ok %t",
+					test.machineType, test.image, test.diskType, mem, size, err1, err2, err3, test.memGiB, test.imageSize, test.ok)
+			}
+			if !strings.Contains(rd.Version(), test.version) {
+				t.Errorf("Version() = %q, want it to contain %q", rd.Version(), test.version)
+			}
+			if test.dir != "" && rd.Version() == embedded.Version() {
+				t.Errorf("Version() = %q, the version of the embedded data", rd.Version())
+			}
+		})
+	}
+
+	if _, err := LoadResourceDetail("testdata", Overlay); err == nil {
+		t.Errorf("LoadResourceDetail() of a directory without data files succeeded")
+	}
+}
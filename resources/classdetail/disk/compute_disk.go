@@ -1,15 +1,22 @@
 package disk
 
 import (
+	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
-	"path/filepath"
-	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 )
 
+var (
+	// ErrUnknownDiskType is wrapped by the errors of the disk types missing from the disk details.
+	ErrUnknownDiskType = errors.New("invalid disk type")
+	// ErrUnknownLocation is wrapped by the errors of the locations where a disk type doesn't run.
+	ErrUnknownLocation = errors.New("no disk type running in")
+)
+
 type diskJSON struct {
 	Name              string
 	Region            string
@@ -58,36 +65,46 @@ func convertToDisk(d diskJSON) (*Disk, error) {
 	return final, nil
 }
 
-// ReadDiskInfo reads the JSON file with disk information.
-func ReadDiskInfo() (map[string]map[string]*Disk, error) {
-	var diskTypes map[string]map[string]*Disk
+// DataFile is the name of the data file of the disk types, embedded in the binary.
+const DataFile = "compute_disk_types.json"
 
-	// Get json file path relative to this directory.
-	_, callerFile, _, _ := runtime.Caller(0)
-	inputPath := filepath.Dir(callerFile) + "/compute_disk_types.json"
+//go:embed compute_disk_types.json
+var diskTypesJSON []byte
 
-	data, err := ioutil.ReadFile(inputPath)
-	if err != nil {
-		return nil, err
-	}
+// EmbeddedData returns the content of the embedded data file of the disk types.
+func EmbeddedData() []byte {
+	return diskTypesJSON
+}
 
-	var jsonMap []diskJSON
-	json.Unmarshal(data, &jsonMap)
+// ReadDiskInfo reads the embedded disk information.
+func ReadDiskInfo() (map[string]map[string]*Disk, error) {
+	return ParseDiskInfo(diskTypesJSON)
+}
 
-	diskTypes = map[string]map[string]*Disk{}
-	for _, d := range jsonMap {
-		d2, err := convertToDisk(d)
-		if err != nil {
-			return nil, err
-		}
-		if diskTypes[d.Name] == nil {
-			diskTypes[d.Name] = map[string]*Disk{}
+// ParseDiskInfo reads the disk information of the JSON data files, in the format of compute_disk_types.json.
+// The disk types of a later file replace those of the same name and zone/region of the earlier ones.
+func ParseDiskInfo(files ...[]byte) (map[string]map[string]*Disk, error) {
+	diskTypes := map[string]map[string]*Disk{}
+	for _, data := range files {
+		var jsonMap []diskJSON
+		if err := json.Unmarshal(data, &jsonMap); err != nil {
+			return nil, fmt.Errorf("invalid disk types data: %v", err)
 		}
 
-		if d.Zone != "" {
-			diskTypes[d.Name][d.Zone] = d2
-		} else {
-			diskTypes[d.Name][d.Region] = d2
+		for _, d := range jsonMap {
+			d2, err := convertToDisk(d)
+			if err != nil {
+				return nil, err
+			}
+			if diskTypes[d.Name] == nil {
+				diskTypes[d.Name] = map[string]*Disk{}
+			}
+
+			if d.Zone != "" {
+				diskTypes[d.Name][d.Zone] = d2
+			} else {
+				diskTypes[d.Name][d.Region] = d2
+			}
 		}
 	}
 
@@ -103,15 +120,34 @@ func Details(diskTypes map[string]map[string]*Disk, diskType, zone, region strin
 
 	d1, ok := diskTypes[diskType]
 	if !ok {
-		return 0, 0, 0, fmt.Errorf("invalid disk type '" + diskType + "'")
+		return 0, 0, 0, fmt.Errorf("%w '%s'", ErrUnknownDiskType, diskType)
 	}
 
 	d2, ok := d1[zone]
 	if !ok {
 		d2, ok = d1[region]
 		if !ok {
-			return 0, 0, 0, fmt.Errorf("no disk type running in '" + region + "'")
+			return 0, 0, 0, fmt.Errorf("%w '%s'", ErrUnknownLocation, region)
 		}
 	}
 	return d2.DefaultSizeGiB, d2.MinSize, d2.MaxSize, nil
 }
+
+// RegionZones returns the sorted zones of a region in which any disk type is available.
+func RegionZones(diskTypes map[string]map[string]*Disk, region string) []string {
+	found := map[string]bool{}
+	for _, locations := range diskTypes {
+		for _, d := range locations {
+			if strings.HasPrefix(d.Zone, region+"-") && !strings.Contains(d.Zone[len(region)+1:], "-") {
+				found[d.Zone] = true
+			}
+		}
+	}
+
+	var zones []string
+	for z := range found {
+		zones = append(zones, z)
+	}
+	sort.Strings(zones)
+	return zones
+}
@@ -22,8 +22,8 @@ func TestDetails(t *testing.T) {
 		max      int64
 		err      error
 	}{
-		{"invalid_type", "pd", "", "", 0, 0, 0, fmt.Errorf("invalid disk type 'pd'")},
-		{"invalid_location", "pd-standard", "", "", 0, 0, 0, fmt.Errorf("no disk type running in ''")},
+		{"invalid_type", "pd", "", "", 0, 0, 0, fmt.Errorf("%w 'pd'", ErrUnknownDiskType)},
+		{"invalid_location", "pd-standard", "", "", 0, 0, 0, fmt.Errorf("%w ''", ErrUnknownLocation)},
 		{"region_0", "pd-standard", "", "us-central1", 500, 200, 65536, nil},
 		{"region_1", "pd-ssd", "", "us-central1", 100, 10, 65536, nil},
 		{"region_2", "pd-balanced", "", "us-central1", 100, 10, 65536, nil},
@@ -44,3 +44,27 @@ func TestDetails(t *testing.T) {
 		})
 	}
 }
+
+func TestRegionZones(t *testing.T) {
+	diskTypes, err := ReadDiskInfo()
+	if err != nil {
+		t.Fatal("could not read disk information")
+	}
+
+	tests := []struct {
+		region string
+		zones  []string
+	}{
+		{"us-central1", []string{"us-central1-a", "us-central1-b", "us-central1-c", "us-central1-d", "us-central1-f"}},
+		{"europe-west1", []string{"europe-west1-b", "europe-west1-c", "europe-west1-d"}},
+		{"us-central", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.region, func(t *testing.T) {
+			if zones := RegionZones(diskTypes, test.region); !reflect.DeepEqual(zones, test.zones) {
+				t.Errorf("RegionZones(%s) = %+v; want %+v", test.region, zones, test.zones)
+			}
+		})
+	}
+}
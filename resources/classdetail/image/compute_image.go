@@ -1,11 +1,9 @@
 package image
 
 import (
+	_ "embed"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"path/filepath"
-	"runtime"
 	"sort"
 	"strings"
 )
@@ -23,24 +21,40 @@ type ImageInfo struct {
 	imagesDiskSize map[string]int64
 }
 
-// ReadComputeImagesInfo reads the JSON file with information about compute images.
+// DataFile is the name of the data file of the compute images, embedded in the binary.
+const DataFile = "compute_images.json"
+
+//go:embed compute_images.json
+var imagesJSON []byte
+
+// EmbeddedData returns the content of the embedded data file of the compute images.
+func EmbeddedData() []byte {
+	return imagesJSON
+}
+
+// ReadComputeImagesInfo reads the embedded information about compute images.
 func ReadComputeImagesInfo() (*ImageInfo, error) {
-	imgInfo := &ImageInfo{}
-	// Get path of the json file relative to this directory.
-	_, callerFile, _, _ := runtime.Caller(0)
-	inputPath := filepath.Dir(callerFile) + "/compute_images.json"
+	return ParseComputeImagesInfo(imagesJSON)
+}
 
-	data, err := ioutil.ReadFile(inputPath)
-	if err != nil {
-		return nil, err
+// ParseComputeImagesInfo reads the information about compute images of the JSON data files, in the format of
+// compute_images.json. The images of a later file replace those of the same name of the earlier ones.
+func ParseComputeImagesInfo(files ...[]byte) (*ImageInfo, error) {
+	images := map[string]computeImage{}
+	for _, data := range files {
+		var jsonMap []computeImage
+		if err := json.Unmarshal(data, &jsonMap); err != nil {
+			return nil, fmt.Errorf("invalid compute images data: %v", err)
+		}
+		for _, img := range jsonMap {
+			images[img.Image] = img
+		}
 	}
 
-	var jsonMap []computeImage
-	json.Unmarshal(data, &jsonMap)
-
+	imgInfo := &ImageInfo{}
 	imgInfo.imagesByFamily = map[string][]computeImage{}
 	imgInfo.imagesDiskSize = map[string]int64{}
-	for _, img := range jsonMap {
+	for _, img := range images {
 		if imgInfo.imagesByFamily[img.Family] == nil {
 			imgInfo.imagesByFamily[img.Family] = []computeImage{}
 		}
@@ -49,8 +63,12 @@ func ReadComputeImagesInfo() (*ImageInfo, error) {
 	}
 
 	for k := range imgInfo.imagesByFamily {
-		sort.SliceStable(imgInfo.imagesByFamily[k], func(i, j int) bool {
-			return imgInfo.imagesByFamily[k][i].CreationTimestamp > imgInfo.imagesByFamily[k][j].CreationTimestamp
+		l := imgInfo.imagesByFamily[k]
+		sort.Slice(l, func(i, j int) bool {
+			if l[i].CreationTimestamp != l[j].CreationTimestamp {
+				return l[i].CreationTimestamp > l[j].CreationTimestamp
+			}
+			return l[i].Image > l[j].Image
 		})
 	}
 	return imgInfo, nil
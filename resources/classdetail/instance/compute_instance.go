@@ -1,17 +1,24 @@
 package instance
 
 import (
+	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
-	"path/filepath"
-	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 
 	memconv "github.com/googleinterns/terraform-cost-estimation/memconverter"
 )
 
+var (
+	// ErrUnknownMachineType is returned for the machine types missing from the machine type details.
+	ErrUnknownMachineType = errors.New("machine type not supported")
+	// ErrMachineTypeUnavailable is wrapped by the errors of the machine types not offered in a zone.
+	ErrMachineTypeUnavailable = errors.New("machine type not available")
+)
+
 // ComputeInstanceInfo holds information about compute instance types.
 type ComputeInstanceInfo struct {
 	CoreNumber int
@@ -26,20 +33,172 @@ var sharedCoreFractional = map[string]float64{
 	"g1-small":  0.5,
 }
 
-// ReadMachineTypes reads JSON file with compute instance type information.
+var sqlSharedCoreTiers = map[string]ComputeInstanceInfo{
+	"db-f1-micro": {CoreNumber: 1, MemoryGiB: 0.6},
+	"db-g1-small": {CoreNumber: 1, MemoryGiB: 1.7},
+}
+
+// DataFile is the name of the data file of the machine types, embedded in the binary.
+const DataFile = "machine_types.json"
+
+//go:embed machine_types.json
+var machineTypesJSON []byte
+
+// EmbeddedData returns the content of the embedded data file of the machine types.
+func EmbeddedData() []byte {
+	return machineTypesJSON
+}
+
+// ReadMachineTypes reads the embedded compute instance type information.
 func ReadMachineTypes() (map[string]ComputeInstanceInfo, error) {
-	// Get path to JSON  file relative to this directory.
-	_, callerFile, _, _ := runtime.Caller(0)
-	inputPath := filepath.Dir(callerFile) + "/machine_types.json"
+	return ParseMachineTypes(machineTypesJSON)
+}
 
-	data, err := ioutil.ReadFile(inputPath)
-	if err != nil {
-		return nil, err
+// ParseMachineTypes reads the compute instance type information of the JSON data files, in the format of
+// machine_types.json. The machine types of a later file replace those of the same name of the earlier ones.
+func ParseMachineTypes(files ...[]byte) (map[string]ComputeInstanceInfo, error) {
+	machineTypes := map[string]ComputeInstanceInfo{}
+	for _, data := range files {
+		var jsonMap map[string]ComputeInstanceInfo
+		if err := json.Unmarshal(data, &jsonMap); err != nil {
+			return nil, fmt.Errorf("invalid machine types data: %v", err)
+		}
+		for name, info := range jsonMap {
+			machineTypes[name] = info
+		}
 	}
+	return machineTypes, nil
+}
 
-	var jsonMap map[string]ComputeInstanceInfo
-	json.Unmarshal(data, &jsonMap)
-	return jsonMap, nil
+// ZonesDataFile is the name of the data file of the zones offering each machine type, embedded in the binary.
+const ZonesDataFile = "machine_type_zones.json"
+
+//go:embed machine_type_zones.json
+var machineTypeZonesJSON []byte
+
+// EmbeddedZonesData returns the content of the embedded data file of the zones offering each machine type.
+func EmbeddedZonesData() []byte {
+	return machineTypeZonesJSON
+}
+
+// ReadMachineTypeZones reads the embedded zones offering each machine type.
+func ReadMachineTypeZones() (map[string][]string, error) {
+	return ParseMachineTypeZones(machineTypeZonesJSON)
+}
+
+// ParseMachineTypeZones reads the zones offering each machine type of the JSON data files, in the format of
+// machine_type_zones.json. The zones of a machine type in a later file replace those of the earlier ones.
+func ParseMachineTypeZones(files ...[]byte) (map[string][]string, error) {
+	zones := map[string][]string{}
+	for _, data := range files {
+		var jsonMap map[string][]string
+		if err := json.Unmarshal(data, &jsonMap); err != nil {
+			return nil, fmt.Errorf("invalid machine type zones data: %v", err)
+		}
+		for name, l := range jsonMap {
+			l = append([]string{}, l...)
+			sort.Strings(l)
+			zones[name] = l
+		}
+	}
+	return zones, nil
+}
+
+// customFamily returns the family of the predefined machine types of a custom machine type
+// (e.g. n2 for n2-custom-2-4096 and n1 for custom-2-4096), empty for predefined machine types.
+func customFamily(machineType string) string {
+	if strings.HasPrefix(machineType, "custom-") {
+		return "n1"
+	}
+	if i := strings.Index(machineType, "-custom-"); i > 0 {
+		return machineType[:i]
+	}
+	return ""
+}
+
+// MachineTypeZones returns the sorted zones offering the machine type, or false if they are unknown.
+// Custom machine types are offered in the zones of the predefined machine types of their family.
+func MachineTypeZones(zones map[string][]string, machineType string) ([]string, bool) {
+	if l, ok := zones[machineType]; ok {
+		return l, true
+	}
+	family := customFamily(machineType)
+	if family == "" {
+		return nil, false
+	}
+
+	found := map[string]bool{}
+	for name, l := range zones {
+		if strings.HasPrefix(name, family+"-") {
+			for _, z := range l {
+				found[z] = true
+			}
+		}
+	}
+	if len(found) == 0 {
+		return nil, false
+	}
+	var l []string
+	for z := range found {
+		l = append(l, z)
+	}
+	sort.Strings(l)
+	return l, true
+}
+
+// zoneRegion returns the region of the zone (e.g. us-central1 for us-central1-a).
+func zoneRegion(zone string) string {
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		return zone[:i]
+	}
+	return zone
+}
+
+// NearbyZones returns at most n of the zones closest to the given one: the zones of the same region first,
+// then those of the same area (e.g. asia-northeast for asia-northeast2-a), then those of the same geography
+// (e.g. asia), in name order. If none of the zones is in the same geography, the first n zones are returned.
+func NearbyZones(zones []string, zone string, n int) []string {
+	region := zoneRegion(zone)
+	regionArea := strings.TrimRight(region, "0123456789")
+	geography := strings.SplitN(zone, "-", 2)[0] + "-"
+
+	var same, inArea, near []string
+	for _, z := range zones {
+		switch r := zoneRegion(z); {
+		case r == region:
+			same = append(same, z)
+		case strings.TrimRight(r, "0123456789") == regionArea:
+			inArea = append(inArea, z)
+		case strings.HasPrefix(z, geography):
+			near = append(near, z)
+		}
+	}
+	nearby := append(append(same, inArea...), near...)
+	if len(nearby) == 0 {
+		nearby = zones
+	}
+	if len(nearby) > n {
+		nearby = nearby[:n]
+	}
+	return nearby
+}
+
+// CheckZone returns an error wrapping ErrMachineTypeUnavailable, with up to 5 nearby zones offering the
+// machine type, if the machine type is not offered in the zone. Machine types of unknown zones are not checked.
+func CheckZone(zones map[string][]string, machineType, zone string) error {
+	offering, ok := MachineTypeZones(zones, machineType)
+	if !ok {
+		return nil
+	}
+	i := sort.SearchStrings(offering, zone)
+	if i < len(offering) && offering[i] == zone {
+		return nil
+	}
+	if len(offering) == 0 {
+		return fmt.Errorf("%w in any zone: %s", ErrMachineTypeUnavailable, machineType)
+	}
+	return fmt.Errorf("%w in %s: %s (nearby zones offering it: %s)", ErrMachineTypeUnavailable, zone, machineType,
+		strings.Join(NearbyZones(offering, zone, 5), ", "))
 }
 
 // getCustomMachineDetails looks for a cutom machine type in the format [machine_type-]custom-<core_num>-<mem_mib>[-ext] and extracts <core_num> and <mem_mib>.
@@ -95,7 +254,7 @@ func GetMachineDetails(machineTypes map[string]ComputeInstanceInfo, machineType
 
 	d, ok := machineTypes[machineType]
 	if !ok {
-		return 0, 0, fmt.Errorf("machine type not supported")
+		return 0, 0, ErrUnknownMachineType
 	}
 	return d.CoreNumber, d.MemoryGiB, nil
 }
@@ -108,3 +267,21 @@ func GetMachineFractionalCore(machineType string) float64 {
 	}
 	return 1
 }
+
+// GetSQLTierDetails returns the number of cores and GBs of memory for a Cloud SQL tier (db-<machine_type>).
+// Shared core tiers (db-f1-micro, db-g1-small) are reported by sharedCore, since they are priced per instance.
+func GetSQLTierDetails(machineTypes map[string]ComputeInstanceInfo, tier string) (coreNum int, memGiB float64, sharedCore bool, err error) {
+	if d, ok := sqlSharedCoreTiers[tier]; ok {
+		return d.CoreNumber, d.MemoryGiB, true, nil
+	}
+
+	if !strings.HasPrefix(tier, "db-") {
+		return 0, 0, false, fmt.Errorf("invalid Cloud SQL tier '" + tier + "'")
+	}
+
+	coreNum, memGiB, err = GetMachineDetails(machineTypes, tier[len("db-"):])
+	if err != nil {
+		return 0, 0, false, err
+	}
+	return coreNum, memGiB, false, nil
+}
@@ -1,9 +1,11 @@
 package instance
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -51,3 +53,74 @@ func TestGetMachineDetails(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSQLTierDetails(t *testing.T) {
+	machineTypes, err := ReadMachineTypes()
+	if err != nil {
+		t.Fatal("could not read machine type information")
+	}
+
+	tests := []struct {
+		name       string
+		tier       string
+		cores      int
+		mem        float64
+		sharedCore bool
+		err        error
+	}{
+		{"shared_core_0", "db-f1-micro", 1, 0.6, true, nil},
+		{"shared_core_1", "db-g1-small", 1, 1.7, true, nil},
+		{"predefined_0", "db-n1-standard-2", 2, 7.5, false, nil},
+		{"predefined_1", "db-n1-highmem-4", 4, 26, false, nil},
+		{"custom", "db-custom-2-7680", 2, 7.5, false, nil},
+		{"no_prefix", "n1-standard-2", 0, 0, false, fmt.Errorf("invalid Cloud SQL tier 'n1-standard-2'")},
+		{"unknown", "db-n1-standard-3", 0, 0, false, fmt.Errorf("machine type not supported")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, m, s, e := GetSQLTierDetails(machineTypes, test.tier)
+			// Test fails if the errors or the return values are different.
+			if !reflect.DeepEqual(e, test.err) || c != test.cores || math.Abs(m-test.mem) > epsilon || s != test.sharedCore {
+				t.Errorf("GetSQLTierDetails(%s) = %+v, %+v, %+v, %+v; want %+v, %+v, %+v, %+v",
+					test.tier, c, m, s, e, test.cores, test.mem, test.sharedCore, test.err)
+			}
+		})
+	}
+}
+
+func TestCheckZone(t *testing.T) {
+	zones, err := ReadMachineTypeZones()
+	if err != nil {
+		t.Fatal("could not read machine type zone information")
+	}
+
+	tests := []struct {
+		name        string
+		machineType string
+		zone        string
+		ok          bool
+		nearby      string
+	}{
+		{"offered", "c2-standard-8", "us-central1-a", true, ""},
+		{"offered_everywhere", "n1-standard-1", "europe-west6-c", true, ""},
+		{"not_offered", "c2-standard-8", "europe-west6-a", false,
+			"europe-west1-b, europe-west1-c, europe-west1-d, europe-west2-a, europe-west2-b"},
+		{"same_region_first", "m2-ultramem-416", "us-central1-c", false, "us-central1-a, us-central1-b, us-east1-c"},
+		{"same_area_first", "n2-standard-4", "asia-northeast2-a", false,
+			"asia-northeast1-a, asia-northeast1-b, asia-northeast1-c, asia-east1-a, asia-east1-b"},
+		{"custom_family", "n2d-custom-2-4096", "us-east4-a", false, "us-east1-b, us-east1-c, us-east1-d, us-central1-a, us-central1-b"},
+		{"custom_n1", "custom-2-4096", "asia-east2-a", true, ""},
+		{"unknown_zones", "n9-standard-2", "us-central1-a", true, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := CheckZone(zones, test.machineType, test.zone)
+			if (err == nil) != test.ok || err != nil && (!errors.Is(err, ErrMachineTypeUnavailable) ||
+				!strings.HasSuffix(err.Error(), "(nearby zones offering it: "+test.nearby+")")) {
+				t.Errorf("CheckZone(%s, %s) = %v; want ok %t, nearby zones %s", test.machineType, test.zone, err, test.ok, test.nearby)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package instance
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrInvalidCustomShape is wrapped by the errors of the custom machine types breaking the rules of their family.
+var ErrInvalidCustomShape = errors.New("invalid custom machine type")
+
+// customMemoryStepMiB is the granularity of the memory of all custom machine types.
+const customMemoryStepMiB = 256
+
+// customRules holds the shapes allowed for the custom machine types of a machine family.
+type customRules struct {
+	// vCPUs are the allowed vCPU counts in ascending order, vCPURule describes them.
+	vCPUs    []int
+	vCPURule string
+	// Memory per vCPU bounds, in MiB.
+	minMemPerVCPU float64
+	maxMemPerVCPU float64
+	// maxExtendedMiB is the maximum memory with extended memory, 0 if the family doesn't support it.
+	maxExtendedMiB int
+}
+
+// vCPUSteps returns the vCPU counts from min to max (both included) in steps of step.
+func vCPUSteps(min, max, step int) []int {
+	var l []int
+	for n := min; n <= max; n += step {
+		l = append(l, n)
+	}
+	return l
+}
+
+// customMachineRules are the custom machine type rules of each machine family, the family of
+// custom-<cores>-<mib> being n1.
+var customMachineRules = map[string]customRules{
+	"n1": {
+		vCPUs:          append([]int{1}, vCPUSteps(2, 96, 2)...),
+		vCPURule:       "1 or an even number of vCPUs up to 96",
+		minMemPerVCPU:  0.9 * 1024,
+		maxMemPerVCPU:  6.5 * 1024,
+		maxExtendedMiB: 624 * 1024,
+	},
+	"n2": {
+		vCPUs:          append(vCPUSteps(2, 32, 2), vCPUSteps(36, 80, 4)...),
+		vCPURule:       "an even number of vCPUs up to 32, then a multiple of 4 up to 80",
+		minMemPerVCPU:  0.5 * 1024,
+		maxMemPerVCPU:  8 * 1024,
+		maxExtendedMiB: 864 * 1024,
+	},
+	"n2d": {
+		vCPUs:          append([]int{2, 4, 8}, vCPUSteps(16, 96, 16)...),
+		vCPURule:       "2, 4, 8 or a multiple of 16 vCPUs up to 96",
+		minMemPerVCPU:  0.5 * 1024,
+		maxMemPerVCPU:  8 * 1024,
+		maxExtendedMiB: 768 * 1024,
+	},
+	"e2": {
+		vCPUs:         vCPUSteps(2, 32, 2),
+		vCPURule:      "an even number of vCPUs from 2 to 32",
+		minMemPerVCPU: 0.5 * 1024,
+		maxMemPerVCPU: 8 * 1024,
+	},
+}
+
+// CustomShapeError is the error of a custom machine type breaking the rules of its family.
+type CustomShapeError struct {
+	MachineType string
+	// Violations describe the broken rules.
+	Violations []string
+	// Closest is the closest valid custom machine type of the family, empty if the family has none.
+	Closest string
+}
+
+func (e *CustomShapeError) Error() string {
+	s := fmt.Sprintf("%v %s: %s", ErrInvalidCustomShape, e.MachineType, strings.Join(e.Violations, "; "))
+	if e.Closest != "" {
+		s += fmt.Sprintf(" (closest valid shape: %s)", e.Closest)
+	}
+	return s
+}
+
+func (e *CustomShapeError) Unwrap() error {
+	return ErrInvalidCustomShape
+}
+
+// closestVCPUs returns the allowed vCPU count closest to n, the larger one on ties.
+func closestVCPUs(allowed []int, n int) int {
+	best := allowed[0]
+	for _, c := range allowed[1:] {
+		if abs(c-n) <= abs(best-n) {
+			best = c
+		}
+	}
+	return best
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// memBounds returns the minimum and maximum memory (in MiB, multiples of 256 MiB) without extended memory
+// of a custom machine type with the given vCPUs.
+func (r customRules) memBounds(cores int) (lo, hi int) {
+	lo = int(math.Ceil(r.minMemPerVCPU*float64(cores)/customMemoryStepMiB)) * customMemoryStepMiB
+	hi = int(math.Floor(r.maxMemPerVCPU*float64(cores)/customMemoryStepMiB)) * customMemoryStepMiB
+	return lo, hi
+}
+
+// closest returns the valid shape closest to the given one: the closest allowed vCPU count, then the memory
+// rounded to 256 MiB and brought within the bounds of that vCPU count. Memory above the bounds is kept
+// as extended memory if the family supports it.
+func (r customRules) closest(cores, memMiB int, ext bool) (int, int, bool) {
+	cores = closestVCPUs(r.vCPUs, cores)
+	memMiB = int(math.Floor(float64(memMiB)/customMemoryStepMiB+0.5)) * customMemoryStepMiB
+	ext = ext && r.maxExtendedMiB > 0
+
+	lo, hi := r.memBounds(cores)
+	switch {
+	case memMiB < lo:
+		memMiB = lo
+	case memMiB > hi && r.maxExtendedMiB > 0:
+		ext = true
+		if memMiB > r.maxExtendedMiB {
+			memMiB = r.maxExtendedMiB
+		}
+	case memMiB > hi:
+		memMiB = hi
+	}
+	return cores, memMiB, ext
+}
+
+// customMachineType returns the name of the custom machine type of the family with the given shape.
+func customMachineType(family string, cores, memMiB int, ext bool) string {
+	s := fmt.Sprintf("custom-%d-%d", cores, memMiB)
+	if family != "n1" {
+		s = family + "-" + s
+	}
+	if ext {
+		s += "-ext"
+	}
+	return s
+}
+
+// formatGiB formats an amount of memory in GiB without trailing zeros (e.g. 0.9 or 6.5).
+func formatGiB(mib float64) string {
+	return fmt.Sprintf("%g GiB", mib/1024)
+}
+
+// CheckCustomShape returns a *CustomShapeError if the custom machine type breaks the rules of its family:
+// the allowed vCPU counts, the memory per vCPU bounds, extended memory eligibility and the 256 MiB
+// memory granularity. Predefined machine types are not checked.
+func CheckCustomShape(machineType string) error {
+	family := customFamily(machineType)
+	if family == "" {
+		return nil
+	}
+	cores, memGiB, err := getCustomMachineDetails(machineType)
+	if err != nil {
+		return err
+	}
+	memMiB := int(math.Round(memGiB * 1024))
+	ext := strings.HasSuffix(machineType, "-ext")
+
+	r, ok := customMachineRules[family]
+	if !ok {
+		return &CustomShapeError{MachineType: machineType,
+			Violations: []string{fmt.Sprintf("%s machine types can't be customized", strings.ToUpper(family))}}
+	}
+
+	name := strings.ToUpper(family)
+	var violations []string
+	if closestVCPUs(r.vCPUs, cores) != cores {
+		violations = append(violations, fmt.Sprintf("%s custom machine types need %s", name, r.vCPURule))
+	}
+	if memMiB%customMemoryStepMiB != 0 {
+		violations = append(violations, fmt.Sprintf("memory must be a multiple of %d MiB", customMemoryStepMiB))
+	}
+
+	minMem, maxMem := r.minMemPerVCPU*float64(cores), r.maxMemPerVCPU*float64(cores)
+	switch {
+	case float64(memMiB) < minMem:
+		violations = append(violations, fmt.Sprintf("memory must be at least %s per vCPU", formatGiB(r.minMemPerVCPU)))
+	case ext && r.maxExtendedMiB == 0:
+		violations = append(violations, fmt.Sprintf("%s custom machine types don't support extended memory", name))
+	case ext && memMiB > r.maxExtendedMiB:
+		violations = append(violations, fmt.Sprintf("extended memory must be at most %s", formatGiB(float64(r.maxExtendedMiB))))
+	case !ext && float64(memMiB) > maxMem && r.maxExtendedMiB > 0:
+		violations = append(violations, fmt.Sprintf("memory above %s per vCPU needs extended memory (-ext)", formatGiB(r.maxMemPerVCPU)))
+	case !ext && float64(memMiB) > maxMem:
+		violations = append(violations, fmt.Sprintf("memory must be at most %s per vCPU", formatGiB(r.maxMemPerVCPU)))
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	c, m, e := r.closest(cores, memMiB, ext)
+	return &CustomShapeError{MachineType: machineType, Violations: violations, Closest: customMachineType(family, c, m, e)}
+}
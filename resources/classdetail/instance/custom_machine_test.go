@@ -0,0 +1,60 @@
+package instance
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCheckCustomShape(t *testing.T) {
+	tests := []struct {
+		name        string
+		machineType string
+		ok          bool
+		violations  []string
+		closest     string
+	}{
+		{"predefined", "n1-standard-1", true, nil, ""},
+		{"n1_one_vcpu", "custom-1-1024", true, nil, ""},
+		{"n1_extended", "custom-2-16384-ext", true, nil, ""},
+		{"n2_above_32", "n2-custom-36-36864", true, nil, ""},
+		{"n2d", "n2d-custom-16-8192", true, nil, ""},
+		{"e2", "e2-custom-2-4096", true, nil, ""},
+		{"n1_odd_vcpus", "custom-3-4096", false,
+			[]string{"N1 custom machine types need 1 or an even number of vCPUs up to 96"}, "custom-4-4096"},
+		{"n1_memory_below_minimum", "custom-2-1024", false,
+			[]string{"memory must be at least 0.9 GiB per vCPU"}, "custom-2-2048"},
+		{"n1_memory_needs_extended", "custom-2-16384", false,
+			[]string{"memory above 6.5 GiB per vCPU needs extended memory (-ext)"}, "custom-2-16384-ext"},
+		{"n1_extended_above_maximum", "custom-96-655360-ext", false,
+			[]string{"extended memory must be at most 624 GiB"}, "custom-96-638976-ext"},
+		{"memory_granularity", "n2-custom-4-5000", false,
+			[]string{"memory must be a multiple of 256 MiB"}, "n2-custom-4-5120"},
+		{"n2_step_above_32", "n2-custom-34-34816", false,
+			[]string{"N2 custom machine types need an even number of vCPUs up to 32, then a multiple of 4 up to 80"}, "n2-custom-36-34816"},
+		{"n2d_vcpus", "n2d-custom-6-6144", false,
+			[]string{"N2D custom machine types need 2, 4, 8 or a multiple of 16 vCPUs up to 96"}, "n2d-custom-8-6144"},
+		{"e2_vcpus_above_limit", "e2-custom-48-49152", false,
+			[]string{"E2 custom machine types need an even number of vCPUs from 2 to 32"}, "e2-custom-32-49152"},
+		{"e2_memory_above_maximum", "e2-custom-2-20480", false,
+			[]string{"memory must be at most 8 GiB per vCPU"}, "e2-custom-2-16384"},
+		{"e2_extended", "e2-custom-2-4096-ext", false,
+			[]string{"E2 custom machine types don't support extended memory"}, "e2-custom-2-4096"},
+		{"several_violations", "custom-5-1000", false,
+			[]string{"N1 custom machine types need 1 or an even number of vCPUs up to 96",
+				"memory must be a multiple of 256 MiB", "memory must be at least 0.9 GiB per vCPU"}, "custom-6-5632"},
+		{"family_without_custom", "c2-custom-4-16384", false, []string{"C2 machine types can't be customized"}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := CheckCustomShape(test.machineType)
+			var shapeErr *CustomShapeError
+			if (err == nil) != test.ok || err != nil && (!errors.As(err, &shapeErr) || !errors.Is(err, ErrInvalidCustomShape) ||
+				!reflect.DeepEqual(shapeErr.Violations, test.violations) || shapeErr.Closest != test.closest) {
+				t.Errorf("CheckCustomShape(%s) = %v; want ok %t, violations %q, closest %q",
+					test.machineType, err, test.ok, test.violations, test.closest)
+			}
+		})
+	}
+}
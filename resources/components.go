@@ -0,0 +1,261 @@
+package resources
+
+import (
+	"fmt"
+
+	billing "github.com/googleinterns/terraform-cost-estimation/billing"
+	"github.com/googleinterns/terraform-cost-estimation/io/js"
+	"github.com/googleinterns/terraform-cost-estimation/io/web"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+// priceComponent holds the hourly before and after pricing of a single billing component (CPU, RAM, storage etc.).
+type priceComponent struct {
+	name      string
+	unitCost1 float64
+	units1    float64
+	unitCost2 float64
+	units2    float64
+	pricing1  PricingInfo
+	pricing2  PricingInfo
+}
+
+func (c priceComponent) total1() float64 {
+	return c.unitCost1 * c.units1
+}
+
+func (c priceComponent) total2() float64 {
+	return c.unitCost2 * c.units2
+}
+
+func (c priceComponent) delta() float64 {
+	return c.total2() - c.total1()
+}
+
+// componentPricing is the pricing of a billing component in a single resource state.
+// The unit cost is usually the hourly unit price of the pricing, which also holds its currency and SKU match.
+type componentPricing struct {
+	name     string
+	unitCost float64
+	units    float64
+	pricing  PricingInfo
+}
+
+// mergeComponents pairs the before and after components by name, keeping the order in which they appear.
+func mergeComponents(before, after []componentPricing) []priceComponent {
+	var merged []priceComponent
+	index := map[string]int{}
+
+	add := func(c componentPricing) *priceComponent {
+		i, ok := index[c.name]
+		if !ok {
+			i = len(merged)
+			index[c.name] = i
+			merged = append(merged, priceComponent{name: c.name})
+		}
+		return &merged[i]
+	}
+
+	for _, c := range before {
+		p := add(c)
+		p.unitCost1, p.units1, p.pricing1 = c.unitCost, c.units, c.pricing
+	}
+	for _, c := range after {
+		p := add(c)
+		p.unitCost2, p.units2, p.pricing2 = c.unitCost, c.units, c.pricing
+	}
+	return merged
+}
+
+func componentsDelta(components []priceComponent) (d float64) {
+	for _, c := range components {
+		d += c.delta()
+	}
+	return
+}
+
+// scaleComponents returns the components with their unit costs over the given period.
+func scaleComponents(components []priceComponent, p Period) []priceComponent {
+	scaled := make([]priceComponent, len(components))
+	for i, c := range components {
+		c.unitCost1, c.unitCost2 = p.Cost(c.unitCost1), p.Cost(c.unitCost2)
+		scaled[i] = c
+	}
+	return scaled
+}
+
+func componentsTotals(components []priceComponent) (t1, t2 float64) {
+	for _, c := range components {
+		t1 += c.total1()
+		t2 += c.total2()
+	}
+	return
+}
+
+// componentsCurrency returns the currency of the priced components or an error if they are priced in different currencies.
+// Components that were not priced are ignored; the default currency is returned if none was.
+func componentsCurrency(components []priceComponent) (string, error) {
+	currency := ""
+	for _, c := range components {
+		for _, p := range []PricingInfo{c.pricing1, c.pricing2} {
+			switch {
+			case p.CurrencyType == "" || p.CurrencyType == currency:
+			case currency == "":
+				currency = p.CurrencyType
+			default:
+				return "", fmt.Errorf("components are priced in different currencies (" + currency + ", " + p.CurrencyType + ")")
+			}
+		}
+	}
+	if currency == "" {
+		currency = billing.DefaultCurrency
+	}
+	return currency, nil
+}
+
+// componentsTable creates a table.Table with the general information rows and the pricing information of each component
+// over the given period. It returns an error if the components are priced in different currencies.
+func componentsTable(general [][2]string, components []priceComponent, p Period) (*table.Table, error) {
+	currency, err := componentsCurrency(components)
+	if err != nil {
+		return nil, err
+	}
+	components = scaleComponents(components, p)
+
+	t := &table.Table{}
+	autoMerge := table.RowConfig{AutoMerge: true}
+	width := len(components) + 3
+
+	fullRow := func(h, s string) (row table.Row) {
+		row = append(row, h)
+		for i := 1; i < width; i++ {
+			row = append(row, s)
+		}
+		return
+	}
+
+	for i, r := range general {
+		v := r[1]
+		if v == "" {
+			v = "unknown"
+		}
+		// Add " " in the end of every other string to avoid unwanted auto-merging in the table package.
+		if i%2 == 1 {
+			v += " "
+		}
+		t.AppendRow(fullRow(r[0], v), autoMerge)
+	}
+
+	h := "Pricing Information\n(" + p.Unit(currency) + ")"
+	t.AppendRow(fullRow(h, h), autoMerge)
+
+	header := table.Row{" ", " "}
+	for _, c := range components {
+		header = append(header, c.name)
+	}
+	t.AppendRow(append(header, "Total"), autoMerge)
+
+	f1 := func(x float64) string { return fmt.Sprintf("%.6f", x) }
+	f2 := func(x float64) string { return fmt.Sprintf("%.2f", x) }
+	t1, t2 := componentsTotals(components)
+
+	rows := []table.Row{
+		{"Before", "Cost\nper\nunit"}, {"Before", "Number\nof\nunits"}, {"Before", "Units\ncost"},
+		{"After", "Cost\nper\nunit"}, {"After", "Number\nof\nunits"}, {"After", "Units\ncost"},
+	}
+	for _, c := range components {
+		rows[0] = append(rows[0], f1(c.unitCost1))
+		rows[1] = append(rows[1], f2(c.units1))
+		rows[2] = append(rows[2], f1(c.total1()))
+		rows[3] = append(rows[3], f1(c.unitCost2))
+		rows[4] = append(rows[4], f2(c.units2))
+		rows[5] = append(rows[5], f1(c.total2()))
+	}
+	for i := range rows {
+		if i < 3 {
+			rows[i] = append(rows[i], f1(t1))
+		} else {
+			// Add " " in the end of string to avoid unwanted auto-merging with the before total.
+			rows[i] = append(rows[i], f1(t2)+" ")
+		}
+	}
+	t.AppendRows(rows)
+
+	delta := t2 - t1
+	color := text.FgGreen
+	change := "No change"
+	if delta < 0 {
+		change = "Down (↓)"
+		color = text.FgRed
+	} else if delta > 0 {
+		change = "Up (↑)"
+	}
+
+	footer := table.Row{"DELTA", change}
+	for _, c := range components {
+		footer = append(footer, f1(c.delta()))
+	}
+	t.AppendFooter(append(footer, f1(delta)))
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 1, AutoMerge: true},
+		{Number: width, AutoMerge: true, ColorsFooter: text.Colors{color}},
+	})
+	t.SetStyle(table.StyleLight)
+	t.Style().Options.SeparateRows = true
+	return t, nil
+}
+
+// componentsWebTables returns the html pricing information tables with hourly, monthly and yearly pricing.
+// The monthly pricing uses the given month period. It returns an error if the components are priced in different currencies.
+func componentsWebTables(stateNum int, name string, general [][2]string, components []priceComponent,
+	month Period) (*web.PricingTypeTables, error) {
+	currency, err := componentsCurrency(components)
+	if err != nil {
+		return nil, err
+	}
+	scaled := func(p Period) (wc []web.ComponentPricing) {
+		for _, c := range scaleComponents(components, p) {
+			wc = append(wc, web.ComponentPricing{Name: c.name, CostPerUnit1: c.unitCost1, Units1: c.units1,
+				CostPerUnit2: c.unitCost2, Units2: c.units2})
+		}
+		return
+	}
+
+	h := web.Table{Index: stateNum, Type: "hourly"}
+	h.AddGeneralInfo(name, general)
+	h.AddComponentsPricing(Hour.Unit(currency), scaled(Hour))
+
+	m := web.Table{Index: stateNum, Type: "monthly"}
+	m.AddGeneralInfo(name, general)
+	m.AddComponentsPricing(month.Unit(currency), scaled(month))
+
+	y := web.Table{Index: stateNum, Type: "yearly"}
+	y.AddGeneralInfo(name, general)
+	y.AddComponentsPricing(Year.Unit(currency), scaled(Year))
+
+	return &web.PricingTypeTables{Hourly: h, Monthly: m, Yearly: y}, nil
+}
+
+// componentsOut returns the json pricing information of the components.
+// A nil pricing is returned when the state does not exist (before creation or after deletion).
+func componentsOut(components []priceComponent, exists, after bool) *js.ComponentsPricing {
+	if !exists {
+		return nil
+	}
+
+	out := &js.ComponentsPricing{Components: map[string]js.Pricing{}}
+	for _, c := range components {
+		unitCost, units, total := c.unitCost1, c.units1, c.total1()
+		if after {
+			unitCost, units, total = c.unitCost2, c.units2, c.total2()
+		}
+		out.Components[c.name] = js.Pricing{
+			UnitCost:  fmt.Sprintf("%.6f", unitCost),
+			NumUnits:  fmt.Sprintf("%.2f", units),
+			TotalCost: fmt.Sprintf("%.6f", total),
+		}
+		out.TotalCost += total
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+package resources
+
+import (
+	"testing"
+)
+
+func TestComponentsCurrency(t *testing.T) {
+	usd := PricingInfo{CurrencyType: "USD"}
+	eur := PricingInfo{CurrencyType: "EUR"}
+
+	tests := []struct {
+		name     string
+		before   []componentPricing
+		after    []componentPricing
+		currency string
+		ok       bool
+	}{
+		{"unpriced", nil, nil, "USD", true},
+		{"single_currency", []componentPricing{{name: "CPU", pricing: eur}}, []componentPricing{{name: "CPU", pricing: eur}, {name: "RAM", pricing: eur}}, "EUR", true},
+		{"created", nil, []componentPricing{{name: "Storage", pricing: eur}}, "EUR", true},
+		{"unpriced_component", []componentPricing{{name: "CPU", pricing: eur}, {name: "RAM"}}, nil, "EUR", true},
+		{"mixed_states", []componentPricing{{name: "CPU", pricing: usd}}, []componentPricing{{name: "CPU", pricing: eur}}, "", false},
+		{"mixed_components", []componentPricing{{name: "CPU", pricing: eur}, {name: "RAM", pricing: usd}}, nil, "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			components := mergeComponents(test.before, test.after)
+			// The tables in a single currency label can't be built from components priced in different ones.
+			if _, err := componentsWebTables(0, "resource", nil, components, Hour); (err == nil) != test.ok {
+				t.Errorf("componentsWebTables() error = %v, want ok %t", err, test.ok)
+			}
+
+			currency, err := componentsCurrency(components)
+			if !test.ok {
+				if err == nil {
+					t.Fatalf("componentsCurrency() = %s, want error", currency)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("componentsCurrency() got error %v", err)
+			}
+			if currency != test.currency {
+				t.Errorf("componentsCurrency() = %s, want %s", currency, test.currency)
+			}
+		})
+	}
+
+	instance := &ComputeInstance{Cores: CoreInfo{UnitPricing: usd}, Memory: MemoryInfo{UnitPricing: eur}}
+	if _, err := (&ComputeInstanceState{After: instance, Action: "create"}).GetWebTables(0, Hour); err == nil {
+		t.Errorf("GetWebTables() of a compute instance priced in USD and EUR returned no error")
+	}
+}
@@ -40,7 +40,7 @@ func NewComputeDisk(details *cd.ResourceDetail, name, id, diskType string, zones
 
 	i := strings.LastIndex(zones[0], "-")
 	if i < 0 {
-		return nil, fmt.Errorf("invalid zone format")
+		return nil, ErrInvalidZone
 	}
 	disk.Region = zones[0][:i]
 
@@ -82,25 +82,27 @@ func NewComputeDisk(details *cd.ResourceDetail, name, id, diskType string, zones
 	return disk, nil
 }
 
-func (disk *ComputeDisk) completePricingInfo(catalog *billing.ComputeEngineCatalog) error {
-	skus, err := catalog.DiskSKUs(disk.Type)
+func (disk *ComputeDisk) completePricingInfo(catalog *billing.Catalog) error {
+	c, err := catalog.ComputeEngine()
 	if err != nil {
 		return err
 	}
 
-	filtered, err := filterSKUs(skus, disk.Region, disk.Description)
+	skus, err := c.LookupDiskSKUs(disk.Type, disk.Region)
 	if err != nil {
 		return err
 	}
 
-	if len(filtered) == 0 {
-		return fmt.Errorf("could not find disk pricing information")
+	filtered, err := billing.DescriptionFilter(skus, disk.Description.Contains, disk.Description.Omits)
+	if err != nil {
+		return err
 	}
 
 	correctTieredRate := func(tr *billingpb.PricingExpression_TierRate) bool {
 		return int64(tr.StartUsageAmount) <= disk.SizeGiB
 	}
-	disk.UnitPricing.fillMonthlyBase(filtered[0], correctTieredRate)
+	disk.UnitPricing.fillMonthlyBase(filtered[0], correctTieredRate, catalog.MonthHours())
+	disk.UnitPricing.explain(catalog, filtered[0], disk.Region, disk.Description, skus)
 
 	// If SKU memory unit is not supported, then return error.
 	if _, err := conv.Convert("gib", 0, disk.UnitPricing.UsageUnit); err != nil {
@@ -122,19 +124,20 @@ type ComputeDiskState struct {
 	Before *ComputeDisk
 	After  *ComputeDisk
 	Action string
+	ResourceAddresses
 }
 
 // CompletePricingInfo completes pricing information of both before and after states.
-func (state *ComputeDiskState) CompletePricingInfo(catalog *billing.ComputeEngineCatalog) error {
+func (state *ComputeDiskState) CompletePricingInfo(catalog *billing.Catalog) error {
 	if state.Before != nil {
 		if err := state.Before.completePricingInfo(catalog); err != nil {
-			return fmt.Errorf(state.Before.Name + "(" + state.Before.Type + ")" + ": " + err.Error())
+			return fmt.Errorf("%s(%s): %w", state.Before.Name, state.Before.Type, err)
 		}
 	}
 
 	if state.After != nil {
 		if err := state.After.completePricingInfo(catalog); err != nil {
-			return fmt.Errorf(state.After.Name + "(" + state.After.Type + ")" + ": " + err.Error())
+			return fmt.Errorf("%s(%s): %w", state.After.Name, state.After.Type, err)
 		}
 	}
 
@@ -211,28 +214,87 @@ func (state *ComputeDiskState) costChanges() (costPerUnit1, costPerUnit2 float64
 	return
 }
 
+// pricings returns the before and after pricing of the disk storage.
+func (state *ComputeDiskState) pricings() []priceComponent {
+	var before, after []componentPricing
+	if state.Before != nil {
+		before = []componentPricing{{name: "Storage", pricing: state.Before.UnitPricing}}
+	}
+	if state.After != nil {
+		after = []componentPricing{{name: "Storage", pricing: state.After.UnitPricing}}
+	}
+	return mergeComponents(before, after)
+}
+
+// Explain returns the SKU match of the disk storage.
+func (state *ComputeDiskState) Explain() []ComponentMatch {
+	name, _, _, _, _, _, _ := state.generalChanges()
+	return componentsExplain(name, state.pricings())
+}
+
+// Currency returns the currency of the disk storage pricing.
+func (state *ComputeDiskState) Currency() (string, error) {
+	return componentsCurrency(state.pricings())
+}
+
+// report describes the compute disk for the machine-readable outputs.
+func (disk *ComputeDisk) report() *ResourceReport {
+	if disk == nil {
+		return nil
+	}
+
+	units, _ := conv.Convert("gib", float64(disk.SizeGiB), strings.Split(disk.UnitPricing.UsageUnit, " ")[0])
+	return &ResourceReport{
+		Name: disk.Name,
+		Attributes: map[string]interface{}{
+			"id":        disk.ID,
+			"disk_type": disk.Type,
+			"zones":     strings.Join(disk.Zones, ","),
+			"image":     disk.Image,
+			"snapshot":  disk.Snapshot,
+			"size_gib":  float64(disk.SizeGiB),
+		},
+		Components: componentCosts([]componentPricing{{"Storage", disk.UnitPricing.HourlyUnitPrice, units, disk.UnitPricing}}),
+	}
+}
+
+// Report describes the change of the compute disk for the machine-readable outputs.
+func (state *ComputeDiskState) Report() StateReport {
+	return StateReport{Kind: "compute_disk", Addresses: state.Addresses, Action: state.Action,
+		Before: state.Before.report(), After: state.After.report()}
+}
+
 // GetWebTables returns html pricing information table strings to be displayed in a web page.
-func (state *ComputeDiskState) GetWebTables(stateNum int) *web.PricingTypeTables {
+func (state *ComputeDiskState) GetWebTables(stateNum int, month Period) (*web.PricingTypeTables, error) {
 	name, id, action, diskType, zones, image, snapshot := state.generalChanges()
 	costPerUnit1, costPerUnit2, units1, units2, delta := state.costChanges()
+	currency, err := state.Currency()
+	if err != nil {
+		return nil, err
+	}
 
 	h := web.Table{Index: stateNum, Type: "hourly"}
 	h.AddComputeDiskGeneralInfo(name, id, action, diskType, zones, image, snapshot)
-	h.AddComputeDiskPricing("hour", costPerUnit1, costPerUnit2, units1, units2, delta)
+	h.AddComputeDiskPricing(Hour.Unit(currency), costPerUnit1, costPerUnit2, units1, units2, delta)
 
 	m := web.Table{Index: stateNum, Type: "monthly"}
 	m.AddComputeDiskGeneralInfo(name, id, action, diskType, zones, image, snapshot)
-	m.AddComputeDiskPricing("month", costPerUnit1*hourlyToMonthly, costPerUnit2*hourlyToMonthly, units1, units2, delta*hourlyToMonthly)
+	m.AddComputeDiskPricing(month.Unit(currency), month.Cost(costPerUnit1), month.Cost(costPerUnit2), units1, units2, month.Cost(delta))
 
 	y := web.Table{Index: stateNum, Type: "yearly"}
 	y.AddComputeDiskGeneralInfo(name, id, action, diskType, zones, image, snapshot)
-	y.AddComputeDiskPricing("year", costPerUnit1*hourlyToYearly, costPerUnit2*hourlyToYearly, units1, units2, delta*hourlyToYearly)
+	y.AddComputeDiskPricing(Year.Unit(currency), Year.Cost(costPerUnit1), Year.Cost(costPerUnit2), units1, units2, Year.Cost(delta))
 
-	return &web.PricingTypeTables{Hourly: h, Monthly: m, Yearly: y}
+	return &web.PricingTypeTables{Hourly: h, Monthly: m, Yearly: y}, nil
 }
 
-// ToTable creates a table.Table and fills it with the pricing information from ComputeDiskState.
-func (state *ComputeDiskState) ToTable() (*table.Table, error) {
+// ToTable creates a table.Table and fills it with the pricing information from ComputeDiskState over the given period.
+func (state *ComputeDiskState) ToTable(p Period) (*table.Table, error) {
+	currency, err := state.Currency()
+	if err != nil {
+		return nil, err
+	}
+
 	name, id, action, diskType, zones, image, snapshot := state.generalChanges()
 	t := &table.Table{}
 	autoMerge := table.RowConfig{AutoMerge: true}
@@ -245,11 +307,12 @@ func (state *ComputeDiskState) ToTable() (*table.Table, error) {
 	t.AppendRow(table.Row{"Snapshot", snapshot + " ", snapshot + " "}, autoMerge)
 	t.AppendRow(table.Row{"Action", action + " ", action + " "}, autoMerge)
 
-	header := "Pricing Information\n(USD/h)"
+	header := "Pricing Information\n(" + p.Unit(currency) + ")"
 	t.AppendRow(table.Row{header, header, header}, autoMerge)
 	t.AppendRow(table.Row{" ", " ", "Disk"}, autoMerge)
 
 	costPerUnit1, costPerUnit2, units1, units2, delta := state.costChanges()
+	costPerUnit1, costPerUnit2, delta = p.Cost(costPerUnit1), p.Cost(costPerUnit2), p.Cost(delta)
 	f1 := func(x float64) string { return fmt.Sprintf("%.6f", x) }
 	f2 := func(x int64) string { return fmt.Sprintf("%d", x) }
 	total1 := f1(costPerUnit1 * float64(units1))
@@ -283,28 +346,29 @@ func (state *ComputeDiskState) ToTable() (*table.Table, error) {
 }
 
 // GetSummaryRow() returns the row for SummaryTable to be outputted about the certain state.
-func (state *ComputeDiskState) GetSummaryRow() (table.Row, error) {
+func (state *ComputeDiskState) GetSummaryRow(p Period) (table.Row, error) {
 	_, r, err := syncDisks(state.Before, state.After)
 	if err != nil {
 		return table.Row{}, err
 	}
-	return table.Row{r.Name, r.ID, r.Type, state.Action, state.GetDelta()}, nil
+	return table.Row{r.Name, r.ID, r.Type, state.Action, p.Cost(state.GetDelta())}, nil
 }
 
 // ToStateOut returns a json output.
-func (state *ComputeDiskState) ToStateOut() (js.JSONOut, error) {
+func (state *ComputeDiskState) ToStateOut(p Period) (js.JSONOut, error) {
 	before, after, err := syncDisks(state.Before, state.After)
 	if err != nil {
 		return nil, err
 	}
 	out := &js.ComputeDiskStateOut{
-		Name:     js.Change{before.Name, after.Name},
-		ID:       js.Change{before.ID, after.ID},
-		Zones:    js.Change{fmt.Sprint(before.Zones), fmt.Sprint(after.Zones)},
-		DiskType: js.Change{before.Type, after.Type},
+		Name:     js.Change{Before: before.Name, After: after.Name},
+		ID:       js.Change{Before: before.ID, After: after.ID},
+		Zones:    js.Change{Before: fmt.Sprint(before.Zones), After: fmt.Sprint(after.Zones)},
+		DiskType: js.Change{Before: before.Type, After: after.Type},
 		Action:   state.Action,
 	}
 	costPerUnit1, costPerUnit2, units1, units2, delta := state.costChanges()
+	costPerUnit1, costPerUnit2, delta = p.Cost(costPerUnit1), p.Cost(costPerUnit2), p.Cost(delta)
 	pricing := js.DiskStatePricing{
 		Before: &js.DiskPricing{
 			Disk: js.Pricing{
@@ -34,7 +34,7 @@ func (core *CoreInfo) isMatch(sku *billingpb.Sku) bool {
 func (core *CoreInfo) completePricingInfo(skus []*billingpb.Sku) error {
 	sku := findMatchingSKU(core, skus)
 	if sku == nil {
-		return fmt.Errorf("could not find core pricing information")
+		return billing.NewSKUNotFoundError("could not find core pricing information")
 	}
 
 	core.UnitPricing.fillHourlyBase(sku, func(tr *billingpb.PricingExpression_TierRate) bool { return true })
@@ -67,7 +67,7 @@ func (mem *MemoryInfo) isMatch(sku *billingpb.Sku) bool {
 func (mem *MemoryInfo) completePricingInfo(skus []*billingpb.Sku) error {
 	sku := findMatchingSKU(mem, skus)
 	if sku == nil {
-		return fmt.Errorf("could not find memory pricing information")
+		return billing.NewSKUNotFoundError("could not find memory pricing information")
 	}
 
 	mem.UnitPricing.fillHourlyBase(sku, func(tr *billingpb.PricingExpression_TierRate) bool { return true })
@@ -96,6 +96,10 @@ type ComputeInstance struct {
 	UsageType   string
 	Memory      MemoryInfo
 	Cores       CoreInfo
+	// shapeErr is the error of an invalid custom machine type, reported by CompletePricingInfo
+	// along with the price of closest, the closest valid shape.
+	shapeErr error
+	closest  *ComputeInstance
 }
 
 // NewComputeInstance builds a compute instance with the specified fields nd fills the other resource details.
@@ -104,7 +108,7 @@ func NewComputeInstance(details *cd.ResourceDetail, id, name, machineType, zone,
 
 	i := strings.LastIndex(zone, "-")
 	if i < 0 {
-		return nil, fmt.Errorf("invalid zone format")
+		return nil, ErrInvalidZone
 	}
 	instance.Region = zone[:i]
 
@@ -117,6 +121,13 @@ func NewComputeInstance(details *cd.ResourceDetail, id, name, machineType, zone,
 	if err != nil {
 		return nil, err
 	}
+	// Invalid custom shapes are reported once the closest valid shape can be priced.
+	if closest, err := details.CheckCustomShape(machineType); err != nil {
+		instance.shapeErr = err
+		if closest != "" {
+			instance.closest, _ = NewComputeInstance(details, id, name, closest, zone, usageType)
+		}
+	}
 
 	instance.Memory.Extended = strings.Contains(machineType, "custom") && strings.HasSuffix(machineType, "-ext")
 
@@ -136,24 +147,54 @@ func NewComputeInstance(details *cd.ResourceDetail, id, name, machineType, zone,
 	return instance, nil
 }
 
+// CheckZone returns an error listing nearby zones offering the machine type if the zone of the instance doesn't,
+// in which case creating the instance would fail at apply time.
+func (instance *ComputeInstance) CheckZone(details *cd.ResourceDetail) error {
+	return details.CheckMachineZone(instance.MachineType, instance.Zone)
+}
+
 // CompletePricingInfo fills the pricing information fields.
-func (instance *ComputeInstance) CompletePricingInfo(catalog *billing.ComputeEngineCatalog) error {
-	cores, err := catalog.GetCoreSKUs(instance.UsageType)
+// It fails for invalid custom machine types, with the hourly price of the closest valid shape if it is known.
+func (instance *ComputeInstance) CompletePricingInfo(catalog *billing.Catalog) error {
+	if instance.shapeErr != nil {
+		if instance.closest == nil || instance.closest.CompletePricingInfo(catalog) != nil {
+			return instance.shapeErr
+		}
+		c := instance.closest
+		return fmt.Errorf("%w, priced at %.6f %s/hour", instance.shapeErr,
+			c.Cores.getTotalPrice()+c.Memory.getTotalPrice(), c.Cores.UnitPricing.CurrencyType)
+	}
+
+	c, err := catalog.ComputeEngine()
+	if err != nil {
+		return err
+	}
+
+	key := billing.SKUKey{
+		ResourceGroup: instance.Cores.ResourceGroup,
+		UsageType:     instance.UsageType,
+		Region:        instance.Region,
+		Family:        billing.MachineFamily(instance.MachineType),
+	}
+	cores, err := c.LookupCoreSKUs(key)
 	if err != nil {
 		return err
 	}
 
-	mem, err := catalog.GetRAMSKUs(instance.UsageType)
+	key.ResourceGroup = instance.Memory.ResourceGroup
+	mem, err := c.LookupRAMSKUs(key)
 	if err != nil {
 		return err
 	}
 
-	filteredCores, err := filterSKUs(cores, instance.Region, instance.Description)
+	// The index narrows the SKUs down to a few candidates, the description tells apart
+	// custom, extended memory and committed use SKUs.
+	filteredCores, err := billing.DescriptionFilter(cores, instance.Description.Contains, instance.Description.Omits)
 	if err != nil {
 		return err
 	}
 
-	filteredRAM, err := filterSKUs(mem, instance.Region, instance.Description)
+	filteredRAM, err := billing.DescriptionFilter(mem, instance.Description.Contains, instance.Description.Omits)
 	if err != nil {
 		return err
 	}
@@ -166,6 +207,11 @@ func (instance *ComputeInstance) CompletePricingInfo(catalog *billing.ComputeEng
 		return err
 	}
 
+	if catalog.Explain() {
+		instance.Cores.UnitPricing.explain(catalog, findMatchingSKU(&instance.Cores, filteredCores), instance.Region, instance.Description, cores)
+		instance.Memory.UnitPricing.explain(catalog, findMatchingSKU(&instance.Memory, filteredRAM), instance.Region, instance.Description, mem)
+	}
+
 	return nil
 }
 
@@ -174,19 +220,20 @@ type ComputeInstanceState struct {
 	Before *ComputeInstance
 	After  *ComputeInstance
 	Action string
+	ResourceAddresses
 }
 
 // CompletePricingInfo completes pricing information of both before and after states.
-func (state *ComputeInstanceState) CompletePricingInfo(catalog *billing.ComputeEngineCatalog) error {
+func (state *ComputeInstanceState) CompletePricingInfo(catalog *billing.Catalog) error {
 	if state.Before != nil {
 		if err := state.Before.CompletePricingInfo(catalog); err != nil {
-			return fmt.Errorf(state.Before.Name + "(" + state.Before.MachineType + ")" + ": " + err.Error())
+			return fmt.Errorf("%s(%s): %w", state.Before.Name, state.Before.MachineType, err)
 		}
 	}
 
 	if state.After != nil {
 		if err := state.After.CompletePricingInfo(catalog); err != nil {
-			return fmt.Errorf(state.After.Name + "(" + state.After.MachineType + ")" + ": " + err.Error())
+			return fmt.Errorf("%s(%s): %w", state.After.Name, state.After.MachineType, err)
 		}
 	}
 	return nil
@@ -270,36 +317,97 @@ func (state *ComputeInstanceState) getCostChanges() (cpuCostPerUnit1, cpuCostPer
 	return
 }
 
+// pricings returns the before and after pricing of the CPU and RAM of the instance.
+func (state *ComputeInstanceState) pricings() []priceComponent {
+	var before, after []componentPricing
+	if state.Before != nil {
+		before = []componentPricing{{name: "CPU", pricing: state.Before.Cores.UnitPricing}, {name: "RAM", pricing: state.Before.Memory.UnitPricing}}
+	}
+	if state.After != nil {
+		after = []componentPricing{{name: "CPU", pricing: state.After.Cores.UnitPricing}, {name: "RAM", pricing: state.After.Memory.UnitPricing}}
+	}
+	return mergeComponents(before, after)
+}
+
+// Explain returns the SKU matches of the CPU and RAM of the instance.
+func (state *ComputeInstanceState) Explain() []ComponentMatch {
+	name, _, _, _, _, _, _ := state.getGeneralChanges()
+	return componentsExplain(name, state.pricings())
+}
+
+// Currency returns the currency of the CPU and RAM pricing.
+func (state *ComputeInstanceState) Currency() (string, error) {
+	return componentsCurrency(state.pricings())
+}
+
+// report describes the compute instance for the machine-readable outputs.
+func (instance *ComputeInstance) report() *ResourceReport {
+	if instance == nil {
+		return nil
+	}
+
+	mem, _ := conv.Convert("gib", instance.Memory.AmountGiB, instance.Memory.UnitPricing.UsageUnit)
+	return &ResourceReport{
+		Name: instance.Name,
+		Attributes: map[string]interface{}{
+			"id":           instance.ID,
+			"machine_type": instance.MachineType,
+			"zone":         instance.Zone,
+			"usage_type":   instance.UsageType,
+			"cpu_type":     instance.Cores.Type,
+			"ram_type":     instance.Memory.Type,
+		},
+		Components: componentCosts([]componentPricing{
+			{"CPU", instance.Cores.UnitPricing.HourlyUnitPrice * instance.Cores.Fractional, float64(instance.Cores.Number), instance.Cores.UnitPricing},
+			{"RAM", instance.Memory.UnitPricing.HourlyUnitPrice, mem, instance.Memory.UnitPricing},
+		}),
+	}
+}
+
+// Report describes the change of the compute instance for the machine-readable outputs.
+func (state *ComputeInstanceState) Report() StateReport {
+	return StateReport{Kind: "compute_instance", Addresses: state.Addresses, Action: state.Action,
+		Before: state.Before.report(), After: state.After.report()}
+}
+
 // GetWebTables returns html pricing information table with hourly, monthly and yearly pricing.
-func (state *ComputeInstanceState) GetWebTables(stateNum int) *web.PricingTypeTables {
+func (state *ComputeInstanceState) GetWebTables(stateNum int, month Period) (*web.PricingTypeTables, error) {
 	name, ID, action, machineType, zone, cpuType, memType := state.getGeneralChanges()
+	currency, err := state.Currency()
+	if err != nil {
+		return nil, err
+	}
 	cpuCostPerUnit1, cpuCostPerUnit2, cpuUnits1, cpuUnits2,
 		memCostPerUnit1, memCostPerUnit2, memUnits1, memUnits2 := state.getCostChanges()
 
 	h := web.Table{Index: stateNum, Type: "hourly"}
 	h.AddComputeInstanceGeneralInfo(name, ID, action, machineType, zone, cpuType, memType)
-	h.AddComputeInstancePricing("hour", cpuCostPerUnit1, cpuCostPerUnit2, cpuUnits1, cpuUnits2,
+	h.AddComputeInstancePricing(Hour.Unit(currency), cpuCostPerUnit1, cpuCostPerUnit2, cpuUnits1, cpuUnits2,
 		memCostPerUnit1, memCostPerUnit2, memUnits1, memUnits2)
 
 	m := web.Table{Index: stateNum, Type: "monthly"}
 	m.AddComputeInstanceGeneralInfo(name, ID, action, machineType, zone, cpuType, memType)
-	m.AddComputeInstancePricing("month", cpuCostPerUnit1*hourlyToMonthly, cpuCostPerUnit2*hourlyToMonthly, cpuUnits1, cpuUnits2,
-		memCostPerUnit1*hourlyToMonthly, memCostPerUnit2*hourlyToMonthly, memUnits1, memUnits2)
+	m.AddComputeInstancePricing(month.Unit(currency), month.Cost(cpuCostPerUnit1), month.Cost(cpuCostPerUnit2), cpuUnits1, cpuUnits2,
+		month.Cost(memCostPerUnit1), month.Cost(memCostPerUnit2), memUnits1, memUnits2)
 
 	y := web.Table{Index: stateNum, Type: "yearly"}
 	y.AddComputeInstanceGeneralInfo(name, ID, action, machineType, zone, cpuType, memType)
-	y.AddComputeInstancePricing("year", cpuCostPerUnit1*hourlyToYearly, cpuCostPerUnit2*hourlyToYearly, cpuUnits1, cpuUnits2,
-		memCostPerUnit1*hourlyToYearly, memCostPerUnit2*hourlyToYearly, memUnits1, memUnits2)
+	y.AddComputeInstancePricing(Year.Unit(currency), Year.Cost(cpuCostPerUnit1), Year.Cost(cpuCostPerUnit2), cpuUnits1, cpuUnits2,
+		Year.Cost(memCostPerUnit1), Year.Cost(memCostPerUnit2), memUnits1, memUnits2)
 
-	return &web.PricingTypeTables{Hourly: h, Monthly: m, Yearly: y}
+	return &web.PricingTypeTables{Hourly: h, Monthly: m, Yearly: y}, nil
 }
 
-// ToTable creates a table.Table and fills it with the pricing information from ComputeInstanceState.
-func (state *ComputeInstanceState) ToTable() (*table.Table, error) {
+// ToTable creates a table.Table and fills it with the pricing information from ComputeInstanceState over the given period.
+func (state *ComputeInstanceState) ToTable(p Period) (*table.Table, error) {
 	before, after, err := syncInstances(state.Before, state.After)
 	if err != nil {
 		return nil, err
 	}
+	currency, err := state.Currency()
+	if err != nil {
+		return nil, err
+	}
 
 	t := &table.Table{}
 	autoMerge := table.RowConfig{AutoMerge: true}
@@ -308,13 +416,13 @@ func (state *ComputeInstanceState) ToTable() (*table.Table, error) {
 	t.AppendRow(initRow("Zone", before.Zone, after.Zone, false), autoMerge)
 	t.AppendRow(initRow("Machine type", before.MachineType, after.MachineType, true), autoMerge)
 	t.AppendRow(initRow("Action", state.Action, state.Action, false), autoMerge)
-	h := "Pricing Information\n(USD/h)"
+	h := "Pricing Information\n(" + p.Unit(currency) + ")"
 	t.AppendRow(table.Row{h, h, h, h, h}, autoMerge)
-	core1, mem1, t1, err := getMemCoreInfo(state.Before)
+	core1, mem1, t1, err := getMemCoreInfo(state.Before, p)
 	if err != nil {
 		return nil, err
 	}
-	core2, mem2, t2, err := getMemCoreInfo(state.After)
+	core2, mem2, t2, err := getMemCoreInfo(state.After, p)
 	if err != nil {
 		return nil, err
 	}
@@ -332,6 +440,7 @@ func (state *ComputeInstanceState) ToTable() (*table.Table, error) {
 	})
 
 	dCore, dMem := state.getDeltas()
+	dCore, dMem = p.Cost(dCore), p.Cost(dMem)
 
 	color := text.FgGreen
 	change := "No change"
@@ -352,37 +461,38 @@ func (state *ComputeInstanceState) ToTable() (*table.Table, error) {
 }
 
 // GetSummaryRow() returns the row for SummaryTable to be outputted about the certain state.
-func (state *ComputeInstanceState) GetSummaryRow() (table.Row, error) {
+func (state *ComputeInstanceState) GetSummaryRow(p Period) (table.Row, error) {
 	dCore, dMem := state.getDeltas()
 	_, r, err := syncInstances(state.Before, state.After)
 	if err != nil {
 		return table.Row{}, err
 	}
-	return table.Row{r.Name, r.ID, r.MachineType, state.Action, fmt.Sprintf("%.6f", dCore+dMem)}, nil
+	return table.Row{r.Name, r.ID, r.MachineType, state.Action, fmt.Sprintf("%.6f", p.Cost(dCore+dMem))}, nil
 }
 
 // ToStateOut creates ComputeInstanceStateOut from state struct to render output in json format.
-func (state *ComputeInstanceState) ToStateOut() (js.JSONOut, error) {
+func (state *ComputeInstanceState) ToStateOut(p Period) (js.JSONOut, error) {
 	before, after, err := syncInstances(state.Before, state.After)
 	if err != nil {
 		return nil, err
 	}
 	out := &js.ComputeInstanceStateOut{
-		Name:        js.Change{before.Name, after.Name},
-		InstanceID:  js.Change{before.ID, after.ID},
-		Zone:        js.Change{before.Zone, after.Zone},
-		MachineType: js.Change{before.MachineType, after.MachineType},
-		CpuType:     js.Change{before.Cores.Type, after.Cores.Type},
-		RamType:     js.Change{before.Memory.Type, after.Memory.Type},
+		Name:        js.Change{Before: before.Name, After: after.Name},
+		InstanceID:  js.Change{Before: before.ID, After: after.ID},
+		Zone:        js.Change{Before: before.Zone, After: after.Zone},
+		MachineType: js.Change{Before: before.MachineType, After: after.MachineType},
+		CpuType:     js.Change{Before: before.Cores.Type, After: after.Cores.Type},
+		RamType:     js.Change{Before: before.Memory.Type, After: after.Memory.Type},
 		Action:      state.Action,
 	}
 
 	dCore, dMem := state.getDeltas()
-	beforeOut, err := completeInstanceOut(state.Before)
+	dCore, dMem = p.Cost(dCore), p.Cost(dMem)
+	beforeOut, err := completeInstanceOut(state.Before, p)
 	if err != nil {
 		return nil, err
 	}
-	afterOut, err := completeInstanceOut(state.After)
+	afterOut, err := completeInstanceOut(state.After, p)
 	if err != nil {
 		return nil, err
 	}
@@ -10,7 +10,10 @@ import (
 	"testing"
 
 	"github.com/golang/protobuf/jsonpb"
+	"github.com/googleinterns/terraform-cost-estimation/billing"
+	cd "github.com/googleinterns/terraform-cost-estimation/resources/classdetail"
 	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+	"google.golang.org/genproto/googleapis/type/money"
 )
 
 func readSKU(path string) (*billingpb.Sku, error) {
@@ -58,6 +61,94 @@ func mapToPricingInfo(skus []*billingpb.Sku, correctTierRate func(*billingpb.Pri
 	return
 }
 
+func TestNewComputeInstance(t *testing.T) {
+	details, err := cd.NewResourceDetail()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		machineType string
+		zone        string
+		kind        ErrorKind
+	}{
+		{"valid", "n2-standard-4", "us-central1-a", ""},
+		{"unknown_machine_type", "n2-standard-3", "us-central1-a", KindUnknownMachineType},
+		{"invalid_zone", "n2-standard-4", "uscentral1a", KindInvalidZone},
+		{"machine_type_not_in_zone", "m1-ultramem-40", "europe-west6-a", KindInvalidZone},
+		{"custom_machine_type_not_in_zone", "n2-custom-2-4096", "us-west4-d", KindInvalidZone},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			instance, err := NewComputeInstance(details, "", "vm", test.machineType, test.zone, "OnDemand")
+			if err == nil {
+				err = instance.CheckZone(details)
+			}
+			kind := ErrorKind("")
+			if err != nil {
+				kind = ErrorKindOf(err, KindInvalidConfig)
+			}
+			if kind != test.kind {
+				t.Errorf("NewComputeInstance(%s, %s) and CheckZone() error = %v of kind %q, want kind %q",
+					test.machineType, test.zone, err, kind, test.kind)
+			}
+		})
+	}
+}
+
+func TestCompletePricingInfoCustomShape(t *testing.T) {
+	details, err := cd.NewResourceDetail()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sku := func(description, group, unit string, nanos int32) *billingpb.Sku {
+		return &billingpb.Sku{
+			Description:    description,
+			Category:       &billingpb.Category{ResourceFamily: "Compute", ResourceGroup: group, UsageType: "OnDemand"},
+			ServiceRegions: []string{"us-central1"},
+			PricingInfo: []*billingpb.PricingInfo{{PricingExpression: &billingpb.PricingExpression{
+				UsageUnitDescription: unit,
+				TieredRates:          []*billingpb.PricingExpression_TierRate{{UnitPrice: &money.Money{CurrencyCode: "USD", Nanos: nanos}}},
+			}}},
+		}
+	}
+	catalog := billing.NewCatalogFromSKUs(map[string][]*billingpb.Sku{billing.ComputeEngineService: {
+		sku("Custom Instance Core running in Americas", "CPU", "hour", 30000000),
+		sku("Custom Instance Ram running in Americas", "RAM", "gibibyte hour", 4000000),
+		sku("Custom Extended Instance Ram running in Americas", "RAM", "gibibyte hour", 9000000),
+	}})
+
+	tests := []struct {
+		name        string
+		machineType string
+		err         string
+	}{
+		{"valid", "custom-4-4096", ""},
+		{"odd_vcpus", "custom-3-4096", "invalid custom machine type custom-3-4096: N1 custom machine types need " +
+			"1 or an even number of vCPUs up to 96 (closest valid shape: custom-4-4096), priced at 0.136000 USD/hour"},
+		{"needs_extended_memory", "custom-2-16384", "invalid custom machine type custom-2-16384: memory above " +
+			"6.5 GiB per vCPU needs extended memory (-ext) (closest valid shape: custom-2-16384-ext), priced at 0.204000 USD/hour"},
+		{"closest_not_priced", "n2-custom-3-4096", "invalid custom machine type n2-custom-3-4096: N2 custom machine types need " +
+			"an even number of vCPUs up to 32, then a multiple of 4 up to 80 (closest valid shape: n2-custom-4-4096)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			instance, err := NewComputeInstance(details, "", "vm", test.machineType, "us-central1-a", "OnDemand")
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = instance.CompletePricingInfo(catalog)
+			if test.err == "" && err != nil || test.err != "" && (err == nil || err.Error() != test.err ||
+				ErrorKindOf(err, KindPricing) != KindInvalidConfig) {
+				t.Errorf("CompletePricingInfo() of %s = %v, want %q", test.machineType, err, test.err)
+			}
+		})
+	}
+}
+
 func TestCompletePricingInfo(t *testing.T) {
 	skus, err := readSKUs()
 	if err != nil {
@@ -84,8 +175,8 @@ func TestCompletePricingInfo(t *testing.T) {
 		{"n1standard_ram", &m2, []*billingpb.Sku{skus[0], skus[1], skus[2], skus[3]}, p[3], nil},
 		{"cpu_resoure_group", &c1, []*billingpb.Sku{skus[0], skus[1], skus[2], skus[3]}, p[1], nil},
 		{"ram_resource_group", &m1, []*billingpb.Sku{skus[0], skus[1], skus[2], skus[3]}, p[2], nil},
-		{"no_core", &c3, []*billingpb.Sku{skus[2], skus[3]}, PricingInfo{}, fmt.Errorf("could not find core pricing information")},
-		{"no_ram", &m3, []*billingpb.Sku{skus[0], skus[1]}, PricingInfo{}, fmt.Errorf("could not find memory pricing information")},
+		{"no_core", &c3, []*billingpb.Sku{skus[2], skus[3]}, PricingInfo{}, billing.NewSKUNotFoundError("could not find core pricing information")},
+		{"no_ram", &m3, []*billingpb.Sku{skus[0], skus[1]}, PricingInfo{}, billing.NewSKUNotFoundError("could not find memory pricing information")},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,625 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	billing "github.com/googleinterns/terraform-cost-estimation/billing"
+	"github.com/googleinterns/terraform-cost-estimation/io/js"
+	"github.com/googleinterns/terraform-cost-estimation/io/web"
+	conv "github.com/googleinterns/terraform-cost-estimation/memconverter"
+	cd "github.com/googleinterns/terraform-cost-estimation/resources/classdetail"
+	"github.com/googleinterns/terraform-cost-estimation/usage"
+	"github.com/jedib0t/go-pretty/v6/table"
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+const (
+	defaultNodeMachineType = "e2-medium"
+	defaultNodeDiskType    = "pd-standard"
+	defaultNodeDiskSizeGiB = 100
+	defaultRegionalZones   = 3
+)
+
+// NodeConfig holds the configuration of the nodes of a GKE node pool.
+// Empty or zero fields are replaced by the GKE defaults.
+type NodeConfig struct {
+	MachineType string
+	DiskType    string
+	DiskSizeGiB int64
+	Preemptible bool
+}
+
+// NodePool holds information about a GKE node pool, whose nodes are priced as Compute Engine instances.
+type NodePool struct {
+	Name         string
+	Cluster      string
+	Location     string
+	Zones        []string
+	NodesPerZone float64
+	Autoscaling  bool
+	MinNodes     int
+	MaxNodes     int
+	Node         *ComputeInstance
+	BootDisk     *ComputeDisk
+}
+
+// isZone returns whether a location is a zone (e.g. us-central1-a) and not a region (e.g. us-central1).
+func isZone(location string) bool {
+	i := strings.LastIndex(location, "-")
+	return i >= 0 && len(location)-i == 2
+}
+
+// locationRegion returns the region of a zone or region location.
+func locationRegion(location string) string {
+	if isZone(location) {
+		return location[:strings.LastIndex(location, "-")]
+	}
+	return location
+}
+
+// nodeZones returns the zones in which the nodes of a cluster or node pool run.
+// Regional clusters without node locations run in three zones of the region.
+func nodeZones(details *cd.ResourceDetail, location string, nodeLocations []string) ([]string, error) {
+	switch {
+	case len(nodeLocations) > 0:
+		return nodeLocations, nil
+	case isZone(location):
+		return []string{location}, nil
+	}
+
+	zones := details.RegionZones(location)
+	if len(zones) == 0 {
+		return nil, fmt.Errorf("invalid location '" + location + "'")
+	}
+	if len(zones) > defaultRegionalZones {
+		zones = zones[:defaultRegionalZones]
+	}
+	return zones, nil
+}
+
+// NewNodePool builds a GKE node pool with the specified fields and fills the other resource details.
+// The number of nodes per zone is the node count, or the minimum node count if autoscaling is enabled.
+// The usage value nodes_per_zone overrides it with the expected average number of nodes per zone.
+func NewNodePool(details *cd.ResourceDetail, name, cluster, location string, nodeLocations []string, nodeCount int,
+	autoscaling bool, minNodes, maxNodes int, config NodeConfig, u usage.Values) (*NodePool, error) {
+
+	if location == "" {
+		return nil, fmt.Errorf("location must be specified")
+	}
+
+	pool := &NodePool{Name: name, Cluster: cluster, Location: location, Autoscaling: autoscaling,
+		MinNodes: minNodes, MaxNodes: maxNodes, NodesPerZone: float64(nodeCount)}
+
+	if autoscaling {
+		if minNodes > maxNodes {
+			return nil, fmt.Errorf("minimum node count is greater than the maximum one")
+		}
+		pool.NodesPerZone = float64(minNodes)
+	}
+	if n := u.Value("nodes_per_zone"); n > 0 {
+		pool.NodesPerZone = n
+	}
+
+	var err error
+	if pool.Zones, err = nodeZones(details, location, nodeLocations); err != nil {
+		return nil, err
+	}
+
+	if config.MachineType == "" {
+		config.MachineType = defaultNodeMachineType
+	}
+	if config.DiskType == "" {
+		config.DiskType = defaultNodeDiskType
+	}
+	if config.DiskSizeGiB <= 0 {
+		config.DiskSizeGiB = defaultNodeDiskSizeGiB
+	}
+
+	usageType := "OnDemand"
+	if config.Preemptible {
+		usageType = "Preemptible"
+	}
+
+	if pool.Node, err = NewComputeInstance(details, "", name, config.MachineType, pool.Zones[0], usageType); err != nil {
+		return nil, err
+	}
+
+	pool.BootDisk, err = NewComputeDisk(details, name, "", config.DiskType, pool.Zones[:1], "", "", config.DiskSizeGiB)
+	if err != nil {
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+// CheckZones returns an error if a zone of the pool doesn't offer the machine type of its nodes.
+func (pool *NodePool) CheckZones(details *cd.ResourceDetail) error {
+	for _, z := range pool.Zones {
+		if err := details.CheckMachineZone(pool.Node.MachineType, z); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NodeCount returns the total number of nodes of the pool in all its zones.
+func (pool *NodePool) NodeCount() float64 {
+	return pool.NodesPerZone * float64(len(pool.Zones))
+}
+
+// CompletePricingInfo fills the pricing information of the nodes and their boot disks.
+func (pool *NodePool) CompletePricingInfo(catalog *billing.Catalog) error {
+	if err := pool.Node.CompletePricingInfo(catalog); err != nil {
+		return err
+	}
+	return pool.BootDisk.completePricingInfo(catalog)
+}
+
+// components returns the hourly pricing of the billing components of all the nodes in the pool.
+// Component names are prefixed with the given string.
+func (pool *NodePool) components(prefix string) []componentPricing {
+	if pool == nil {
+		return nil
+	}
+
+	n := pool.NodeCount()
+	cores := pool.Node.Cores
+	mem, _ := conv.Convert("gib", pool.Node.Memory.AmountGiB, pool.Node.Memory.UnitPricing.UsageUnit)
+	disk, _ := conv.Convert("gib", float64(pool.BootDisk.SizeGiB), pool.BootDisk.UnitPricing.UsageUnit)
+
+	return []componentPricing{
+		{prefix + "Node CPU", cores.UnitPricing.HourlyUnitPrice * cores.Fractional, float64(cores.Number) * n, cores.UnitPricing},
+		{prefix + "Node RAM", pool.Node.Memory.UnitPricing.HourlyUnitPrice, mem * n, pool.Node.Memory.UnitPricing},
+		{prefix + "Node boot disk", pool.BootDisk.UnitPricing.HourlyUnitPrice, disk * n, pool.BootDisk.UnitPricing},
+	}
+}
+
+func (pool *NodePool) nodesString() string {
+	s := fmt.Sprintf("%g per zone x %d zone(s)", pool.NodesPerZone, len(pool.Zones))
+	if pool.Autoscaling {
+		s += fmt.Sprintf(" (autoscaling %d-%d per zone)", pool.MinNodes, pool.MaxNodes)
+	}
+	return s
+}
+
+func (pool *NodePool) nodeString() string {
+	return fmt.Sprintf("%s (%s), %s %d GiB", pool.Node.MachineType, pool.Node.UsageType, pool.BootDisk.Type,
+		pool.BootDisk.SizeGiB)
+}
+
+// NodePoolState holds the before and after states of a GKE node pool and the action performed.
+type NodePoolState struct {
+	Before *NodePool
+	After  *NodePool
+	Action string
+	ResourceAddresses
+}
+
+// CompletePricingInfo completes pricing information of both before and after states.
+func (state *NodePoolState) CompletePricingInfo(catalog *billing.Catalog) error {
+	if state.Before != nil {
+		if err := state.Before.CompletePricingInfo(catalog); err != nil {
+			return fmt.Errorf("%s(%s): %w", state.Before.Name, state.Before.Node.MachineType, err)
+		}
+	}
+
+	if state.After != nil {
+		if err := state.After.CompletePricingInfo(catalog); err != nil {
+			return fmt.Errorf("%s(%s): %w", state.After.Name, state.After.Node.MachineType, err)
+		}
+	}
+	return nil
+}
+
+func (state *NodePoolState) components() []priceComponent {
+	return mergeComponents(state.Before.components(""), state.After.components(""))
+}
+
+// GetDelta returns the hourly cost change of the node pool.
+func (state *NodePoolState) GetDelta() float64 {
+	return componentsDelta(state.components())
+}
+
+func (state *NodePoolState) generalChanges() (name string, rows [][2]string) {
+	before, after, _ := syncNodePools(state.Before, state.After)
+	name = generalChange(before.Name, after.Name)
+	rows = [][2]string{
+		{"Cluster", generalChange(before.Cluster, after.Cluster)},
+		{"Action", state.Action},
+		{"Location", generalChange(before.Location, after.Location)},
+		{"Zones", zonesChange(before.Zones, after.Zones)},
+		{"Nodes", generalChange(before.nodesString(), after.nodesString())},
+		{"Node", generalChange(before.nodeString(), after.nodeString())},
+	}
+	return
+}
+
+// Explain returns the SKU matches of the billing components.
+func (state *NodePoolState) Explain() []ComponentMatch {
+	name, _ := state.generalChanges()
+	return componentsExplain(name, state.components())
+}
+
+// Currency returns the currency of the billing components.
+func (state *NodePoolState) Currency() (string, error) {
+	return componentsCurrency(state.components())
+}
+
+// report describes the container node pool for the machine-readable outputs.
+func (pool *NodePool) report() *ResourceReport {
+	if pool == nil {
+		return nil
+	}
+
+	return &ResourceReport{
+		Name: pool.Name,
+		Attributes: map[string]interface{}{
+			"cluster":  pool.Cluster,
+			"location": pool.Location,
+			"zones":    strings.Join(pool.Zones, ","),
+			"nodes":    pool.nodesString(),
+			"node":     pool.nodeString(),
+		},
+		Components: componentCosts(pool.components("")),
+	}
+}
+
+// Report describes the change of the container node pool for the machine-readable outputs.
+func (state *NodePoolState) Report() StateReport {
+	return StateReport{Kind: "container_node_pool", Addresses: state.Addresses, Action: state.Action,
+		Before: state.Before.report(), After: state.After.report()}
+}
+
+// GetWebTables returns html pricing information table with hourly, monthly and yearly pricing.
+func (state *NodePoolState) GetWebTables(stateNum int, month Period) (*web.PricingTypeTables, error) {
+	name, rows := state.generalChanges()
+	return componentsWebTables(stateNum, name, rows, state.components(), month)
+}
+
+// ToTable creates a table.Table and fills it with the pricing information from NodePoolState.
+func (state *NodePoolState) ToTable(p Period) (*table.Table, error) {
+	if _, _, err := syncNodePools(state.Before, state.After); err != nil {
+		return nil, err
+	}
+
+	name, rows := state.generalChanges()
+	return componentsTable(append([][2]string{{"Name", name}}, rows...), state.components(), p)
+}
+
+// GetSummaryRow returns the row for SummaryTable to be outputted about the certain state.
+func (state *NodePoolState) GetSummaryRow(p Period) (table.Row, error) {
+	_, r, err := syncNodePools(state.Before, state.After)
+	if err != nil {
+		return table.Row{}, err
+	}
+	return table.Row{r.Name, r.Cluster, r.Node.MachineType, state.Action, fmt.Sprintf("%.6f", p.Cost(state.GetDelta()))}, nil
+}
+
+// ToStateOut creates NodePoolStateOut from state struct to render output in json format.
+func (state *NodePoolState) ToStateOut(p Period) (js.JSONOut, error) {
+	before, after, err := syncNodePools(state.Before, state.After)
+	if err != nil {
+		return nil, err
+	}
+
+	components := scaleComponents(state.components(), p)
+	out := &js.NodePoolStateOut{
+		Name:        js.Change{Before: before.Name, After: after.Name},
+		Cluster:     js.Change{Before: before.Cluster, After: after.Cluster},
+		Zones:       js.Change{Before: strings.Join(before.Zones, ","), After: strings.Join(after.Zones, ",")},
+		MachineType: js.Change{Before: before.Node.MachineType, After: after.Node.MachineType},
+		NodeCount:   js.Change{Before: fmt.Sprintf("%g", before.NodeCount()), After: fmt.Sprintf("%g", after.NodeCount())},
+		Action:      state.Action,
+		Pricing: js.ComponentsStatePricing{
+			Before: componentsOut(components, state.Before != nil, false),
+			After:  componentsOut(components, state.After != nil, true),
+			Delta:  componentsDelta(components),
+		},
+	}
+	return out, nil
+}
+
+// syncNodePools replace nils in state's before and after to be able to use them.
+func syncNodePools(before, after *NodePool) (*NodePool, *NodePool, error) {
+	if after == nil && before == nil {
+		return nil, nil, fmt.Errorf("After and Before can't be nil at the same time.")
+	}
+	if after == nil {
+		return before, before, nil
+	}
+	if before == nil {
+		return after, after, nil
+	}
+	return before, after, nil
+}
+
+// KubernetesCluster holds information about a GKE cluster.
+// Standard clusters carry the management fee and the node pools defined inline (including the default one).
+// Autopilot clusters are priced by the pod resource requests given as usage assumptions.
+type KubernetesCluster struct {
+	Name              string
+	ID                string
+	Location          string
+	Region            string
+	Autopilot         bool
+	PodVCPU           float64
+	PodMemoryGiB      float64
+	PodStorageGiB     float64
+	NodePools         []*NodePool
+	FeePricing        PricingInfo
+	PodCPUPricing     PricingInfo
+	PodMemoryPricing  PricingInfo
+	PodStoragePricing PricingInfo
+}
+
+// NewKubernetesCluster builds a GKE cluster with the specified fields and fills the other resource details.
+// Autopilot usage values (average requests of all running pods): pod_vcpu, pod_memory_gib, pod_ephemeral_storage_gib.
+func NewKubernetesCluster(name, id, location string, autopilot bool, nodePools []*NodePool, u usage.Values) (*KubernetesCluster, error) {
+	if location == "" {
+		return nil, fmt.Errorf("location must be specified")
+	}
+
+	cluster := &KubernetesCluster{Name: name, ID: id, Location: location, Region: locationRegion(location),
+		Autopilot: autopilot, NodePools: nodePools}
+
+	if autopilot {
+		if len(nodePools) > 0 {
+			return nil, fmt.Errorf("Autopilot clusters can't have node pools")
+		}
+		cluster.PodVCPU = u.Value("pod_vcpu")
+		cluster.PodMemoryGiB = u.Value("pod_memory_gib")
+		cluster.PodStorageGiB = u.Value("pod_ephemeral_storage_gib")
+	}
+
+	return cluster, nil
+}
+
+// CheckZones returns an error if a zone of a node pool of the cluster doesn't offer the machine type of its nodes.
+func (cluster *KubernetesCluster) CheckZones(details *cd.ResourceDetail) error {
+	for _, pool := range cluster.NodePools {
+		if err := pool.CheckZones(details); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cluster *KubernetesCluster) mode() string {
+	switch {
+	case cluster.Autopilot:
+		return "Autopilot"
+	case isZone(cluster.Location):
+		return "Zonal"
+	default:
+		return "Regional"
+	}
+}
+
+// CompletePricingInfo fills the pricing information fields.
+func (cluster *KubernetesCluster) CompletePricingInfo(catalog *billing.Catalog) error {
+	c, err := catalog.KubernetesEngine()
+	if err != nil {
+		return err
+	}
+	allRates := func(*billingpb.PricingExpression_TierRate) bool { return true }
+
+	skus, err := c.ClusterFeeSKUs()
+	if err != nil {
+		return err
+	}
+	d := Description{Contains: []string{cluster.mode()}}
+	sku, err := firstSKU(skus, cluster.Region, d)
+	if err != nil {
+		return err
+	}
+	cluster.FeePricing.fillHourlyBase(sku, allRates)
+	cluster.FeePricing.explain(catalog, sku, cluster.Region, d, skus)
+
+	if cluster.Autopilot {
+		for _, p := range []struct {
+			resource string
+			pricing  *PricingInfo
+		}{{"CPU", &cluster.PodCPUPricing}, {"Memory", &cluster.PodMemoryPricing}, {"Storage", &cluster.PodStoragePricing}} {
+			skus, err := c.AutopilotSKUs(p.resource)
+			if err != nil {
+				return err
+			}
+			sku, err := firstSKU(skus, cluster.Region, Description{})
+			if err != nil {
+				return err
+			}
+			p.pricing.fillHourlyBase(sku, allRates)
+			p.pricing.explain(catalog, sku, cluster.Region, Description{}, skus)
+		}
+	}
+
+	for _, pool := range cluster.NodePools {
+		if err := pool.CompletePricingInfo(catalog); err != nil {
+			return fmt.Errorf("node pool %s: %w", pool.Name, err)
+		}
+	}
+	return nil
+}
+
+// components returns the hourly pricing of the billing components of the cluster.
+func (cluster *KubernetesCluster) components() []componentPricing {
+	if cluster == nil {
+		return nil
+	}
+
+	c := []componentPricing{{"Management fee", cluster.FeePricing.HourlyUnitPrice, 1, cluster.FeePricing}}
+	if cluster.Autopilot {
+		mem, _ := conv.Convert("gib", cluster.PodMemoryGiB, cluster.PodMemoryPricing.UsageUnit)
+		storage, _ := conv.Convert("gib", cluster.PodStorageGiB, cluster.PodStoragePricing.UsageUnit)
+		c = append(c, componentPricing{"Pod vCPU", cluster.PodCPUPricing.HourlyUnitPrice, cluster.PodVCPU, cluster.PodCPUPricing},
+			componentPricing{"Pod memory", cluster.PodMemoryPricing.HourlyUnitPrice, mem, cluster.PodMemoryPricing},
+			componentPricing{"Pod ephemeral storage", cluster.PodStoragePricing.HourlyUnitPrice, storage, cluster.PodStoragePricing})
+	}
+
+	for _, pool := range cluster.NodePools {
+		c = append(c, pool.components(pool.Name+": ")...)
+	}
+	return c
+}
+
+func (cluster *KubernetesCluster) nodePoolsString() string {
+	var pools []string
+	for _, p := range cluster.NodePools {
+		pools = append(pools, p.Name+" ("+p.nodeString()+", "+p.nodesString()+")")
+	}
+
+	if len(pools) == 0 {
+		return "none"
+	}
+	return strings.Join(pools, "; ")
+}
+
+// KubernetesClusterState holds the before and after states of a GKE cluster and the action performed.
+type KubernetesClusterState struct {
+	Before *KubernetesCluster
+	After  *KubernetesCluster
+	Action string
+	ResourceAddresses
+}
+
+// CompletePricingInfo completes pricing information of both before and after states.
+func (state *KubernetesClusterState) CompletePricingInfo(catalog *billing.Catalog) error {
+	if state.Before != nil {
+		if err := state.Before.CompletePricingInfo(catalog); err != nil {
+			return fmt.Errorf("%s(%s): %w", state.Before.Name, state.Before.mode(), err)
+		}
+	}
+
+	if state.After != nil {
+		if err := state.After.CompletePricingInfo(catalog); err != nil {
+			return fmt.Errorf("%s(%s): %w", state.After.Name, state.After.mode(), err)
+		}
+	}
+	return nil
+}
+
+func (state *KubernetesClusterState) components() []priceComponent {
+	return mergeComponents(state.Before.components(), state.After.components())
+}
+
+// GetDelta returns the hourly cost change of the cluster.
+func (state *KubernetesClusterState) GetDelta() float64 {
+	return componentsDelta(state.components())
+}
+
+func (state *KubernetesClusterState) generalChanges() (name string, rows [][2]string) {
+	before, after, _ := syncClusters(state.Before, state.After)
+	id := before.ID
+	if id == "" {
+		id = after.ID
+	}
+
+	name = generalChange(before.Name, after.Name)
+	rows = [][2]string{
+		{"ID", id},
+		{"Action", state.Action},
+		{"Location", generalChange(before.Location, after.Location)},
+		{"Mode", generalChange(before.mode(), after.mode())},
+		{"Node pools", generalChange(before.nodePoolsString(), after.nodePoolsString())},
+	}
+	return
+}
+
+// Explain returns the SKU matches of the billing components.
+func (state *KubernetesClusterState) Explain() []ComponentMatch {
+	name, _ := state.generalChanges()
+	return componentsExplain(name, state.components())
+}
+
+// Currency returns the currency of the billing components.
+func (state *KubernetesClusterState) Currency() (string, error) {
+	return componentsCurrency(state.components())
+}
+
+// report describes the container cluster for the machine-readable outputs.
+func (cluster *KubernetesCluster) report() *ResourceReport {
+	if cluster == nil {
+		return nil
+	}
+
+	return &ResourceReport{
+		Name: cluster.Name,
+		Attributes: map[string]interface{}{
+			"id":         cluster.ID,
+			"location":   cluster.Location,
+			"mode":       cluster.mode(),
+			"node_pools": cluster.nodePoolsString(),
+		},
+		Components: componentCosts(cluster.components()),
+	}
+}
+
+// Report describes the change of the container cluster for the machine-readable outputs.
+func (state *KubernetesClusterState) Report() StateReport {
+	return StateReport{Kind: "container_cluster", Addresses: state.Addresses, Action: state.Action,
+		Before: state.Before.report(), After: state.After.report()}
+}
+
+// GetWebTables returns html pricing information table with hourly, monthly and yearly pricing.
+func (state *KubernetesClusterState) GetWebTables(stateNum int, month Period) (*web.PricingTypeTables, error) {
+	name, rows := state.generalChanges()
+	return componentsWebTables(stateNum, name, rows, state.components(), month)
+}
+
+// ToTable creates a table.Table and fills it with the pricing information from KubernetesClusterState.
+func (state *KubernetesClusterState) ToTable(p Period) (*table.Table, error) {
+	if _, _, err := syncClusters(state.Before, state.After); err != nil {
+		return nil, err
+	}
+
+	name, rows := state.generalChanges()
+	return componentsTable(append([][2]string{{"Name", name}}, rows...), state.components(), p)
+}
+
+// GetSummaryRow returns the row for SummaryTable to be outputted about the certain state.
+func (state *KubernetesClusterState) GetSummaryRow(p Period) (table.Row, error) {
+	_, r, err := syncClusters(state.Before, state.After)
+	if err != nil {
+		return table.Row{}, err
+	}
+	return table.Row{r.Name, r.ID, r.mode() + " cluster", state.Action, fmt.Sprintf("%.6f", p.Cost(state.GetDelta()))}, nil
+}
+
+// ToStateOut creates KubernetesClusterStateOut from state struct to render output in json format.
+func (state *KubernetesClusterState) ToStateOut(p Period) (js.JSONOut, error) {
+	before, after, err := syncClusters(state.Before, state.After)
+	if err != nil {
+		return nil, err
+	}
+
+	components := scaleComponents(state.components(), p)
+	out := &js.KubernetesClusterStateOut{
+		Name:      js.Change{Before: before.Name, After: after.Name},
+		ID:        js.Change{Before: before.ID, After: after.ID},
+		Location:  js.Change{Before: before.Location, After: after.Location},
+		Mode:      js.Change{Before: before.mode(), After: after.mode()},
+		NodePools: js.Change{Before: before.nodePoolsString(), After: after.nodePoolsString()},
+		Action:    state.Action,
+		Pricing: js.ComponentsStatePricing{
+			Before: componentsOut(components, state.Before != nil, false),
+			After:  componentsOut(components, state.After != nil, true),
+			Delta:  componentsDelta(components),
+		},
+	}
+	return out, nil
+}
+
+// syncClusters replace nils in state's before and after to be able to use them.
+func syncClusters(before, after *KubernetesCluster) (*KubernetesCluster, *KubernetesCluster, error) {
+	if after == nil && before == nil {
+		return nil, nil, fmt.Errorf("After and Before can't be nil at the same time.")
+	}
+	if after == nil {
+		return before, before, nil
+	}
+	if before == nil {
+		return after, after, nil
+	}
+	return before, after, nil
+}
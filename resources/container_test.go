@@ -0,0 +1,176 @@
+package resources
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	cd "github.com/googleinterns/terraform-cost-estimation/resources/classdetail"
+	"github.com/googleinterns/terraform-cost-estimation/usage"
+)
+
+func TestNewNodePool(t *testing.T) {
+	details, err := cd.NewResourceDetail()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	tests := []struct {
+		name          string
+		location      string
+		nodeLocations []string
+		nodeCount     int
+		autoscaling   bool
+		min, max      int
+		usage         usage.Values
+		zones         []string
+		nodesPerZone  float64
+		err           error
+	}{
+		{"no_location", "", nil, 1, false, 0, 0, nil, nil, 0, fmt.Errorf("location must be specified")},
+		{"invalid_region", "us-central", nil, 1, false, 0, 0, nil, nil, 0, fmt.Errorf("invalid location 'us-central'")},
+		{"invalid_autoscaling", "us-central1-a", nil, 1, true, 3, 1, nil, nil, 0,
+			fmt.Errorf("minimum node count is greater than the maximum one")},
+		{"zonal", "us-central1-a", nil, 2, false, 0, 0, nil, []string{"us-central1-a"}, 2, nil},
+		{"regional", "us-central1", nil, 1, false, 0, 0, nil,
+			[]string{"us-central1-a", "us-central1-b", "us-central1-c"}, 1, nil},
+		{"node_locations", "us-central1", []string{"us-central1-b", "us-central1-f"}, 1, false, 0, 0, nil,
+			[]string{"us-central1-b", "us-central1-f"}, 1, nil},
+		{"autoscaling_min", "us-central1-a", nil, 0, true, 1, 5, nil, []string{"us-central1-a"}, 1, nil},
+		{"autoscaling_usage", "us-central1-a", nil, 0, true, 1, 5, usage.Values{"nodes_per_zone": 2.5},
+			[]string{"us-central1-a"}, 2.5, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool, err := NewNodePool(details, "pool", "cluster", test.location, test.nodeLocations, test.nodeCount,
+				test.autoscaling, test.min, test.max, NodeConfig{}, test.usage)
+			// Test fails if the errors are different or the zones and number of nodes differ.
+			if !reflect.DeepEqual(err, test.err) {
+				t.Fatalf("NewNodePool(%s, %+v) returned error %+v; want %+v", test.location, test.nodeLocations, err, test.err)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(pool.Zones, test.zones) || pool.NodesPerZone != test.nodesPerZone {
+				t.Errorf("NewNodePool(%s, %+v) -> zones %+v, %g nodes per zone; want %+v, %g", test.location,
+					test.nodeLocations, pool.Zones, pool.NodesPerZone, test.zones, test.nodesPerZone)
+			}
+			if pool.Node.MachineType != defaultNodeMachineType || pool.BootDisk.Type != defaultNodeDiskType ||
+				pool.BootDisk.SizeGiB != defaultNodeDiskSizeGiB {
+				t.Errorf("NewNodePool(%s, %+v) did not apply the default node configuration", test.location, test.nodeLocations)
+			}
+		})
+	}
+}
+
+func TestNodePoolCheckZones(t *testing.T) {
+	details, err := cd.NewResourceDetail()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	tests := []struct {
+		name          string
+		nodeLocations []string
+		kind          ErrorKind
+	}{
+		{"offered", []string{"us-central1-a", "us-central1-f"}, ""},
+		{"first_zone_offered", []string{"us-central1-a", "europe-west6-a"}, KindInvalidZone},
+		{"no_zone_offered", []string{"europe-west6-a"}, KindInvalidZone},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool, err := NewNodePool(details, "pool", "cluster", "us-central1", test.nodeLocations, 1, false, 0, 0,
+				NodeConfig{MachineType: "c2-standard-8"}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = pool.CheckZones(details)
+			kind := ErrorKind("")
+			if err != nil {
+				kind = ErrorKindOf(err, KindInvalidConfig)
+			}
+			if kind != test.kind {
+				t.Errorf("CheckZones() of %+v = %v of kind %q, want kind %q", test.nodeLocations, err, kind, test.kind)
+			}
+		})
+	}
+}
+
+func TestNewKubernetesCluster(t *testing.T) {
+	u := usage.Values{"pod_vcpu": 4, "pod_memory_gib": 16, "pod_ephemeral_storage_gib": 10}
+
+	tests := []struct {
+		name      string
+		location  string
+		autopilot bool
+		pools     []*NodePool
+		cluster   *KubernetesCluster
+		err       error
+	}{
+		{"no_location", "", false, nil, nil, fmt.Errorf("location must be specified")},
+		{"autopilot_pools", "us-central1", true, []*NodePool{{}}, nil, fmt.Errorf("Autopilot clusters can't have node pools")},
+		{"zonal", "us-central1-a", false, nil,
+			&KubernetesCluster{Location: "us-central1-a", Region: "us-central1"}, nil},
+		{"autopilot", "us-central1", true, nil,
+			&KubernetesCluster{Location: "us-central1", Region: "us-central1", Autopilot: true, PodVCPU: 4, PodMemoryGiB: 16,
+				PodStorageGiB: 10}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cluster, err := NewKubernetesCluster("", "", test.location, test.autopilot, test.pools, u)
+			if !reflect.DeepEqual(err, test.err) || !reflect.DeepEqual(cluster, test.cluster) {
+				t.Errorf("NewKubernetesCluster(%s, %t) = %+v, %+v; want %+v, %+v", test.location, test.autopilot,
+					cluster, err, test.cluster, test.err)
+			}
+		})
+	}
+}
+
+func TestKubernetesClusterStateGetDelta(t *testing.T) {
+	fee := PricingInfo{UsageUnit: "h", HourlyUnitPrice: 0.1}
+	node := &ComputeInstance{
+		Cores:  CoreInfo{Number: 2, Fractional: 1, UnitPricing: PricingInfo{UsageUnit: "h", HourlyUnitPrice: 0.02}},
+		Memory: MemoryInfo{AmountGiB: 4, UnitPricing: PricingInfo{UsageUnit: "gibibyte", HourlyUnitPrice: 0.003}},
+	}
+	disk := &ComputeDisk{SizeGiB: 100, UnitPricing: PricingInfo{UsageUnit: "gibibyte", HourlyUnitPrice: 0.04 / hourlyToMonthly}}
+	pool := &NodePool{Name: "default-pool", Zones: []string{"us-central1-a", "us-central1-b", "us-central1-c"},
+		NodesPerZone: 2, Node: node, BootDisk: disk}
+
+	standard := &KubernetesCluster{FeePricing: fee, NodePools: []*NodePool{pool}}
+	autopilot := &KubernetesCluster{Autopilot: true, FeePricing: fee, PodVCPU: 4, PodMemoryGiB: 16,
+		PodCPUPricing:     PricingInfo{UsageUnit: "h", HourlyUnitPrice: 0.0445},
+		PodMemoryPricing:  PricingInfo{UsageUnit: "gibibyte", HourlyUnitPrice: 0.0049225},
+		PodStoragePricing: PricingInfo{UsageUnit: "gibibyte", HourlyUnitPrice: 0.0000548}}
+
+	nodes := 6.0
+	poolCost := nodes * (2*0.02 + 4*0.003 + 100*0.04/hourlyToMonthly)
+	autopilotCost := 0.1 + 4*0.0445 + 16*0.0049225
+
+	tests := []struct {
+		name  string
+		state KubernetesClusterState
+		delta float64
+	}{
+		{"create_standard", KubernetesClusterState{After: standard}, 0.1 + poolCost},
+		{"destroy_standard", KubernetesClusterState{Before: standard}, -0.1 - poolCost},
+		{"standard_to_autopilot", KubernetesClusterState{Before: standard, After: autopilot}, autopilotCost - 0.1 - poolCost},
+		{"node_pool", KubernetesClusterState{}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if d := test.state.GetDelta(); math.Abs(d-test.delta) > epsilon {
+				t.Errorf("%+v.GetDelta() = %f; want %f", test.state, d, test.delta)
+			}
+		})
+	}
+
+	if d := (&NodePoolState{After: pool}).GetDelta(); math.Abs(d-poolCost) > epsilon {
+		t.Errorf("NodePoolState.GetDelta() = %f; want %f", d, poolCost)
+	}
+}
@@ -92,3 +92,54 @@ func (d *Description) fillForComputeDisk(diskType string, regional bool) {
 		d.Omits = append(d.Omits, "Regional")
 	}
 }
+
+func (d *Description) fillForSQLInstance(databaseVersion, availability string) error {
+	switch {
+	case strings.HasPrefix(databaseVersion, "MYSQL"):
+		d.Contains = append(d.Contains, "MySQL")
+	case strings.HasPrefix(databaseVersion, "POSTGRES"):
+		d.Contains = append(d.Contains, "PostgreSQL")
+	case strings.HasPrefix(databaseVersion, "SQLSERVER"):
+		d.Contains = append(d.Contains, "SQL Server")
+	default:
+		return fmt.Errorf("unsupported database version '" + databaseVersion + "'")
+	}
+
+	// High availability instances have their own (Regional) SKUs.
+	switch availability {
+	case "REGIONAL":
+		d.Contains = append(d.Contains, "Regional")
+	case "ZONAL":
+		d.Contains = append(d.Contains, "Zonal")
+	default:
+		return fmt.Errorf("invalid availability type '" + availability + "'")
+	}
+
+	return nil
+}
+
+func (d *Description) fillForBucketStorage(locationType string) {
+	switch locationType {
+	case "MULTI_REGION":
+		d.Contains = append(d.Contains, "Multi-Region")
+	case "DUAL_REGION":
+		d.Contains = append(d.Contains, "Dual-Region")
+	default:
+		d.Omits = append(d.Omits, "Multi-Region", "Dual-Region")
+	}
+	d.Omits = append(d.Omits, "Retrieval", "Early Delete")
+}
+
+func (d *Description) fillForBucketOperations(storageClass, locationType string) {
+	switch {
+	case storageClass != "STANDARD":
+		d.Contains = append(d.Contains, storageClass[:1]+strings.ToLower(storageClass[1:])+" Storage")
+	case locationType == "MULTI_REGION":
+		d.Contains = append(d.Contains, "Multi-Regional Storage")
+	case locationType == "DUAL_REGION":
+		d.Contains = append(d.Contains, "Dual-Regional Storage")
+	default:
+		d.Contains = append(d.Contains, "Regional Storage")
+		d.Omits = append(d.Omits, "Multi-Regional", "Dual-Regional")
+	}
+}
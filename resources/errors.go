@@ -0,0 +1,131 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/googleinterns/terraform-cost-estimation/billing"
+	"github.com/googleinterns/terraform-cost-estimation/resources/classdetail/disk"
+	"github.com/googleinterns/terraform-cost-estimation/resources/classdetail/instance"
+)
+
+// ErrorKind classifies why a resource change could not be priced.
+type ErrorKind string
+
+// The kinds of the resource errors.
+const (
+	KindUnsupportedType    ErrorKind = "unsupported_type"
+	KindUnknownMachineType ErrorKind = "unknown_machine_type"
+	KindUnknownDiskType    ErrorKind = "unknown_disk_type"
+	KindInvalidZone        ErrorKind = "invalid_zone"
+	KindMissingSKU         ErrorKind = "missing_sku"
+	KindInvalidConfig      ErrorKind = "invalid_configuration"
+	KindPricing            ErrorKind = "pricing_failed"
+)
+
+var (
+	// ErrUnsupportedType is wrapped by the errors of the resource types with no registered pricer.
+	ErrUnsupportedType = errors.New("unsupported resource type")
+	// ErrInvalidZone is returned for the zones that are not of the form region-zone.
+	ErrInvalidZone = errors.New("invalid zone format")
+)
+
+// ResourceError is the error of a resource change that could not be decoded or priced,
+// so its cost is missing from the totals.
+type ResourceError struct {
+	Address string
+	Type    string
+	Kind    ErrorKind
+	Err     error
+}
+
+// NewResourceError returns the error of the resource change with the given address and type.
+// The kind is found from the error chain, the given kind is used if the cause is not a known one.
+func NewResourceError(address, resourceType string, kind ErrorKind, err error) *ResourceError {
+	return &ResourceError{Address: address, Type: resourceType, Kind: ErrorKindOf(err, kind), Err: err}
+}
+
+// NewStateErrors returns the errors of all the resource changes of the state (e.g. the rules of a load balancer)
+// that failed to be priced with the given error.
+func NewStateErrors(state ResourceState, err error) (errs []*ResourceError) {
+	var addresses []string
+	if r, ok := state.(Reporter); ok {
+		addresses = r.Report().Addresses
+	}
+	if len(addresses) == 0 {
+		return []*ResourceError{NewResourceError("", "", KindPricing, err)}
+	}
+	for _, a := range addresses {
+		errs = append(errs, NewResourceError(a, AddressType(a), KindPricing, err))
+	}
+	return
+}
+
+func (e *ResourceError) Error() string {
+	if e.Address == "" {
+		return fmt.Sprintf("%s: %v", e.Kind, e.Err)
+	}
+	return fmt.Sprintf("%s: %s: %v", e.Address, e.Kind, e.Err)
+}
+
+func (e *ResourceError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorKindOf returns the kind of the error from its chain, or the fallback kind if the cause is not a known one.
+func ErrorKindOf(err error, fallback ErrorKind) ErrorKind {
+	switch {
+	case errors.Is(err, ErrUnsupportedType):
+		return KindUnsupportedType
+	case errors.Is(err, instance.ErrUnknownMachineType):
+		return KindUnknownMachineType
+	case errors.Is(err, disk.ErrUnknownDiskType):
+		return KindUnknownDiskType
+	case errors.Is(err, ErrInvalidZone), errors.Is(err, disk.ErrUnknownLocation), errors.Is(err, instance.ErrMachineTypeUnavailable):
+		return KindInvalidZone
+	case errors.Is(err, instance.ErrInvalidCustomShape):
+		return KindInvalidConfig
+	case errors.Is(err, billing.ErrSKUNotFound):
+		return KindMissingSKU
+	}
+	return fallback
+}
+
+// AddressType returns the resource type of the Terraform resource address
+// (e.g. google_compute_instance for module.a.google_compute_instance.vm[0]).
+func AddressType(address string) string {
+	parts := strings.Split(address, ".")
+	i := 0
+	for i+2 < len(parts) && parts[i] == "module" {
+		i += 2
+	}
+	if parts[i] == "data" && i+1 < len(parts) {
+		i++
+	}
+	return parts[i]
+}
+
+// PricedCount returns the number of priced resource changes of the states: one per address, at least one per state,
+// so that a grouped state (e.g. a load balancer with its forwarding rules) counts each of its resources.
+func PricedCount(states []ResourceState) int {
+	priced := 0
+	for _, s := range states {
+		n := 1
+		if r, ok := s.(Reporter); ok && len(r.Report().Addresses) > 0 {
+			n = len(r.Report().Addresses)
+		}
+		priced += n
+	}
+	return priced
+}
+
+// Coverage returns the percentage of the resource changes that were priced: the changes of the states
+// (see PricedCount) over those and the unpriced ones. It is 100 if there are none.
+func Coverage(states []ResourceState, unpriced []*ResourceError) float64 {
+	priced := PricedCount(states)
+	if priced+len(unpriced) == 0 {
+		return 100
+	}
+	return 100 * float64(priced) / float64(priced+len(unpriced))
+}
@@ -0,0 +1,108 @@
+package resources
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/googleinterns/terraform-cost-estimation/billing"
+	"github.com/googleinterns/terraform-cost-estimation/resources/classdetail/disk"
+	"github.com/googleinterns/terraform-cost-estimation/resources/classdetail/instance"
+)
+
+func TestErrorKindOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected ErrorKind
+	}{
+		{"unsupported", fmt.Errorf("%w 'google_compute_network'", ErrUnsupportedType), KindUnsupportedType},
+		{"machine_type", fmt.Errorf("vm(n1-standard-3): %w", instance.ErrUnknownMachineType), KindUnknownMachineType},
+		{"disk_type", fmt.Errorf("%w 'pd'", disk.ErrUnknownDiskType), KindUnknownDiskType},
+		{"zone", ErrInvalidZone, KindInvalidZone},
+		{"disk_location", fmt.Errorf("%w 'moon'", disk.ErrUnknownLocation), KindInvalidZone},
+		{"machine_type_zone", fmt.Errorf("%w in europe-west6-a: c2-standard-8", instance.ErrMachineTypeUnavailable), KindInvalidZone},
+		{"custom_shape", &instance.CustomShapeError{MachineType: "custom-3-4096", Closest: "custom-4-4096"}, KindInvalidConfig},
+		{"sku", fmt.Errorf("vm(n1-standard-1): %w", billing.NewSKUNotFoundError("found no core SKU of this usage type")), KindMissingSKU},
+		{"other", fmt.Errorf("in_use must be between 0 and 1"), KindInvalidConfig},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := ErrorKindOf(test.err, KindInvalidConfig); actual != test.expected {
+				t.Errorf("ErrorKindOf(%v) = %s, want %s", test.err, actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestAddressType(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		expected string
+	}{
+		{"root", "google_compute_instance.vm", "google_compute_instance"},
+		{"index", "google_compute_instance.vm[0]", "google_compute_instance"},
+		{"module", "module.a.module.b[\"x\"].google_compute_disk.d", "google_compute_disk"},
+		{"data", "module.a.data.google_compute_image.img", "google_compute_image"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := AddressType(test.address); actual != test.expected {
+				t.Errorf("AddressType(%q) = %q, want %q", test.address, actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	lb := &LoadBalancerState{ResourceAddresses: ResourceAddresses{[]string{"google_compute_forwarding_rule.a", "google_compute_forwarding_rule.b"}}}
+	ip := &AddressState{ResourceAddresses: ResourceAddresses{[]string{"google_compute_address.ip"}}}
+	unpriced := []*ResourceError{NewResourceError("google_compute_network.net", "google_compute_network", KindUnsupportedType, ErrUnsupportedType)}
+
+	tests := []struct {
+		name     string
+		states   []ResourceState
+		unpriced []*ResourceError
+		expected float64
+	}{
+		{"empty", nil, nil, 100},
+		{"all_priced", []ResourceState{ip}, nil, 100},
+		{"none_priced", nil, unpriced, 0},
+		{"load_balancer", []ResourceState{lb, ip}, unpriced, 75},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := Coverage(test.states, test.unpriced); actual != test.expected {
+				t.Errorf("Coverage() = %f, want %f", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestPricedCount(t *testing.T) {
+	lb := &LoadBalancerState{ResourceAddresses: ResourceAddresses{[]string{"google_compute_forwarding_rule.a", "google_compute_forwarding_rule.b"}}}
+	ip := &AddressState{ResourceAddresses: ResourceAddresses{[]string{"google_compute_address.ip"}}}
+	unnamed := &AddressState{}
+
+	tests := []struct {
+		name     string
+		states   []ResourceState
+		expected int
+	}{
+		{"empty", nil, 0},
+		{"address", []ResourceState{ip}, 1},
+		{"load_balancer", []ResourceState{lb, ip}, 3},
+		{"no_addresses", []ResourceState{unnamed}, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := PricedCount(test.states); actual != test.expected {
+				t.Errorf("PricedCount() = %d, want %d", actual, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package resources
+
+import (
+	billing "github.com/googleinterns/terraform-cost-estimation/billing"
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+// SKUMatch records the SKU a component was priced with and how it was chosen:
+// the description filters applied and the candidate SKUs of the region that were rejected.
+type SKUMatch struct {
+	ID          string
+	Description string
+	Regions     []string
+	UsageType   string
+	TierStart   float64
+	Contains    []string
+	Omits       []string
+	Rejected    []RejectedSKU
+}
+
+// RejectedSKU is a candidate SKU that was not used for pricing and the reason why.
+type RejectedSKU struct {
+	ID          string
+	Description string
+	Reason      string
+}
+
+// ComponentMatch is the SKU match of a billing component in the before or after state of a resource.
+type ComponentMatch struct {
+	Resource  string
+	Component string
+	State     string
+	Match     *SKUMatch
+}
+
+// Explainer is implemented by the resource states that report the SKU matches of their components.
+// The matches are recorded only if the catalog explains them (see billing.Catalog.SetExplain).
+type Explainer interface {
+	Explain() []ComponentMatch
+}
+
+// explain records the SKU the pricing was filled with, if the catalog explains its matches.
+// The candidates are the SKUs of the region among skus, rejected either by the description
+// filters or because another SKU was chosen.
+func (p *PricingInfo) explain(catalog *billing.Catalog, sku *billingpb.Sku, region string, d Description, skus []*billingpb.Sku) {
+	if !catalog.Explain() || sku == nil {
+		return
+	}
+
+	m := &SKUMatch{
+		ID:          sku.SkuId,
+		Description: sku.Description,
+		Regions:     sku.ServiceRegions,
+		UsageType:   sku.Category.UsageType,
+		TierStart:   p.tierStart,
+		Contains:    d.Contains,
+		Omits:       d.Omits,
+	}
+
+	inRegion := billing.Query{Region: region}
+	filters := billing.Query{Contains: d.Contains, Omits: d.Omits}
+	for _, c := range skus {
+		if c == sku || !inRegion.Matches(c) {
+			continue
+		}
+		reason := "not selected"
+		if !filters.Matches(c) {
+			reason = "description filters"
+		}
+		m.Rejected = append(m.Rejected, RejectedSKU{ID: c.SkuId, Description: c.Description, Reason: reason})
+	}
+	p.SKU = m
+}
+
+// componentsExplain returns the SKU matches of the before and after components of a resource.
+func componentsExplain(resource string, components []priceComponent) (matches []ComponentMatch) {
+	for _, c := range components {
+		if c.pricing1.SKU != nil {
+			matches = append(matches, ComponentMatch{Resource: resource, Component: c.name, State: "before", Match: c.pricing1.SKU})
+		}
+		if c.pricing2.SKU != nil {
+			matches = append(matches, ComponentMatch{Resource: resource, Component: c.name, State: "after", Match: c.pricing2.SKU})
+		}
+	}
+	return
+}
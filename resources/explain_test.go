@@ -0,0 +1,113 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+
+	billing "github.com/googleinterns/terraform-cost-estimation/billing"
+	cd "github.com/googleinterns/terraform-cost-estimation/resources/classdetail"
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+	"google.golang.org/genproto/googleapis/type/money"
+)
+
+func TestPricingInfoExplain(t *testing.T) {
+	tiered := func(id, description, region string) *billingpb.Sku {
+		sku := benchmarkSKU(description, "Storage", "RegionalStorage", "OnDemand", "gibibyte month", region)
+		sku.SkuId = id
+		sku.PricingInfo[0].PricingExpression.TieredRates = append(sku.PricingInfo[0].PricingExpression.TieredRates,
+			&billingpb.PricingExpression_TierRate{StartUsageAmount: 1024, UnitPrice: &money.Money{Nanos: 500000}})
+		return sku
+	}
+	standard := tiered("A", "Standard Storage US Regional", "us-central1")
+	nearline := tiered("B", "Nearline Storage US Regional", "us-central1")
+	standardEU := tiered("C", "Standard Storage Europe", "europe-west1")
+	dual := tiered("D", "Standard Storage US Dual-Region", "us-central1")
+	skus := []*billingpb.Sku{nearline, standardEU, dual, standard}
+	d := Description{Contains: []string{"Standard"}, Omits: []string{"Dual-Region"}}
+
+	explained := billing.NewCatalogFromSKUs(nil)
+	explained.SetExplain(true)
+
+	tests := []struct {
+		name    string
+		catalog *billing.Catalog
+		amount  float64
+		match   *SKUMatch
+	}{
+		{"disabled", billing.NewCatalogFromSKUs(nil), 10, nil},
+		{"first_tier", explained, 10, &SKUMatch{
+			ID:          "A",
+			Description: "Standard Storage US Regional",
+			Regions:     []string{"us-central1"},
+			UsageType:   "OnDemand",
+			Contains:    d.Contains,
+			Omits:       d.Omits,
+			Rejected: []RejectedSKU{
+				{"B", "Nearline Storage US Regional", "description filters"},
+				{"D", "Standard Storage US Dual-Region", "description filters"},
+			},
+		}},
+		{"second_tier", explained, 2048, &SKUMatch{
+			ID:          "A",
+			Description: "Standard Storage US Regional",
+			Regions:     []string{"us-central1"},
+			UsageType:   "OnDemand",
+			TierStart:   1024,
+			Contains:    d.Contains,
+			Omits:       d.Omits,
+			Rejected: []RejectedSKU{
+				{"B", "Nearline Storage US Regional", "description filters"},
+				{"D", "Standard Storage US Dual-Region", "description filters"},
+			},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := monthlyPricing(test.catalog, skus, "us-central1", d, test.amount)
+			if err != nil || !reflect.DeepEqual(p.SKU, test.match) {
+				t.Errorf("monthlyPricing(%f).SKU = %+v, %+v; want %+v, nil", test.amount, p.SKU, err, test.match)
+			}
+		})
+	}
+}
+
+func TestComputeInstanceStateExplain(t *testing.T) {
+	details, err := cd.NewResourceDetail()
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog := benchmarkCatalog()
+	catalog.SetExplain(true)
+
+	instance, err := NewComputeInstance(details, "", "test", "n2-standard-4", "us-central1-a", "OnDemand")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &ComputeInstanceState{After: instance, Action: "create"}
+	if err := state.CompletePricingInfo(catalog); err != nil {
+		t.Fatal(err)
+	}
+
+	match := func(description, custom string) *SKUMatch {
+		return &SKUMatch{
+			Description: description,
+			Regions:     []string{"us-central1"},
+			UsageType:   "OnDemand",
+			Contains:    instance.Description.Contains,
+			Omits:       instance.Description.Omits,
+			Rejected:    []RejectedSKU{{Description: custom, Reason: "description filters"}},
+		}
+	}
+	expected := []ComponentMatch{
+		{"test", "CPU", "after", match("N2 Instance Core running in us-central1", "N2 Custom Instance Core running in us-central1")},
+		{"test", "RAM", "after", match("N2 Instance Ram running in us-central1", "N2 Custom Instance Ram running in us-central1")},
+	}
+	// The extended memory SKU is rejected as well.
+	expected[1].Match.Rejected = append(expected[1].Match.Rejected,
+		RejectedSKU{Description: "N2 Custom Instance Extended Ram running in us-central1", Reason: "description filters"})
+
+	if actual := state.Explain(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("state.Explain() = %+v; want %+v", actual, expected)
+	}
+}
@@ -13,27 +13,59 @@ type PricingInfo struct {
 	UsageUnit       string
 	HourlyUnitPrice float64
 	CurrencyType    string
+	// SKU records the SKU match in explain mode.
+	SKU       *SKUMatch
+	tierStart float64
 }
 
 func (p *PricingInfo) fillHourlyBase(sku *billingpb.Sku, correctTieredRate func(*billingpb.PricingExpression_TierRate) bool) {
 	p.UsageUnit, p.HourlyUnitPrice, p.CurrencyType = billing.PricingInfo(sku, correctTieredRate)
+	p.fillTier(sku, correctTieredRate)
 }
 
-func (p *PricingInfo) fillMonthlyBase(sku *billingpb.Sku, correctTieredRate func(*billingpb.PricingExpression_TierRate) bool) {
+// fillMonthlyBase fills the pricing of an SKU charged per month, spread over a month of the given number of hours.
+func (p *PricingInfo) fillMonthlyBase(sku *billingpb.Sku, correctTieredRate func(*billingpb.PricingExpression_TierRate) bool,
+	monthHours float64) {
 	usageUnit, monthly, currencyType := billing.PricingInfo(sku, correctTieredRate)
 	p.UsageUnit = usageUnit
-	p.HourlyUnitPrice = monthly / hourlyToMonthly
+	p.HourlyUnitPrice = monthly / monthHours
 	p.CurrencyType = currencyType
+	p.fillTier(sku, correctTieredRate)
 }
 
-//ResourceState is the interface of a general before/after resource state(ComputeInstance,...).
+func (p *PricingInfo) fillTier(sku *billingpb.Sku, correctTieredRate func(*billingpb.PricingExpression_TierRate) bool) {
+	if tr := billing.PricingTier(sku, correctTieredRate); tr != nil {
+		p.tierStart = tr.StartUsageAmount
+	}
+}
+
+// fillMonthlyGraduated fills the pricing of the given monthly amount of usage of an SKU whose tiers are graduated
+// (e.g. storage and egress), at the average rate of the tiers the amount spans, spread over a month of the given
+// number of hours.
+func (p *PricingInfo) fillMonthlyGraduated(sku *billingpb.Sku, amount, monthHours float64) {
+	usageUnit, monthly, currencyType := billing.GraduatedPricingInfo(sku, amount)
+	p.UsageUnit = usageUnit
+	p.HourlyUnitPrice = monthly / monthHours
+	p.CurrencyType = currencyType
+	p.fillTier(sku, func(tr *billingpb.PricingExpression_TierRate) bool {
+		return tr.StartUsageAmount <= amount
+	})
+}
+
+// ResourceState is the interface of a general before/after resource state(ComputeInstance,...).
 type ResourceState interface {
-	CompletePricingInfo(catalog *billing.ComputeEngineCatalog) error
+	CompletePricingInfo(catalog *billing.Catalog) error
 	GetDelta() float64
-	GetWebTables(stateNum int) *web.PricingTypeTables
-	ToTable() (*table.Table, error)
-	GetSummaryRow() (table.Row, error)
-	ToStateOut() (js.JSONOut, error)
+	GetWebTables(stateNum int, month Period) (*web.PricingTypeTables, error)
+	ToTable(p Period) (*table.Table, error)
+	GetSummaryRow(p Period) (table.Row, error)
+	ToStateOut(p Period) (js.JSONOut, error)
+}
+
+// CurrencyReporter is implemented by the resource states reporting the currency of their pricing.
+// States priced in different currencies can't be added up in the same total.
+type CurrencyReporter interface {
+	Currency() (string, error)
 }
 
 // skuObject is the interface for CPU cores and RAM SKUs from the billing catalog.
@@ -0,0 +1,310 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	billing "github.com/googleinterns/terraform-cost-estimation/billing"
+	"github.com/googleinterns/terraform-cost-estimation/io/js"
+	"github.com/googleinterns/terraform-cost-estimation/io/web"
+	conv "github.com/googleinterns/terraform-cost-estimation/memconverter"
+	"github.com/googleinterns/terraform-cost-estimation/usage"
+	"github.com/jedib0t/go-pretty/v6/table"
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+// minChargeRules is the number of forwarding rules covered by the minimum service charge.
+const minChargeRules = 5
+
+// ForwardingRule holds information about the google_compute_forwarding_rule and
+// google_compute_global_forwarding_rule resource types.
+// The processed data is a monthly amount taken from usage assumptions.
+type ForwardingRule struct {
+	Name         string
+	ID           string
+	Region       string
+	Global       bool
+	Scheme       string
+	ProcessedGiB float64
+}
+
+// NewForwardingRule builds a forwarding rule with the specified fields.
+// Usage values: data_processed_gib.
+func NewForwardingRule(name, id, region, scheme string, global bool, u usage.Values) (*ForwardingRule, error) {
+	if global {
+		region = "global"
+	} else if region == "" {
+		return nil, fmt.Errorf("region must be specified")
+	}
+	if scheme == "" {
+		scheme = "EXTERNAL"
+	}
+
+	gib := u.Value("data_processed_gib")
+	if gib < 0 {
+		return nil, fmt.Errorf("data_processed_gib can't be negative")
+	}
+	return &ForwardingRule{Name: name, ID: id, Region: region, Global: global, Scheme: scheme, ProcessedGiB: gib}, nil
+}
+
+// LoadBalancer groups the forwarding rules sending traffic to the same URL map, target proxy or backend service.
+// Target proxies are not charged, the forwarding rules are charged per hour (the minimum charge covers
+// the first five rules) and per GiB of processed data.
+// The minimum charge is shared by all the rules of a project in a region, so load balancers of the same
+// region are estimated independently as an upper bound.
+type LoadBalancer struct {
+	Name                  string
+	Region                string
+	Global                bool
+	Rules                 []*ForwardingRule
+	Proxies               []string
+	MinChargePricing      PricingInfo
+	AdditionalRulePricing PricingInfo
+	ProcessingPricing     PricingInfo
+}
+
+// NewLoadBalancer builds a load balancer from its forwarding rules and target proxies.
+// All the forwarding rules must be in the same region.
+func NewLoadBalancer(name string, rules []*ForwardingRule, proxies []string) (*LoadBalancer, error) {
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("load balancer has no forwarding rules")
+	}
+
+	lb := &LoadBalancer{Name: name, Region: rules[0].Region, Global: rules[0].Global, Rules: rules, Proxies: proxies}
+	for _, r := range rules[1:] {
+		if r.Region != lb.Region {
+			return nil, fmt.Errorf("forwarding rules of load balancer are in different regions")
+		}
+	}
+	return lb, nil
+}
+
+// ProcessedGiB returns the monthly data processed by all the forwarding rules.
+func (lb *LoadBalancer) ProcessedGiB() (gib float64) {
+	for _, r := range lb.Rules {
+		gib += r.ProcessedGiB
+	}
+	return
+}
+
+func (lb *LoadBalancer) description(contains string) Description {
+	d := Description{Contains: []string{contains}}
+	if lb.Global {
+		d.Contains = append(d.Contains, "Global")
+	} else {
+		d.Omits = append(d.Omits, "Global")
+	}
+	return d
+}
+
+// CompletePricingInfo fills the pricing information fields.
+func (lb *LoadBalancer) CompletePricingInfo(catalog *billing.Catalog) error {
+	c, err := catalog.Network()
+	if err != nil {
+		return err
+	}
+	allRates := func(*billingpb.PricingExpression_TierRate) bool { return true }
+
+	skus, err := c.LoadBalancingSKUs()
+	if err != nil {
+		return err
+	}
+
+	d := lb.description("Minimum Service Charge")
+	sku, err := firstSKU(skus, lb.Region, d)
+	if err != nil {
+		return err
+	}
+	lb.MinChargePricing.fillHourlyBase(sku, allRates)
+	lb.MinChargePricing.explain(catalog, sku, lb.Region, d, skus)
+
+	if len(lb.Rules) > minChargeRules {
+		d = lb.description("Additional Service Charge")
+		if sku, err = firstSKU(skus, lb.Region, d); err != nil {
+			return err
+		}
+		lb.AdditionalRulePricing.fillHourlyBase(sku, allRates)
+		lb.AdditionalRulePricing.explain(catalog, sku, lb.Region, d, skus)
+	}
+
+	if lb.ProcessingPricing, err = monthlyPricing(catalog, skus, lb.Region, lb.description("Data Processing"), lb.ProcessedGiB()); err != nil {
+		return err
+	}
+	if _, err := conv.Convert("gib", 0, lb.ProcessingPricing.UsageUnit); err != nil {
+		return fmt.Errorf("data processing unit of SKU is not supported")
+	}
+	return nil
+}
+
+// components returns the hourly pricing of the billing components of the load balancer.
+// Data processing is reported per forwarding rule.
+func (lb *LoadBalancer) components() []componentPricing {
+	if lb == nil {
+		return nil
+	}
+
+	additional := 0.0
+	if n := len(lb.Rules); n > minChargeRules {
+		additional = float64(n - minChargeRules)
+	}
+	c := []componentPricing{
+		{"Forwarding rules (first 5)", lb.MinChargePricing.HourlyUnitPrice, 1, lb.MinChargePricing},
+		{"Forwarding rules (additional)", lb.AdditionalRulePricing.HourlyUnitPrice, additional, lb.AdditionalRulePricing},
+	}
+
+	for _, r := range lb.Rules {
+		units, _ := conv.Convert("gib", r.ProcessedGiB, lb.ProcessingPricing.UsageUnit)
+		c = append(c, componentPricing{r.Name + ": Data processing", lb.ProcessingPricing.HourlyUnitPrice, units, lb.ProcessingPricing})
+	}
+	return c
+}
+
+func (lb *LoadBalancer) ruleNames() string {
+	var names []string
+	for _, r := range lb.Rules {
+		names = append(names, r.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// LoadBalancerState holds the before and after states of a load balancer and the action performed.
+type LoadBalancerState struct {
+	Before *LoadBalancer
+	After  *LoadBalancer
+	Action string
+	ResourceAddresses
+}
+
+// CompletePricingInfo completes pricing information of both before and after states.
+func (state *LoadBalancerState) CompletePricingInfo(catalog *billing.Catalog) error {
+	if state.Before != nil {
+		if err := state.Before.CompletePricingInfo(catalog); err != nil {
+			return fmt.Errorf("%s(%s): %w", state.Before.Name, state.Before.Region, err)
+		}
+	}
+
+	if state.After != nil {
+		if err := state.After.CompletePricingInfo(catalog); err != nil {
+			return fmt.Errorf("%s(%s): %w", state.After.Name, state.After.Region, err)
+		}
+	}
+	return nil
+}
+
+func (state *LoadBalancerState) components() []priceComponent {
+	return mergeComponents(state.Before.components(), state.After.components())
+}
+
+// GetDelta returns the hourly cost change of the load balancer.
+func (state *LoadBalancerState) GetDelta() float64 {
+	return componentsDelta(state.components())
+}
+
+func (state *LoadBalancerState) generalChanges() (name string, rows [][2]string) {
+	before, after, _ := syncLoadBalancers(state.Before, state.After)
+
+	name = generalChange(before.Name, after.Name)
+	rows = [][2]string{
+		{"Action", state.Action},
+		{"Region", generalChange(before.Region, after.Region)},
+		{"Forwarding rules", generalChange(before.ruleNames(), after.ruleNames())},
+		{"Target proxies", generalChange(strings.Join(before.Proxies, ", "), strings.Join(after.Proxies, ", "))},
+	}
+	return
+}
+
+// Explain returns the SKU matches of the billing components.
+func (state *LoadBalancerState) Explain() []ComponentMatch {
+	name, _ := state.generalChanges()
+	return componentsExplain(name, state.components())
+}
+
+// Currency returns the currency of the billing components.
+func (state *LoadBalancerState) Currency() (string, error) {
+	return componentsCurrency(state.components())
+}
+
+// report describes the load balancer for the machine-readable outputs.
+func (lb *LoadBalancer) report() *ResourceReport {
+	if lb == nil {
+		return nil
+	}
+
+	return &ResourceReport{
+		Name: lb.Name,
+		Attributes: map[string]interface{}{
+			"region":           lb.Region,
+			"forwarding_rules": lb.ruleNames(),
+			"target_proxies":   strings.Join(lb.Proxies, ","),
+		},
+		Components: componentCosts(lb.components()),
+	}
+}
+
+// Report describes the change of the load balancer for the machine-readable outputs.
+func (state *LoadBalancerState) Report() StateReport {
+	return StateReport{Kind: "load_balancer", Addresses: state.Addresses, Action: state.Action,
+		Before: state.Before.report(), After: state.After.report()}
+}
+
+// GetWebTables returns html pricing information table with hourly, monthly and yearly pricing.
+func (state *LoadBalancerState) GetWebTables(stateNum int, month Period) (*web.PricingTypeTables, error) {
+	name, rows := state.generalChanges()
+	return componentsWebTables(stateNum, name, rows, state.components(), month)
+}
+
+// ToTable creates a table.Table and fills it with the pricing information from LoadBalancerState.
+func (state *LoadBalancerState) ToTable(p Period) (*table.Table, error) {
+	if _, _, err := syncLoadBalancers(state.Before, state.After); err != nil {
+		return nil, err
+	}
+
+	name, rows := state.generalChanges()
+	return componentsTable(append([][2]string{{"Name", name}}, rows...), state.components(), p)
+}
+
+// GetSummaryRow returns the row for SummaryTable to be outputted about the certain state.
+func (state *LoadBalancerState) GetSummaryRow(p Period) (table.Row, error) {
+	_, r, err := syncLoadBalancers(state.Before, state.After)
+	if err != nil {
+		return table.Row{}, err
+	}
+	return table.Row{r.Name, "", "Load balancer", state.Action, fmt.Sprintf("%.6f", p.Cost(state.GetDelta()))}, nil
+}
+
+// ToStateOut creates LoadBalancerStateOut from state struct to render output in json format.
+func (state *LoadBalancerState) ToStateOut(p Period) (js.JSONOut, error) {
+	before, after, err := syncLoadBalancers(state.Before, state.After)
+	if err != nil {
+		return nil, err
+	}
+
+	components := scaleComponents(state.components(), p)
+	out := &js.LoadBalancerStateOut{
+		Name:            js.Change{Before: before.Name, After: after.Name},
+		Region:          js.Change{Before: before.Region, After: after.Region},
+		ForwardingRules: js.Change{Before: before.ruleNames(), After: after.ruleNames()},
+		Proxies:         js.Change{Before: strings.Join(before.Proxies, ", "), After: strings.Join(after.Proxies, ", ")},
+		Action:          state.Action,
+		Pricing: js.ComponentsStatePricing{
+			Before: componentsOut(components, state.Before != nil, false),
+			After:  componentsOut(components, state.After != nil, true),
+			Delta:  componentsDelta(components),
+		},
+	}
+	return out, nil
+}
+
+// syncLoadBalancers replace nils in state's before and after to be able to use them.
+func syncLoadBalancers(before, after *LoadBalancer) (*LoadBalancer, *LoadBalancer, error) {
+	if after == nil && before == nil {
+		return nil, nil, fmt.Errorf("After and Before can't be nil at the same time.")
+	}
+	if after == nil {
+		return before, before, nil
+	}
+	if before == nil {
+		return after, after, nil
+	}
+	return before, after, nil
+}
@@ -0,0 +1,69 @@
+package resources
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestNewLoadBalancer(t *testing.T) {
+	central := &ForwardingRule{Name: "a", Region: "us-central1"}
+	east := &ForwardingRule{Name: "b", Region: "us-east1"}
+
+	tests := []struct {
+		name  string
+		rules []*ForwardingRule
+		lb    *LoadBalancer
+		err   error
+	}{
+		{"no_rules", nil, nil, fmt.Errorf("load balancer has no forwarding rules")},
+		{"different_regions", []*ForwardingRule{central, east}, nil,
+			fmt.Errorf("forwarding rules of load balancer are in different regions")},
+		{"one_region", []*ForwardingRule{central, central},
+			&LoadBalancer{Name: "lb", Region: "us-central1", Rules: []*ForwardingRule{central, central}}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lb, err := NewLoadBalancer("lb", test.rules, nil)
+			if !reflect.DeepEqual(err, test.err) || !reflect.DeepEqual(lb, test.lb) {
+				t.Errorf("NewLoadBalancer(%+v) = %+v, %+v; want %+v, %+v", test.rules, lb, err, test.lb, test.err)
+			}
+		})
+	}
+}
+
+func TestLoadBalancerStateGetDelta(t *testing.T) {
+	newLB := func(rules int, gib float64) *LoadBalancer {
+		lb := &LoadBalancer{
+			MinChargePricing:      PricingInfo{UsageUnit: "h", HourlyUnitPrice: 0.025},
+			AdditionalRulePricing: PricingInfo{UsageUnit: "h", HourlyUnitPrice: 0.01},
+			ProcessingPricing:     PricingInfo{UsageUnit: "gibibyte", HourlyUnitPrice: 0.008 / hourlyToMonthly},
+		}
+		for i := 0; i < rules; i++ {
+			lb.Rules = append(lb.Rules, &ForwardingRule{Name: fmt.Sprintf("rule-%d", i), ProcessedGiB: gib})
+		}
+		return lb
+	}
+	processing := func(gib float64) float64 { return gib * 0.008 / hourlyToMonthly }
+
+	tests := []struct {
+		name  string
+		state LoadBalancerState
+		delta float64
+	}{
+		{"create_one_rule", LoadBalancerState{After: newLB(1, 100)}, 0.025 + processing(100)},
+		{"create_seven_rules", LoadBalancerState{After: newLB(7, 10)}, 0.025 + 2*0.01 + processing(70)},
+		{"add_rules", LoadBalancerState{Before: newLB(2, 0), After: newLB(6, 0)}, 0.01},
+		{"destroy", LoadBalancerState{Before: newLB(1, 0)}, -0.025},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if d := test.state.GetDelta(); math.Abs(d-test.delta) > epsilon {
+				t.Errorf("%+v.GetDelta() = %f; want %f", test.state, d, test.delta)
+			}
+		})
+	}
+}
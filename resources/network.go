@@ -0,0 +1,610 @@
+package resources
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	billing "github.com/googleinterns/terraform-cost-estimation/billing"
+	"github.com/googleinterns/terraform-cost-estimation/io/js"
+	"github.com/googleinterns/terraform-cost-estimation/io/web"
+	conv "github.com/googleinterns/terraform-cost-estimation/memconverter"
+	"github.com/googleinterns/terraform-cost-estimation/usage"
+	"github.com/jedib0t/go-pretty/v6/table"
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+// natMaxBilledVMs is the number of VM instances after which the Cloud NAT gateway uptime charge stops growing.
+const natMaxBilledVMs = 32
+
+// egressClass is a destination class of network egress, identified in the billing catalog
+// by the resource group and the destination part of the SKU description.
+type egressClass struct {
+	group       string
+	destination string
+}
+
+// networkEgressClasses maps the egress destination classes used in usage assumptions to their SKUs.
+// Internet egress uses the resource group of the network tier (Premium or Standard).
+var networkEgressClasses = map[string]egressClass{
+	"internet_americas":     {"InternetEgress", "to Americas"},
+	"internet_emea":         {"InternetEgress", "to EMEA"},
+	"internet_apac":         {"InternetEgress", "to APAC"},
+	"internet_china":        {"InternetEgress", "to China"},
+	"internet_australia":    {"InternetEgress", "to Australia"},
+	"inter_region_americas": {"InterregionEgress", "to Americas"},
+	"inter_region_emea":     {"InterregionEgress", "to EMEA"},
+	"inter_region_apac":     {"InterregionEgress", "to APAC"},
+	"inter_zone":            {"InterzoneEgress", "Inter Zone"},
+}
+
+// NetworkEgress holds the monthly egress of a resource by destination class and its pricing.
+type NetworkEgress struct {
+	GiB     map[string]float64
+	Pricing map[string]PricingInfo
+}
+
+// newNetworkEgress reads the egress_gib.<class> usage values.
+func newNetworkEgress(u usage.Values) (NetworkEgress, error) {
+	e := NetworkEgress{GiB: u.Group("egress_gib")}
+	for class := range e.GiB {
+		if _, ok := networkEgressClasses[class]; !ok {
+			return e, fmt.Errorf("invalid egress destination class '" + class + "'")
+		}
+	}
+	return e, nil
+}
+
+func (e *NetworkEgress) classes() []string {
+	var classes []string
+	for c := range e.GiB {
+		classes = append(classes, c)
+	}
+	sort.Strings(classes)
+	return classes
+}
+
+// internetGiB returns the egress to the internet, which is the traffic going through a NAT gateway.
+func (e *NetworkEgress) internetGiB() (gib float64) {
+	for c, x := range e.GiB {
+		if strings.HasPrefix(c, "internet_") {
+			gib += x
+		}
+	}
+	return
+}
+
+func (e *NetworkEgress) completePricingInfo(catalog *billing.Catalog, region, networkTier string) error {
+	c, err := catalog.Network()
+	if err != nil {
+		return err
+	}
+
+	e.Pricing = map[string]PricingInfo{}
+	for _, class := range e.classes() {
+		ec := networkEgressClasses[class]
+		group := ec.group
+		if group == "InternetEgress" {
+			group = networkTierPrefix(networkTier) + group
+		}
+
+		skus, err := c.EgressSKUs(group)
+		if err != nil {
+			return err
+		}
+
+		p, err := monthlyPricing(catalog, skus, region, Description{Contains: []string{ec.destination}}, e.GiB[class])
+		if err != nil {
+			return err
+		}
+		if _, err := conv.Convert("gib", 0, p.UsageUnit); err != nil {
+			return fmt.Errorf("egress unit of SKU is not supported")
+		}
+		e.Pricing[class] = p
+	}
+	return nil
+}
+
+func (e *NetworkEgress) components() []componentPricing {
+	var c []componentPricing
+	for _, class := range e.classes() {
+		p := e.Pricing[class]
+		units, _ := conv.Convert("gib", e.GiB[class], p.UsageUnit)
+		c = append(c, componentPricing{"Egress (" + class + ")", p.HourlyUnitPrice, units, p})
+	}
+	return c
+}
+
+func networkTierPrefix(networkTier string) string {
+	if networkTier == "STANDARD" {
+		return "Standard"
+	}
+	return "Premium"
+}
+
+// Address holds information about the google_compute_address and google_compute_global_address resource types.
+// Only external addresses are charged, at the unused (reserved) rate or at the in use rate for the
+// fraction of the month they are attached to a VM instance.
+type Address struct {
+	Name          string
+	ID            string
+	Address       string
+	Region        string
+	Global        bool
+	NetworkTier   string
+	InUse         float64
+	Egress        NetworkEgress
+	UnusedPricing PricingInfo
+	InUsePricing  PricingInfo
+}
+
+// NewAddress builds an external static IP address with the specified fields and fills the other resource details.
+// The address is considered in use if one of its users is a VM instance, the addresses of forwarding rules
+// not being charged while in use; the in_use usage value (from 0 to 1) overrides it.
+// Usage values egress_gib.<class> give the egress of the VM instance using the address.
+func NewAddress(name, id, address, region, networkTier string, global bool, users []string,
+	u usage.Values) (*Address, error) {
+
+	if global {
+		region = "global"
+	} else if region == "" {
+		return nil, fmt.Errorf("region must be specified")
+	}
+	if networkTier == "" {
+		networkTier = "PREMIUM"
+	}
+
+	a := &Address{Name: name, ID: id, Address: address, Region: region, Global: global, NetworkTier: networkTier}
+	for _, user := range users {
+		if strings.Contains(user, "/instances/") {
+			a.InUse = 1
+		}
+	}
+	if inUse, ok := u["in_use"]; ok {
+		if inUse < 0 || inUse > 1 {
+			return nil, fmt.Errorf("in_use must be between 0 and 1")
+		}
+		a.InUse = inUse
+	}
+
+	var err error
+	if a.Egress, err = newNetworkEgress(u); err != nil {
+		return nil, err
+	}
+	if global && len(a.Egress.GiB) > 0 {
+		return nil, fmt.Errorf("egress of global addresses is priced by their load balancers")
+	}
+	return a, nil
+}
+
+// CompletePricingInfo fills the pricing information fields.
+func (a *Address) CompletePricingInfo(catalog *billing.Catalog) error {
+	c, err := catalog.Network()
+	if err != nil {
+		return err
+	}
+	allRates := func(*billingpb.PricingExpression_TierRate) bool { return true }
+
+	skus, err := c.IPAddressSKUs()
+	if err != nil {
+		return err
+	}
+
+	d := Description{Contains: []string{"Static Ip Charge"}}
+	sku, err := firstSKU(skus, a.Region, d)
+	if err != nil {
+		return err
+	}
+	a.UnusedPricing.fillHourlyBase(sku, allRates)
+	a.UnusedPricing.explain(catalog, sku, a.Region, d, skus)
+
+	// Addresses of forwarding rules are not charged while in use.
+	if !a.Global && a.InUse > 0 {
+		d = Description{Contains: []string{"External IP Charge on a Standard VM"}}
+		if sku, err = firstSKU(skus, a.Region, d); err != nil {
+			return err
+		}
+		a.InUsePricing.fillHourlyBase(sku, allRates)
+		a.InUsePricing.explain(catalog, sku, a.Region, d, skus)
+	}
+
+	return a.Egress.completePricingInfo(catalog, a.Region, a.NetworkTier)
+}
+
+// components returns the hourly pricing of the billing components of the address.
+func (a *Address) components() []componentPricing {
+	if a == nil {
+		return nil
+	}
+
+	c := []componentPricing{{"Static IP (unused)", a.UnusedPricing.HourlyUnitPrice, 1 - a.InUse, a.UnusedPricing}}
+	if !a.Global {
+		c = append(c, componentPricing{"Static IP (in use)", a.InUsePricing.HourlyUnitPrice, a.InUse, a.InUsePricing})
+	}
+	return append(c, a.Egress.components()...)
+}
+
+// AddressState holds the before and after states of a static IP address and the action performed.
+type AddressState struct {
+	Before *Address
+	After  *Address
+	Action string
+	ResourceAddresses
+}
+
+// CompletePricingInfo completes pricing information of both before and after states.
+func (state *AddressState) CompletePricingInfo(catalog *billing.Catalog) error {
+	if state.Before != nil {
+		if err := state.Before.CompletePricingInfo(catalog); err != nil {
+			return fmt.Errorf("%s(%s): %w", state.Before.Name, state.Before.Region, err)
+		}
+	}
+
+	if state.After != nil {
+		if err := state.After.CompletePricingInfo(catalog); err != nil {
+			return fmt.Errorf("%s(%s): %w", state.After.Name, state.After.Region, err)
+		}
+	}
+	return nil
+}
+
+func (state *AddressState) components() []priceComponent {
+	return mergeComponents(state.Before.components(), state.After.components())
+}
+
+// GetDelta returns the hourly cost change of the address.
+func (state *AddressState) GetDelta() float64 {
+	return componentsDelta(state.components())
+}
+
+func (state *AddressState) generalChanges() (name string, rows [][2]string) {
+	before, after, _ := syncAddresses(state.Before, state.After)
+	id := before.ID
+	if id == "" {
+		id = after.ID
+	}
+
+	name = generalChange(before.Name, after.Name)
+	rows = [][2]string{
+		{"ID", id},
+		{"Action", state.Action},
+		{"Region", generalChange(before.Region, after.Region)},
+		{"Address", generalChange(before.Address, after.Address)},
+		{"Network tier", generalChange(before.NetworkTier, after.NetworkTier)},
+		{"In use", generalChange(fmt.Sprintf("%.0f%%", before.InUse*100), fmt.Sprintf("%.0f%%", after.InUse*100))},
+	}
+	return
+}
+
+// Explain returns the SKU matches of the billing components.
+func (state *AddressState) Explain() []ComponentMatch {
+	name, _ := state.generalChanges()
+	return componentsExplain(name, state.components())
+}
+
+// Currency returns the currency of the billing components.
+func (state *AddressState) Currency() (string, error) {
+	return componentsCurrency(state.components())
+}
+
+// report describes the compute address for the machine-readable outputs.
+func (a *Address) report() *ResourceReport {
+	if a == nil {
+		return nil
+	}
+
+	return &ResourceReport{
+		Name: a.Name,
+		Attributes: map[string]interface{}{
+			"id":           a.ID,
+			"region":       a.Region,
+			"address":      a.Address,
+			"network_tier": a.NetworkTier,
+			"in_use":       a.InUse,
+		},
+		Components: componentCosts(a.components()),
+	}
+}
+
+// Report describes the change of the compute address for the machine-readable outputs.
+func (state *AddressState) Report() StateReport {
+	return StateReport{Kind: "compute_address", Addresses: state.Addresses, Action: state.Action,
+		Before: state.Before.report(), After: state.After.report()}
+}
+
+// GetWebTables returns html pricing information table with hourly, monthly and yearly pricing.
+func (state *AddressState) GetWebTables(stateNum int, month Period) (*web.PricingTypeTables, error) {
+	name, rows := state.generalChanges()
+	return componentsWebTables(stateNum, name, rows, state.components(), month)
+}
+
+// ToTable creates a table.Table and fills it with the pricing information from AddressState.
+func (state *AddressState) ToTable(p Period) (*table.Table, error) {
+	if _, _, err := syncAddresses(state.Before, state.After); err != nil {
+		return nil, err
+	}
+
+	name, rows := state.generalChanges()
+	return componentsTable(append([][2]string{{"Name", name}}, rows...), state.components(), p)
+}
+
+// GetSummaryRow returns the row for SummaryTable to be outputted about the certain state.
+func (state *AddressState) GetSummaryRow(p Period) (table.Row, error) {
+	_, r, err := syncAddresses(state.Before, state.After)
+	if err != nil {
+		return table.Row{}, err
+	}
+	return table.Row{r.Name, r.ID, "Static IP", state.Action, fmt.Sprintf("%.6f", p.Cost(state.GetDelta()))}, nil
+}
+
+// ToStateOut creates AddressStateOut from state struct to render output in json format.
+func (state *AddressState) ToStateOut(p Period) (js.JSONOut, error) {
+	before, after, err := syncAddresses(state.Before, state.After)
+	if err != nil {
+		return nil, err
+	}
+
+	components := scaleComponents(state.components(), p)
+	out := &js.AddressStateOut{
+		Name:        js.Change{Before: before.Name, After: after.Name},
+		ID:          js.Change{Before: before.ID, After: after.ID},
+		Region:      js.Change{Before: before.Region, After: after.Region},
+		Address:     js.Change{Before: before.Address, After: after.Address},
+		NetworkTier: js.Change{Before: before.NetworkTier, After: after.NetworkTier},
+		InUse:       js.Change{Before: fmt.Sprintf("%.2f", before.InUse), After: fmt.Sprintf("%.2f", after.InUse)},
+		Action:      state.Action,
+		Pricing: js.ComponentsStatePricing{
+			Before: componentsOut(components, state.Before != nil, false),
+			After:  componentsOut(components, state.After != nil, true),
+			Delta:  componentsDelta(components),
+		},
+	}
+	return out, nil
+}
+
+// syncAddresses replace nils in state's before and after to be able to use them.
+func syncAddresses(before, after *Address) (*Address, *Address, error) {
+	if after == nil && before == nil {
+		return nil, nil, fmt.Errorf("After and Before can't be nil at the same time.")
+	}
+	if after == nil {
+		return before, before, nil
+	}
+	if before == nil {
+		return after, after, nil
+	}
+	return before, after, nil
+}
+
+// RouterNAT holds information about the google_compute_router_nat resource type.
+// The gateway uptime is charged per VM instance using it (up to 32 instances) and
+// the processed data is charged per GiB.
+type RouterNAT struct {
+	Name              string
+	Router            string
+	Region            string
+	VMs               float64
+	ProcessedGiB      float64
+	Egress            NetworkEgress
+	GatewayPricing    PricingInfo
+	ProcessingPricing PricingInfo
+}
+
+// NewRouterNAT builds a Cloud NAT gateway with the specified fields and fills the other resource details.
+// Usage values: vm_count (defaults to 1), egress_gib.<class> and data_processed_gib, which defaults to the
+// internet egress going through the gateway.
+func NewRouterNAT(name, router, region string, u usage.Values) (*RouterNAT, error) {
+	if region == "" {
+		return nil, fmt.Errorf("region must be specified")
+	}
+
+	nat := &RouterNAT{Name: name, Router: router, Region: region, VMs: 1}
+	if vms, ok := u["vm_count"]; ok {
+		nat.VMs = vms
+	}
+	if nat.VMs < 0 {
+		return nil, fmt.Errorf("vm_count can't be negative")
+	}
+
+	var err error
+	if nat.Egress, err = newNetworkEgress(u); err != nil {
+		return nil, err
+	}
+
+	nat.ProcessedGiB = nat.Egress.internetGiB()
+	if gib, ok := u["data_processed_gib"]; ok {
+		nat.ProcessedGiB = gib
+	}
+	return nat, nil
+}
+
+// CompletePricingInfo fills the pricing information fields.
+func (nat *RouterNAT) CompletePricingInfo(catalog *billing.Catalog) error {
+	c, err := catalog.Network()
+	if err != nil {
+		return err
+	}
+
+	skus, err := c.NATSKUs()
+	if err != nil {
+		return err
+	}
+
+	d := Description{Contains: []string{"Gateway Uptime"}}
+	sku, err := firstSKU(skus, nat.Region, d)
+	if err != nil {
+		return err
+	}
+	nat.GatewayPricing.fillHourlyBase(sku, func(*billingpb.PricingExpression_TierRate) bool { return true })
+	nat.GatewayPricing.explain(catalog, sku, nat.Region, d, skus)
+
+	d = Description{Contains: []string{"Data Processing"}}
+	if nat.ProcessingPricing, err = monthlyPricing(catalog, skus, nat.Region, d, nat.ProcessedGiB); err != nil {
+		return err
+	}
+	if _, err := conv.Convert("gib", 0, nat.ProcessingPricing.UsageUnit); err != nil {
+		return fmt.Errorf("data processing unit of SKU is not supported")
+	}
+
+	return nat.Egress.completePricingInfo(catalog, nat.Region, "PREMIUM")
+}
+
+// components returns the hourly pricing of the billing components of the NAT gateway.
+func (nat *RouterNAT) components() []componentPricing {
+	if nat == nil {
+		return nil
+	}
+
+	vms := nat.VMs
+	if vms > natMaxBilledVMs {
+		vms = natMaxBilledVMs
+	}
+	processed, _ := conv.Convert("gib", nat.ProcessedGiB, nat.ProcessingPricing.UsageUnit)
+
+	c := []componentPricing{
+		{"Gateway uptime", nat.GatewayPricing.HourlyUnitPrice, vms, nat.GatewayPricing},
+		{"Data processing", nat.ProcessingPricing.HourlyUnitPrice, processed, nat.ProcessingPricing},
+	}
+	return append(c, nat.Egress.components()...)
+}
+
+// RouterNATState holds the before and after states of a Cloud NAT gateway and the action performed.
+type RouterNATState struct {
+	Before *RouterNAT
+	After  *RouterNAT
+	Action string
+	ResourceAddresses
+}
+
+// CompletePricingInfo completes pricing information of both before and after states.
+func (state *RouterNATState) CompletePricingInfo(catalog *billing.Catalog) error {
+	if state.Before != nil {
+		if err := state.Before.CompletePricingInfo(catalog); err != nil {
+			return fmt.Errorf("%s(%s): %w", state.Before.Name, state.Before.Router, err)
+		}
+	}
+
+	if state.After != nil {
+		if err := state.After.CompletePricingInfo(catalog); err != nil {
+			return fmt.Errorf("%s(%s): %w", state.After.Name, state.After.Router, err)
+		}
+	}
+	return nil
+}
+
+func (state *RouterNATState) components() []priceComponent {
+	return mergeComponents(state.Before.components(), state.After.components())
+}
+
+// GetDelta returns the hourly cost change of the NAT gateway.
+func (state *RouterNATState) GetDelta() float64 {
+	return componentsDelta(state.components())
+}
+
+func (state *RouterNATState) generalChanges() (name string, rows [][2]string) {
+	before, after, _ := syncRouterNATs(state.Before, state.After)
+
+	name = generalChange(before.Name, after.Name)
+	rows = [][2]string{
+		{"Action", state.Action},
+		{"Router", generalChange(before.Router, after.Router)},
+		{"Region", generalChange(before.Region, after.Region)},
+		{"VM instances", generalChange(fmt.Sprintf("%g", before.VMs), fmt.Sprintf("%g", after.VMs))},
+	}
+	return
+}
+
+// Explain returns the SKU matches of the billing components.
+func (state *RouterNATState) Explain() []ComponentMatch {
+	name, _ := state.generalChanges()
+	return componentsExplain(name, state.components())
+}
+
+// Currency returns the currency of the billing components.
+func (state *RouterNATState) Currency() (string, error) {
+	return componentsCurrency(state.components())
+}
+
+// report describes the router nat for the machine-readable outputs.
+func (nat *RouterNAT) report() *ResourceReport {
+	if nat == nil {
+		return nil
+	}
+
+	return &ResourceReport{
+		Name: nat.Name,
+		Attributes: map[string]interface{}{
+			"router":       nat.Router,
+			"region":       nat.Region,
+			"vm_instances": nat.VMs,
+		},
+		Components: componentCosts(nat.components()),
+	}
+}
+
+// Report describes the change of the router nat for the machine-readable outputs.
+func (state *RouterNATState) Report() StateReport {
+	return StateReport{Kind: "router_nat", Addresses: state.Addresses, Action: state.Action,
+		Before: state.Before.report(), After: state.After.report()}
+}
+
+// GetWebTables returns html pricing information table with hourly, monthly and yearly pricing.
+func (state *RouterNATState) GetWebTables(stateNum int, month Period) (*web.PricingTypeTables, error) {
+	name, rows := state.generalChanges()
+	return componentsWebTables(stateNum, name, rows, state.components(), month)
+}
+
+// ToTable creates a table.Table and fills it with the pricing information from RouterNATState.
+func (state *RouterNATState) ToTable(p Period) (*table.Table, error) {
+	if _, _, err := syncRouterNATs(state.Before, state.After); err != nil {
+		return nil, err
+	}
+
+	name, rows := state.generalChanges()
+	return componentsTable(append([][2]string{{"Name", name}}, rows...), state.components(), p)
+}
+
+// GetSummaryRow returns the row for SummaryTable to be outputted about the certain state.
+func (state *RouterNATState) GetSummaryRow(p Period) (table.Row, error) {
+	_, r, err := syncRouterNATs(state.Before, state.After)
+	if err != nil {
+		return table.Row{}, err
+	}
+	return table.Row{r.Name, r.Router, "Cloud NAT", state.Action, fmt.Sprintf("%.6f", p.Cost(state.GetDelta()))}, nil
+}
+
+// ToStateOut creates RouterNATStateOut from state struct to render output in json format.
+func (state *RouterNATState) ToStateOut(p Period) (js.JSONOut, error) {
+	before, after, err := syncRouterNATs(state.Before, state.After)
+	if err != nil {
+		return nil, err
+	}
+
+	components := scaleComponents(state.components(), p)
+	out := &js.RouterNATStateOut{
+		Name:   js.Change{Before: before.Name, After: after.Name},
+		Router: js.Change{Before: before.Router, After: after.Router},
+		Region: js.Change{Before: before.Region, After: after.Region},
+		Action: state.Action,
+		Pricing: js.ComponentsStatePricing{
+			Before: componentsOut(components, state.Before != nil, false),
+			After:  componentsOut(components, state.After != nil, true),
+			Delta:  componentsDelta(components),
+		},
+	}
+	return out, nil
+}
+
+// syncRouterNATs replace nils in state's before and after to be able to use them.
+func syncRouterNATs(before, after *RouterNAT) (*RouterNAT, *RouterNAT, error) {
+	if after == nil && before == nil {
+		return nil, nil, fmt.Errorf("After and Before can't be nil at the same time.")
+	}
+	if after == nil {
+		return before, before, nil
+	}
+	if before == nil {
+		return after, after, nil
+	}
+	return before, after, nil
+}
@@ -0,0 +1,115 @@
+package resources
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/googleinterns/terraform-cost-estimation/usage"
+)
+
+func TestNewAddress(t *testing.T) {
+	const (
+		instanceUser = "https://www.googleapis.com/compute/v1/projects/p/zones/us-central1-a/instances/vm"
+		ruleUser     = "https://www.googleapis.com/compute/v1/projects/p/regions/us-central1/forwardingRules/lb"
+	)
+	tests := []struct {
+		name    string
+		region  string
+		global  bool
+		users   []string
+		usage   usage.Values
+		address *Address
+		err     error
+	}{
+		{"no_region", "", false, nil, nil, nil, fmt.Errorf("region must be specified")},
+		{"wrong_in_use", "us-central1", false, nil, usage.Values{"in_use": 2}, nil,
+			fmt.Errorf("in_use must be between 0 and 1")},
+		{"wrong_egress", "us-central1", false, nil, usage.Values{"egress_gib.mars": 1}, nil,
+			fmt.Errorf("invalid egress destination class 'mars'")},
+		{"global_egress", "", true, nil, usage.Values{"egress_gib.inter_zone": 1}, nil,
+			fmt.Errorf("egress of global addresses is priced by their load balancers")},
+		{"unused", "us-central1", false, nil, nil,
+			&Address{Region: "us-central1", NetworkTier: "PREMIUM", Egress: NetworkEgress{GiB: map[string]float64{}}}, nil},
+		{"users", "us-central1", false, []string{instanceUser}, nil,
+			&Address{Region: "us-central1", NetworkTier: "PREMIUM", InUse: 1,
+				Egress: NetworkEgress{GiB: map[string]float64{}}}, nil},
+		{"forwarding_rule_user", "us-central1", false, []string{ruleUser}, nil,
+			&Address{Region: "us-central1", NetworkTier: "PREMIUM", Egress: NetworkEgress{GiB: map[string]float64{}}}, nil},
+		{"usage_override", "us-central1", false, []string{instanceUser}, usage.Values{"in_use": 0.5, "egress_gib.inter_zone": 3},
+			&Address{Region: "us-central1", NetworkTier: "PREMIUM", InUse: 0.5,
+				Egress: NetworkEgress{GiB: map[string]float64{"inter_zone": 3}}}, nil},
+		{"global", "", true, nil, nil,
+			&Address{Region: "global", Global: true, NetworkTier: "PREMIUM", Egress: NetworkEgress{GiB: map[string]float64{}}}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			address, err := NewAddress("", "", "", test.region, "", test.global, test.users, test.usage)
+			if !reflect.DeepEqual(err, test.err) || !reflect.DeepEqual(address, test.address) {
+				t.Errorf("NewAddress(%s, %t, %+v, %+v) = %+v, %+v; want %+v, %+v", test.region, test.global, test.users,
+					test.usage, address, err, test.address, test.err)
+			}
+		})
+	}
+}
+
+func TestNewRouterNAT(t *testing.T) {
+	tests := []struct {
+		name  string
+		usage usage.Values
+		nat   *RouterNAT
+		err   error
+	}{
+		{"negative_vms", usage.Values{"vm_count": -1}, nil, fmt.Errorf("vm_count can't be negative")},
+		{"defaults", nil, &RouterNAT{Region: "us-central1", VMs: 1, Egress: NetworkEgress{GiB: map[string]float64{}}}, nil},
+		{"processed_from_egress", usage.Values{"egress_gib.internet_apac": 20, "egress_gib.inter_zone": 5},
+			&RouterNAT{Region: "us-central1", VMs: 1, ProcessedGiB: 20,
+				Egress: NetworkEgress{GiB: map[string]float64{"internet_apac": 20, "inter_zone": 5}}}, nil},
+		{"processed_override", usage.Values{"vm_count": 40, "data_processed_gib": 100, "egress_gib.internet_apac": 20},
+			&RouterNAT{Region: "us-central1", VMs: 40, ProcessedGiB: 100,
+				Egress: NetworkEgress{GiB: map[string]float64{"internet_apac": 20}}}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nat, err := NewRouterNAT("", "", "us-central1", test.usage)
+			if !reflect.DeepEqual(err, test.err) || !reflect.DeepEqual(nat, test.nat) {
+				t.Errorf("NewRouterNAT(%+v) = %+v, %+v; want %+v, %+v", test.usage, nat, err, test.nat, test.err)
+			}
+		})
+	}
+}
+
+func TestNetworkStatesGetDelta(t *testing.T) {
+	gib := func(price float64) PricingInfo { return PricingInfo{UsageUnit: "gibibyte", HourlyUnitPrice: price} }
+	hourly := func(price float64) PricingInfo { return PricingInfo{UsageUnit: "h", HourlyUnitPrice: price} }
+
+	egress := NetworkEgress{GiB: map[string]float64{"internet_emea": 100},
+		Pricing: map[string]PricingInfo{"internet_emea": gib(0.12 / hourlyToMonthly)}}
+	unused := &Address{UnusedPricing: hourly(0.01), InUsePricing: hourly(0.004)}
+	used := &Address{InUse: 1, UnusedPricing: hourly(0.01), InUsePricing: hourly(0.004), Egress: egress}
+	nat := &RouterNAT{VMs: 40, ProcessedGiB: 100, GatewayPricing: hourly(0.0014),
+		ProcessingPricing: gib(0.045 / hourlyToMonthly), Egress: egress}
+
+	egressCost := 100 * 0.12 / hourlyToMonthly
+	tests := []struct {
+		name  string
+		state ResourceState
+		delta float64
+	}{
+		{"create_unused_address", &AddressState{After: unused}, 0.01},
+		{"attach_address", &AddressState{Before: unused, After: used}, 0.004 + egressCost - 0.01},
+		{"create_nat", &RouterNATState{After: nat}, natMaxBilledVMs*0.0014 + 100*0.045/hourlyToMonthly + egressCost},
+		{"destroy_nat", &RouterNATState{Before: nat}, -natMaxBilledVMs*0.0014 - 100*0.045/hourlyToMonthly - egressCost},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if d := test.state.GetDelta(); math.Abs(d-test.delta) > epsilon {
+				t.Errorf("%+v.GetDelta() = %f; want %f", test.state, d, test.delta)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package resources
+
+import (
+	"fmt"
+)
+
+// Month conventions: the average month of 730 hours (365 days * 24 hours / 12 months) or a month of 30 days.
+const (
+	Month730h = "730h"
+	Month30d  = "30d"
+)
+
+// Period is a reporting period of the costs, which are estimated hourly and multiplied by its number of hours.
+type Period struct {
+	Name  string
+	Hours float64
+}
+
+var (
+	// Hour is the period the costs are estimated in.
+	Hour = Period{Name: "hour", Hours: 1}
+	// Year is a period of 365 days.
+	Year = Period{Name: "year", Hours: hourlyToYearly}
+)
+
+// Month returns the month period of the given convention (730h or 30d).
+func Month(convention string) (Period, error) {
+	switch convention {
+	case Month730h:
+		return Period{Name: "month", Hours: 730}, nil
+	case Month30d:
+		return Period{Name: "month", Hours: hourlyToMonthly}, nil
+	default:
+		return Period{}, fmt.Errorf("unknown month convention %q, must be 730h or 30d", convention)
+	}
+}
+
+// NewPeriod returns the reporting period with the given name (hour, month or year).
+// The month convention is used only for the monthly period.
+func NewPeriod(name, monthConvention string) (Period, error) {
+	switch name {
+	case Hour.Name:
+		return Hour, nil
+	case "month":
+		return Month(monthConvention)
+	case Year.Name:
+		return Year, nil
+	default:
+		return Period{}, fmt.Errorf("unknown reporting period %q, must be hour, month or year", name)
+	}
+}
+
+// Unit returns the unit of the costs in the given currency over the period (e.g. USD/month).
+func (p Period) Unit(currency string) string {
+	return currency + "/" + p.Name
+}
+
+// Cost returns the cost over the period of the given hourly cost.
+func (p Period) Cost(hourly float64) float64 {
+	return hourly * p.Hours
+}
@@ -0,0 +1,104 @@
+package resources
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	billing "github.com/googleinterns/terraform-cost-estimation/billing"
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+	"google.golang.org/genproto/googleapis/type/money"
+)
+
+func TestNewPeriod(t *testing.T) {
+	tests := []struct {
+		name            string
+		period          string
+		monthConvention string
+		expected        Period
+		ok              bool
+	}{
+		{"hour", "hour", "", Period{"hour", 1}, true},
+		{"month_730h", "month", Month730h, Period{"month", 730}, true},
+		{"month_30d", "month", Month30d, Period{"month", 720}, true},
+		{"year", "year", Month730h, Period{"year", 8760}, true},
+		{"unknown_month", "month", "4w", Period{}, false},
+		{"unknown_period", "week", Month730h, Period{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := NewPeriod(test.period, test.monthConvention)
+			if (err == nil) != test.ok {
+				t.Fatalf("NewPeriod(%q, %q) got error %v, want ok %v", test.period, test.monthConvention, err, test.ok)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("NewPeriod(%q, %q) = %+v, want %+v", test.period, test.monthConvention, actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestScaleComponents(t *testing.T) {
+	month, _ := Month(Month730h)
+	components := mergeComponents(
+		[]componentPricing{{name: "CPU", unitCost: 0.02, units: 2}, {name: "RAM", unitCost: 0.003, units: 8}},
+		[]componentPricing{{name: "CPU", unitCost: 0.02, units: 4}},
+	)
+
+	tests := []struct {
+		name  string
+		p     Period
+		delta float64
+	}{
+		{"hour", Hour, 0.04 - 0.024},
+		{"month", month, (0.04 - 0.024) * 730},
+		{"year", Year, (0.04 - 0.024) * 8760},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scaled := scaleComponents(components, test.p)
+			if d := componentsDelta(scaled); d-test.delta > epsilon || test.delta-d > epsilon {
+				t.Errorf("componentsDelta(scaleComponents(%s)) = %f, want %f", test.p.Name, d, test.delta)
+			}
+			if scaled[0].units1 != 2 || scaled[0].units2 != 4 {
+				t.Errorf("scaleComponents(%s) changed the number of units", test.p.Name)
+			}
+		})
+	}
+}
+
+func TestMonthlyPriceConventions(t *testing.T) {
+	// Standard persistent disk: 0.04 per GiB month.
+	sku := &billingpb.Sku{
+		Description:    "Storage PD Capacity",
+		ServiceRegions: []string{"us-central1"},
+		PricingInfo: []*billingpb.PricingInfo{{PricingExpression: &billingpb.PricingExpression{
+			UsageUnitDescription: "gibibyte month",
+			TieredRates:          []*billingpb.PricingExpression_TierRate{{UnitPrice: &money.Money{CurrencyCode: "USD", Nanos: 40000000}}},
+		}}},
+	}
+	allRates := func(*billingpb.PricingExpression_TierRate) bool { return true }
+
+	for _, convention := range []string{Month730h, Month30d} {
+		t.Run(convention, func(t *testing.T) {
+			month, _ := Month(convention)
+			catalog := billing.NewCatalogFromSKUs(nil)
+			catalog.SetMonthHours(month.Hours)
+
+			base := PricingInfo{}
+			base.fillMonthlyBase(sku, allRates, catalog.MonthHours())
+			usage, err := monthlyPricing(catalog, []*billingpb.Sku{sku}, "us-central1", Description{}, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if monthly := month.Cost(base.HourlyUnitPrice); math.Abs(monthly-0.04) > epsilon {
+				t.Errorf("fillMonthlyBase() = %f USD/month, want 0.04", monthly)
+			}
+			if monthly := month.Cost(usage.HourlyUnitPrice); math.Abs(monthly-0.04) > epsilon {
+				t.Errorf("monthlyPricing() = %f USD/month, want 0.04", monthly)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package resources
+
+// ComponentCost is the hourly cost of a billing component (CPU, RAM, storage etc.) of a resource.
+type ComponentCost struct {
+	Name      string
+	UnitCost  float64
+	Units     float64
+	UsageUnit string
+	Currency  string
+}
+
+// Total returns the hourly cost of all the units of the component.
+func (c ComponentCost) Total() float64 {
+	return c.UnitCost * c.Units
+}
+
+// ResourceReport describes a resource in the before or after state of a change:
+// its name, its attributes by snake_case name and the hourly cost of its billing components.
+// The attribute values are strings, or float64 for the numeric ones (sizes, counts and usage fractions).
+type ResourceReport struct {
+	Name       string
+	Attributes map[string]interface{}
+	Components []ComponentCost
+}
+
+// Total returns the hourly cost of the resource.
+func (r *ResourceReport) Total() (t float64) {
+	if r == nil {
+		return 0
+	}
+	for _, c := range r.Components {
+		t += c.Total()
+	}
+	return
+}
+
+// StateReport describes a resource change for the machine-readable outputs.
+// Before and After are nil when the resource does not exist (before creation or after deletion).
+type StateReport struct {
+	Kind      string
+	Addresses []string
+	Action    string
+	Before    *ResourceReport
+	After     *ResourceReport
+}
+
+// Reporter is implemented by the resource states that describe their change as a StateReport.
+type Reporter interface {
+	Report() StateReport
+}
+
+// ResourceAddresses holds the Terraform addresses of the resources priced by a state (e.g. google_compute_instance.vm[0]).
+// A state usually prices one resource, but grouped resources (e.g. the forwarding rules of a load balancer) have several.
+type ResourceAddresses struct {
+	Addresses []string
+}
+
+// AddAddress records the Terraform address of a resource priced by the state.
+func (a *ResourceAddresses) AddAddress(address string) {
+	a.Addresses = append(a.Addresses, address)
+}
+
+// Addressable is implemented by the resource states recording the Terraform addresses of their resources.
+type Addressable interface {
+	AddAddress(address string)
+}
+
+// componentCosts returns the hourly cost of the components of a resource.
+func componentCosts(components []componentPricing) []ComponentCost {
+	costs := []ComponentCost{}
+	for _, c := range components {
+		costs = append(costs, ComponentCost{Name: c.name, UnitCost: c.unitCost, Units: c.units,
+			UsageUnit: c.pricing.UsageUnit, Currency: c.pricing.CurrencyType})
+	}
+	return costs
+}
@@ -0,0 +1,51 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAddressStateReport(t *testing.T) {
+	unused := PricingInfo{UsageUnit: "hour", HourlyUnitPrice: 0.01, CurrencyType: "USD"}
+	inUse := PricingInfo{UsageUnit: "hour", HourlyUnitPrice: 0.004, CurrencyType: "USD"}
+	address := &Address{Name: "ip", Region: "us-central1", NetworkTier: "PREMIUM", InUse: 0.25,
+		Egress: NetworkEgress{GiB: map[string]float64{}}, UnusedPricing: unused, InUsePricing: inUse}
+	report := &ResourceReport{
+		Name: "ip",
+		Attributes: map[string]interface{}{
+			"id":           "",
+			"region":       "us-central1",
+			"address":      "",
+			"network_tier": "PREMIUM",
+			"in_use":       0.25,
+		},
+		Components: []ComponentCost{
+			{Name: "Static IP (unused)", UnitCost: 0.01, Units: 0.75, UsageUnit: "hour", Currency: "USD"},
+			{Name: "Static IP (in use)", UnitCost: 0.004, Units: 0.25, UsageUnit: "hour", Currency: "USD"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		state    *AddressState
+		expected StateReport
+	}{
+		{"create", &AddressState{After: address, Action: "create", ResourceAddresses: ResourceAddresses{[]string{"google_compute_address.ip"}}},
+			StateReport{Kind: "compute_address", Addresses: []string{"google_compute_address.ip"}, Action: "create", After: report}},
+		{"delete", &AddressState{Before: address, Action: "delete"},
+			StateReport{Kind: "compute_address", Action: "delete", Before: report}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := test.state.Report()
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("Report() = %+v, want %+v", actual, test.expected)
+			}
+			if total := actual.After.Total() - actual.Before.Total(); total-test.state.GetDelta() > epsilon ||
+				test.state.GetDelta()-total > epsilon {
+				t.Errorf("Report() total change = %f, want %f", total, test.state.GetDelta())
+			}
+		})
+	}
+}
@@ -0,0 +1,340 @@
+package resources
+
+import (
+	"fmt"
+
+	billing "github.com/googleinterns/terraform-cost-estimation/billing"
+	"github.com/googleinterns/terraform-cost-estimation/io/js"
+	"github.com/googleinterns/terraform-cost-estimation/io/web"
+	conv "github.com/googleinterns/terraform-cost-estimation/memconverter"
+	cd "github.com/googleinterns/terraform-cost-estimation/resources/classdetail"
+	"github.com/jedib0t/go-pretty/v6/table"
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+const (
+	defaultSQLAvailability = "ZONAL"
+	defaultSQLDiskType     = "PD_SSD"
+	defaultSQLDiskSizeGiB  = 10
+)
+
+// SQLInstance holds information about the Cloud SQL database instance resource type.
+type SQLInstance struct {
+	Name            string
+	ID              string
+	DatabaseVersion string
+	Tier            string
+	Region          string
+	Availability    string
+	MasterInstance  string
+	DiskType        string
+	DiskSizeGiB     int64
+	Cores           int
+	MemoryGiB       float64
+	SharedCore      bool
+	Description     Description
+	CorePricing     PricingInfo
+	MemoryPricing   PricingInfo
+	InstancePricing PricingInfo
+	StoragePricing  PricingInfo
+}
+
+// NewSQLInstance builds a Cloud SQL database instance with the specified fields and fills the other resource details.
+// Empty availability and disk type default to ZONAL and PD_SSD, and a disk size <= 0 defaults to 10 GiB.
+// A non-empty master instance name marks the instance as a read replica.
+func NewSQLInstance(details *cd.ResourceDetail, name, id, databaseVersion, tier, region, availability, masterInstance,
+	diskType string, diskSize int64) (*SQLInstance, error) {
+
+	if region == "" {
+		return nil, fmt.Errorf("region must be specified")
+	}
+
+	instance := &SQLInstance{Name: name, ID: id, DatabaseVersion: databaseVersion, Tier: tier, Region: region,
+		Availability: availability, MasterInstance: masterInstance, DiskType: diskType, DiskSizeGiB: diskSize}
+
+	if instance.Availability == "" {
+		instance.Availability = defaultSQLAvailability
+	}
+	if instance.DiskType == "" {
+		instance.DiskType = defaultSQLDiskType
+	}
+	if instance.DiskSizeGiB <= 0 {
+		instance.DiskSizeGiB = defaultSQLDiskSizeGiB
+	}
+
+	if err := instance.Description.fillForSQLInstance(databaseVersion, instance.Availability); err != nil {
+		return nil, err
+	}
+
+	var err error
+	instance.Cores, instance.MemoryGiB, instance.SharedCore, err = details.SQLTierDetails(tier)
+	if err != nil {
+		return nil, err
+	}
+
+	return instance, nil
+}
+
+// IsReplica returns whether the database instance is a read replica of another instance.
+func (instance *SQLInstance) IsReplica() bool {
+	return instance.MasterInstance != ""
+}
+
+func firstSKU(skus []*billingpb.Sku, region string, d Description) (*billingpb.Sku, error) {
+	filtered, err := filterSKUs(skus, region, d)
+	if err != nil {
+		return nil, err
+	}
+	return filtered[0], nil
+}
+
+// CompletePricingInfo fills the pricing information fields.
+func (instance *SQLInstance) CompletePricingInfo(catalog *billing.Catalog) error {
+	c, err := catalog.CloudSQL()
+	if err != nil {
+		return err
+	}
+	allRates := func(*billingpb.PricingExpression_TierRate) bool { return true }
+
+	if instance.SharedCore {
+		skus, err := c.SharedCoreSKUs(instance.Tier)
+		if err != nil {
+			return err
+		}
+		sku, err := firstSKU(skus, instance.Region, instance.Description)
+		if err != nil {
+			return err
+		}
+		instance.InstancePricing.fillHourlyBase(sku, allRates)
+		instance.InstancePricing.explain(catalog, sku, instance.Region, instance.Description, skus)
+	} else {
+		cores, err := c.CoreSKUs()
+		if err != nil {
+			return err
+		}
+		sku, err := firstSKU(cores, instance.Region, instance.Description)
+		if err != nil {
+			return err
+		}
+		instance.CorePricing.fillHourlyBase(sku, allRates)
+		instance.CorePricing.explain(catalog, sku, instance.Region, instance.Description, cores)
+
+		mem, err := c.RAMSKUs()
+		if err != nil {
+			return err
+		}
+		sku, err = firstSKU(mem, instance.Region, instance.Description)
+		if err != nil {
+			return err
+		}
+		instance.MemoryPricing.fillHourlyBase(sku, allRates)
+		instance.MemoryPricing.explain(catalog, sku, instance.Region, instance.Description, mem)
+		// If the SKU memory unit is not supported, return error.
+		if _, err := conv.Convert("gib", 0, instance.MemoryPricing.UsageUnit); err != nil {
+			return fmt.Errorf("memory unit of SKU is not supported")
+		}
+	}
+
+	storage, err := c.StorageSKUs(instance.DiskType)
+	if err != nil {
+		return err
+	}
+	sku, err := firstSKU(storage, instance.Region, instance.Description)
+	if err != nil {
+		return err
+	}
+	instance.StoragePricing.fillMonthlyBase(sku, allRates, catalog.MonthHours())
+	instance.StoragePricing.explain(catalog, sku, instance.Region, instance.Description, storage)
+	// If the SKU storage unit is not supported, return error.
+	if _, err := conv.Convert("gib", 0, instance.StoragePricing.UsageUnit); err != nil {
+		return fmt.Errorf("storage unit of SKU is not supported")
+	}
+
+	return nil
+}
+
+// components returns the hourly pricing of the billing components of the instance.
+func (instance *SQLInstance) components() []componentPricing {
+	if instance == nil {
+		return nil
+	}
+
+	var c []componentPricing
+	if instance.SharedCore {
+		c = append(c, componentPricing{"Instance", instance.InstancePricing.HourlyUnitPrice, 1, instance.InstancePricing})
+	} else {
+		mem, _ := conv.Convert("gib", instance.MemoryGiB, instance.MemoryPricing.UsageUnit)
+		c = append(c, componentPricing{"CPU", instance.CorePricing.HourlyUnitPrice, float64(instance.Cores), instance.CorePricing},
+			componentPricing{"RAM", instance.MemoryPricing.HourlyUnitPrice, mem, instance.MemoryPricing})
+	}
+
+	storage, _ := conv.Convert("gib", float64(instance.DiskSizeGiB), instance.StoragePricing.UsageUnit)
+	return append(c, componentPricing{"Storage", instance.StoragePricing.HourlyUnitPrice, storage, instance.StoragePricing})
+}
+
+func (instance *SQLInstance) role() string {
+	if instance.IsReplica() {
+		return "read replica of " + instance.MasterInstance
+	}
+	return "primary"
+}
+
+// SQLInstanceState holds the before and after states of a Cloud SQL database instance and the action performed.
+type SQLInstanceState struct {
+	Before *SQLInstance
+	After  *SQLInstance
+	Action string
+	ResourceAddresses
+}
+
+// CompletePricingInfo completes pricing information of both before and after states.
+func (state *SQLInstanceState) CompletePricingInfo(catalog *billing.Catalog) error {
+	if state.Before != nil {
+		if err := state.Before.CompletePricingInfo(catalog); err != nil {
+			return fmt.Errorf("%s(%s): %w", state.Before.Name, state.Before.Tier, err)
+		}
+	}
+
+	if state.After != nil {
+		if err := state.After.CompletePricingInfo(catalog); err != nil {
+			return fmt.Errorf("%s(%s): %w", state.After.Name, state.After.Tier, err)
+		}
+	}
+	return nil
+}
+
+func (state *SQLInstanceState) components() []priceComponent {
+	return mergeComponents(state.Before.components(), state.After.components())
+}
+
+// GetDelta returns the hourly cost change of the database instance.
+func (state *SQLInstanceState) GetDelta() float64 {
+	return componentsDelta(state.components())
+}
+
+func (state *SQLInstanceState) generalChanges() (name string, rows [][2]string) {
+	before, after, _ := syncSQLInstances(state.Before, state.After)
+	id := before.ID
+	if id == "" {
+		id = after.ID
+	}
+
+	name = generalChange(before.Name, after.Name)
+	rows = [][2]string{
+		{"ID", id},
+		{"Action", state.Action},
+		{"Region", generalChange(before.Region, after.Region)},
+		{"Database version", generalChange(before.DatabaseVersion, after.DatabaseVersion)},
+		{"Tier", generalChange(before.Tier, after.Tier)},
+		{"Availability", generalChange(before.Availability, after.Availability)},
+		{"Role", generalChange(before.role(), after.role())},
+		{"Disk", generalChange(fmt.Sprintf("%s %d GiB", before.DiskType, before.DiskSizeGiB),
+			fmt.Sprintf("%s %d GiB", after.DiskType, after.DiskSizeGiB))},
+	}
+	return
+}
+
+// Explain returns the SKU matches of the billing components.
+func (state *SQLInstanceState) Explain() []ComponentMatch {
+	name, _ := state.generalChanges()
+	return componentsExplain(name, state.components())
+}
+
+// Currency returns the currency of the billing components.
+func (state *SQLInstanceState) Currency() (string, error) {
+	return componentsCurrency(state.components())
+}
+
+// report describes the sql instance for the machine-readable outputs.
+func (instance *SQLInstance) report() *ResourceReport {
+	if instance == nil {
+		return nil
+	}
+
+	return &ResourceReport{
+		Name: instance.Name,
+		Attributes: map[string]interface{}{
+			"id":                   instance.ID,
+			"region":               instance.Region,
+			"database_version":     instance.DatabaseVersion,
+			"tier":                 instance.Tier,
+			"availability_type":    instance.Availability,
+			"disk_type":            instance.DiskType,
+			"disk_size_gib":        float64(instance.DiskSizeGiB),
+			"master_instance_name": instance.MasterInstance,
+		},
+		Components: componentCosts(instance.components()),
+	}
+}
+
+// Report describes the change of the sql instance for the machine-readable outputs.
+func (state *SQLInstanceState) Report() StateReport {
+	return StateReport{Kind: "sql_instance", Addresses: state.Addresses, Action: state.Action,
+		Before: state.Before.report(), After: state.After.report()}
+}
+
+// GetWebTables returns html pricing information table with hourly, monthly and yearly pricing.
+func (state *SQLInstanceState) GetWebTables(stateNum int, month Period) (*web.PricingTypeTables, error) {
+	name, rows := state.generalChanges()
+	return componentsWebTables(stateNum, name, rows, state.components(), month)
+}
+
+// ToTable creates a table.Table and fills it with the pricing information from SQLInstanceState.
+func (state *SQLInstanceState) ToTable(p Period) (*table.Table, error) {
+	if _, _, err := syncSQLInstances(state.Before, state.After); err != nil {
+		return nil, err
+	}
+
+	name, rows := state.generalChanges()
+	return componentsTable(append([][2]string{{"Name", name}}, rows...), state.components(), p)
+}
+
+// GetSummaryRow returns the row for SummaryTable to be outputted about the certain state.
+func (state *SQLInstanceState) GetSummaryRow(p Period) (table.Row, error) {
+	_, r, err := syncSQLInstances(state.Before, state.After)
+	if err != nil {
+		return table.Row{}, err
+	}
+	return table.Row{r.Name, r.ID, r.Tier, state.Action, fmt.Sprintf("%.6f", p.Cost(state.GetDelta()))}, nil
+}
+
+// ToStateOut creates SQLInstanceStateOut from state struct to render output in json format.
+func (state *SQLInstanceState) ToStateOut(p Period) (js.JSONOut, error) {
+	before, after, err := syncSQLInstances(state.Before, state.After)
+	if err != nil {
+		return nil, err
+	}
+
+	components := scaleComponents(state.components(), p)
+	out := &js.SQLInstanceStateOut{
+		Name:            js.Change{Before: before.Name, After: after.Name},
+		ID:              js.Change{Before: before.ID, After: after.ID},
+		Region:          js.Change{Before: before.Region, After: after.Region},
+		DatabaseVersion: js.Change{Before: before.DatabaseVersion, After: after.DatabaseVersion},
+		Tier:            js.Change{Before: before.Tier, After: after.Tier},
+		Availability:    js.Change{Before: before.Availability, After: after.Availability},
+		DiskType:        js.Change{Before: before.DiskType, After: after.DiskType},
+		MasterInstance:  js.Change{Before: before.MasterInstance, After: after.MasterInstance},
+		Action:          state.Action,
+		Pricing: js.ComponentsStatePricing{
+			Before: componentsOut(components, state.Before != nil, false),
+			After:  componentsOut(components, state.After != nil, true),
+			Delta:  componentsDelta(components),
+		},
+	}
+	return out, nil
+}
+
+// syncSQLInstances replace nils in state's before and after to be able to use them.
+func syncSQLInstances(before, after *SQLInstance) (*SQLInstance, *SQLInstance, error) {
+	if after == nil && before == nil {
+		return nil, nil, fmt.Errorf("After and Before can't be nil at the same time.")
+	}
+	if after == nil {
+		return before, before, nil
+	}
+	if before == nil {
+		return after, after, nil
+	}
+	return before, after, nil
+}
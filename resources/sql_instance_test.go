@@ -0,0 +1,97 @@
+package resources
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	cd "github.com/googleinterns/terraform-cost-estimation/resources/classdetail"
+)
+
+func TestNewSQLInstance(t *testing.T) {
+	details, err := cd.NewResourceDetail()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	tests := []struct {
+		name         string
+		version      string
+		tier         string
+		region       string
+		availability string
+		diskType     string
+		diskSize     int64
+		instance     *SQLInstance
+		err          error
+	}{
+		{"no_region", "MYSQL_5_7", "db-f1-micro", "", "", "", 0,
+			nil, fmt.Errorf("region must be specified")},
+
+		{"wrong_version", "ORACLE_12", "db-f1-micro", "us-central1", "", "", 0,
+			nil, fmt.Errorf("unsupported database version 'ORACLE_12'")},
+
+		{"wrong_availability", "MYSQL_5_7", "db-f1-micro", "us-central1", "GLOBAL", "", 0,
+			nil, fmt.Errorf("invalid availability type 'GLOBAL'")},
+
+		{"wrong_tier", "MYSQL_5_7", "n1-standard-1", "us-central1", "", "", 0,
+			nil, fmt.Errorf("invalid Cloud SQL tier 'n1-standard-1'")},
+
+		{"defaults", "MYSQL_5_7", "db-f1-micro", "us-central1", "", "", 0,
+			&SQLInstance{DatabaseVersion: "MYSQL_5_7", Tier: "db-f1-micro", Region: "us-central1", Availability: "ZONAL",
+				DiskType: "PD_SSD", DiskSizeGiB: 10, Cores: 1, MemoryGiB: 0.6, SharedCore: true,
+				Description: Description{Contains: []string{"MySQL", "Zonal"}}}, nil},
+
+		{"high_availability", "POSTGRES_12", "db-custom-2-7680", "europe-west1", "REGIONAL", "PD_HDD", 200,
+			&SQLInstance{DatabaseVersion: "POSTGRES_12", Tier: "db-custom-2-7680", Region: "europe-west1", Availability: "REGIONAL",
+				DiskType: "PD_HDD", DiskSizeGiB: 200, Cores: 2, MemoryGiB: 7.5,
+				Description: Description{Contains: []string{"PostgreSQL", "Regional"}}}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			instance, err := NewSQLInstance(details, "", "", test.version, test.tier, test.region, test.availability, "",
+				test.diskType, test.diskSize)
+			// Test fails if the errors are different or the instances are different.
+			if !reflect.DeepEqual(err, test.err) || !reflect.DeepEqual(instance, test.instance) {
+				t.Errorf("NewSQLInstance(%s, %s, %s, %s, %s, %d) = %+v, %+v; want %+v, %+v", test.version, test.tier, test.region,
+					test.availability, test.diskType, test.diskSize, instance, err, test.instance, test.err)
+			}
+		})
+	}
+}
+
+func TestSQLInstanceStateGetDelta(t *testing.T) {
+	core := PricingInfo{UsageUnit: "h", HourlyUnitPrice: 0.0413}
+	mem := PricingInfo{UsageUnit: "gibibyte", HourlyUnitPrice: 0.007}
+	storage := PricingInfo{UsageUnit: "gibibyte", HourlyUnitPrice: 0.17 / hourlyToMonthly}
+
+	i1 := &SQLInstance{Cores: 2, MemoryGiB: 7.5, DiskSizeGiB: 100, CorePricing: core, MemoryPricing: mem, StoragePricing: storage}
+	i2 := &SQLInstance{Cores: 4, MemoryGiB: 15, DiskSizeGiB: 100, CorePricing: core, MemoryPricing: mem, StoragePricing: storage}
+	shared := &SQLInstance{Cores: 1, MemoryGiB: 0.6, SharedCore: true, DiskSizeGiB: 10,
+		InstancePricing: PricingInfo{UsageUnit: "h", HourlyUnitPrice: 0.0105}, StoragePricing: storage}
+
+	total1 := 2*0.0413 + 7.5*0.007 + 100*0.17/hourlyToMonthly
+	total2 := 4*0.0413 + 15*0.007 + 100*0.17/hourlyToMonthly
+	totalShared := 0.0105 + 10*0.17/hourlyToMonthly
+
+	tests := []struct {
+		name  string
+		state SQLInstanceState
+		delta float64
+	}{
+		{"create", SQLInstanceState{After: i1}, total1},
+		{"destroy", SQLInstanceState{Before: i1}, -total1},
+		{"update_tier", SQLInstanceState{Before: i1, After: i2}, total2 - total1},
+		{"update_to_shared_core", SQLInstanceState{Before: i1, After: shared}, totalShared - total1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if d := test.state.GetDelta(); math.Abs(d-test.delta) > epsilon {
+				t.Errorf("%+v.GetDelta() = %f; want %f", test.state, d, test.delta)
+			}
+		})
+	}
+}
@@ -0,0 +1,462 @@
+package resources
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	billing "github.com/googleinterns/terraform-cost-estimation/billing"
+	"github.com/googleinterns/terraform-cost-estimation/io/js"
+	"github.com/googleinterns/terraform-cost-estimation/io/web"
+	conv "github.com/googleinterns/terraform-cost-estimation/memconverter"
+	"github.com/googleinterns/terraform-cost-estimation/usage"
+	"github.com/jedib0t/go-pretty/v6/table"
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+)
+
+var (
+	multiRegions = map[string]bool{"US": true, "EU": true, "ASIA": true}
+	dualRegions  = map[string]bool{"ASIA1": true, "EUR4": true, "NAM4": true}
+
+	// egressDestinations maps the egress destination classes used in usage assumptions to the SKU descriptions.
+	egressDestinations = map[string]string{
+		"worldwide": "Download Worldwide Destinations (excluding Asia & Australia)",
+		"asia":      "Download APAC",
+		"australia": "Download Australia",
+		"china":     "Download China",
+	}
+)
+
+// LifecycleRule is a lifecycle rule of a bucket changing the storage class of (SetStorageClass)
+// or deleting (Delete) objects older than AgeDays.
+type LifecycleRule struct {
+	Action       string
+	StorageClass string
+	AgeDays      int
+}
+
+// StorageBucket holds information about the Cloud Storage bucket resource type.
+// Stored data, operations and egress are monthly amounts taken from usage assumptions.
+type StorageBucket struct {
+	Name           string
+	ID             string
+	Location       string
+	LocationType   string
+	StorageClass   string
+	Lifecycle      []LifecycleRule
+	StorageGiB     map[string]float64
+	ClassAOps      float64
+	ClassBOps      float64
+	EgressGiB      map[string]float64
+	StoragePricing map[string]PricingInfo
+	ClassAPricing  PricingInfo
+	ClassBPricing  PricingInfo
+	EgressPricing  map[string]PricingInfo
+}
+
+func bucketStorageClass(storageClass string) (string, error) {
+	switch storageClass {
+	case "", "STANDARD", "MULTI_REGIONAL", "REGIONAL", "DURABLE_REDUCED_AVAILABILITY":
+		return "STANDARD", nil
+	case "NEARLINE", "COLDLINE", "ARCHIVE":
+		return storageClass, nil
+	default:
+		return "", fmt.Errorf("invalid storage class '" + storageClass + "'")
+	}
+}
+
+func bucketLocationType(location string) (string, error) {
+	switch {
+	case multiRegions[location]:
+		return "MULTI_REGION", nil
+	case dualRegions[location]:
+		return "DUAL_REGION", nil
+	case strings.Contains(location, "-"):
+		return "REGION", nil
+	default:
+		return "", fmt.Errorf("invalid bucket location '" + location + "'")
+	}
+}
+
+// NewStorageBucket builds a storage bucket with the specified fields and fills the other resource details.
+// Usage values: storage_gib (optionally split by class as storage_gib.<CLASS>), class_a_operations,
+// class_b_operations, egress_gib.<destination> (worldwide, asia, australia, china) and retention_days.
+func NewStorageBucket(name, id, location, storageClass string, lifecycle []LifecycleRule, u usage.Values) (*StorageBucket, error) {
+	bucket := &StorageBucket{Name: name, ID: id, Location: strings.ToUpper(location), Lifecycle: lifecycle,
+		ClassAOps: u.Value("class_a_operations"), ClassBOps: u.Value("class_b_operations"), EgressGiB: u.Group("egress_gib")}
+
+	var err error
+	if bucket.StorageClass, err = bucketStorageClass(storageClass); err != nil {
+		return nil, err
+	}
+	if bucket.LocationType, err = bucketLocationType(bucket.Location); err != nil {
+		return nil, err
+	}
+
+	for _, r := range lifecycle {
+		if r.Action != "SetStorageClass" {
+			continue
+		}
+		if _, err := bucketStorageClass(r.StorageClass); err != nil {
+			return nil, err
+		}
+	}
+
+	for d := range bucket.EgressGiB {
+		if _, ok := egressDestinations[d]; !ok {
+			return nil, fmt.Errorf("invalid egress destination '" + d + "'")
+		}
+	}
+
+	if byClass := u.Group("storage_gib"); len(byClass) > 0 {
+		bucket.StorageGiB = map[string]float64{}
+		for c, gib := range byClass {
+			class, err := bucketStorageClass(c)
+			if err != nil {
+				return nil, err
+			}
+			bucket.StorageGiB[class] += gib
+		}
+	} else {
+		bucket.StorageGiB = bucket.storageByClass(u.Value("storage_gib"), u.Value("retention_days"))
+	}
+
+	return bucket, nil
+}
+
+// storageByClass splits the stored data between the storage classes set by the lifecycle rules.
+// Objects are assumed to be written at a constant rate and kept for the retention period, which is
+// the age of the earliest Delete rule or the retentionDays value. Without a retention period, all data
+// is considered to be in the default storage class of the bucket.
+func (bucket *StorageBucket) storageByClass(gib, retentionDays float64) map[string]float64 {
+	var transitions []LifecycleRule
+	for _, r := range bucket.Lifecycle {
+		switch {
+		case r.Action == "Delete" && r.AgeDays > 0 && (retentionDays <= 0 || float64(r.AgeDays) < retentionDays):
+			retentionDays = float64(r.AgeDays)
+		case r.Action == "SetStorageClass" && r.AgeDays > 0:
+			transitions = append(transitions, r)
+		}
+	}
+
+	if retentionDays <= 0 || len(transitions) == 0 {
+		return map[string]float64{bucket.StorageClass: gib}
+	}
+
+	sort.SliceStable(transitions, func(i, j int) bool { return transitions[i].AgeDays < transitions[j].AgeDays })
+
+	byClass := map[string]float64{}
+	class, start := bucket.StorageClass, 0.0
+	for _, t := range transitions {
+		age := float64(t.AgeDays)
+		if age >= retentionDays {
+			break
+		}
+		byClass[class] += gib * (age - start) / retentionDays
+		// Rules are validated when building the bucket.
+		class, _ = bucketStorageClass(t.StorageClass)
+		start = age
+	}
+	byClass[class] += gib * (retentionDays - start) / retentionDays
+	return byClass
+}
+
+func (bucket *StorageBucket) storageClasses() []string {
+	var classes []string
+	for c := range bucket.StorageGiB {
+		classes = append(classes, c)
+	}
+	sort.Strings(classes)
+	return classes
+}
+
+func (bucket *StorageBucket) egressDestinations() []string {
+	var dest []string
+	for d := range bucket.EgressGiB {
+		dest = append(dest, d)
+	}
+	sort.Strings(dest)
+	return dest
+}
+
+// monthlyPricing returns the pricing of the given monthly amount of usage of the first SKU of the region
+// fitting the description. The tiers of the SKU are graduated: each rate applies only to the usage within its tier.
+func monthlyPricing(catalog *billing.Catalog, skus []*billingpb.Sku, region string, d Description, amount float64) (PricingInfo, error) {
+	p := PricingInfo{}
+	sku, err := firstSKU(skus, region, d)
+	if err != nil {
+		return p, err
+	}
+
+	p.fillMonthlyGraduated(sku, amount, catalog.MonthHours())
+	p.explain(catalog, sku, region, d, skus)
+	return p, nil
+}
+
+// CompletePricingInfo fills the pricing information fields.
+func (bucket *StorageBucket) CompletePricingInfo(catalog *billing.Catalog) error {
+	c, err := catalog.CloudStorage()
+	if err != nil {
+		return err
+	}
+	region := strings.ToLower(bucket.Location)
+
+	bucket.StoragePricing = map[string]PricingInfo{}
+	for _, class := range bucket.storageClasses() {
+		skus, err := c.StorageSKUs(class, bucket.LocationType)
+		if err != nil {
+			return err
+		}
+
+		d := Description{}
+		d.fillForBucketStorage(bucket.LocationType)
+		p, err := monthlyPricing(catalog, skus, region, d, bucket.StorageGiB[class])
+		if err != nil {
+			return err
+		}
+		// If the SKU storage unit is not supported, return error.
+		if _, err := conv.Convert("gib", 0, p.UsageUnit); err != nil {
+			return fmt.Errorf("storage unit of SKU is not supported")
+		}
+		bucket.StoragePricing[class] = p
+	}
+
+	for _, op := range []struct {
+		class   string
+		amount  float64
+		pricing *PricingInfo
+	}{{"A", bucket.ClassAOps, &bucket.ClassAPricing}, {"B", bucket.ClassBOps, &bucket.ClassBPricing}} {
+		if op.amount <= 0 {
+			continue
+		}
+
+		skus, err := c.OperationSKUs(op.class)
+		if err != nil {
+			return err
+		}
+
+		d := Description{}
+		d.fillForBucketOperations(bucket.StorageClass, bucket.LocationType)
+		if *op.pricing, err = monthlyPricing(catalog, skus, region, d, op.amount); err != nil {
+			return err
+		}
+	}
+
+	bucket.EgressPricing = map[string]PricingInfo{}
+	for _, dest := range bucket.egressDestinations() {
+		skus, err := c.EgressSKUs()
+		if err != nil {
+			return err
+		}
+
+		d := Description{Contains: []string{egressDestinations[dest]}}
+		p, err := monthlyPricing(catalog, skus, region, d, bucket.EgressGiB[dest])
+		if err != nil {
+			return err
+		}
+		if _, err := conv.Convert("gib", 0, p.UsageUnit); err != nil {
+			return fmt.Errorf("egress unit of SKU is not supported")
+		}
+		bucket.EgressPricing[dest] = p
+	}
+
+	return nil
+}
+
+// components returns the hourly pricing of the billing components of the bucket.
+func (bucket *StorageBucket) components() []componentPricing {
+	if bucket == nil {
+		return nil
+	}
+
+	var c []componentPricing
+	for _, class := range bucket.storageClasses() {
+		p := bucket.StoragePricing[class]
+		units, _ := conv.Convert("gib", bucket.StorageGiB[class], p.UsageUnit)
+		c = append(c, componentPricing{"Storage (" + class + ")", p.HourlyUnitPrice, units, p})
+	}
+
+	if bucket.ClassAOps > 0 {
+		c = append(c, componentPricing{"Class A operations", bucket.ClassAPricing.HourlyUnitPrice, bucket.ClassAOps, bucket.ClassAPricing})
+	}
+	if bucket.ClassBOps > 0 {
+		c = append(c, componentPricing{"Class B operations", bucket.ClassBPricing.HourlyUnitPrice, bucket.ClassBOps, bucket.ClassBPricing})
+	}
+
+	for _, dest := range bucket.egressDestinations() {
+		p := bucket.EgressPricing[dest]
+		units, _ := conv.Convert("gib", bucket.EgressGiB[dest], p.UsageUnit)
+		c = append(c, componentPricing{"Egress (" + dest + ")", p.HourlyUnitPrice, units, p})
+	}
+	return c
+}
+
+func (bucket *StorageBucket) lifecycleString() string {
+	var rules []string
+	for _, r := range bucket.Lifecycle {
+		switch r.Action {
+		case "SetStorageClass":
+			rules = append(rules, fmt.Sprintf("%s after %dd", r.StorageClass, r.AgeDays))
+		case "Delete":
+			rules = append(rules, fmt.Sprintf("delete after %dd", r.AgeDays))
+		default:
+		}
+	}
+
+	if len(rules) == 0 {
+		return "none"
+	}
+	return strings.Join(rules, ", ")
+}
+
+// StorageBucketState holds the before and after states of a storage bucket and the action performed.
+type StorageBucketState struct {
+	Before *StorageBucket
+	After  *StorageBucket
+	Action string
+	ResourceAddresses
+}
+
+// CompletePricingInfo completes pricing information of both before and after states.
+func (state *StorageBucketState) CompletePricingInfo(catalog *billing.Catalog) error {
+	if state.Before != nil {
+		if err := state.Before.CompletePricingInfo(catalog); err != nil {
+			return fmt.Errorf("%s(%s): %w", state.Before.Name, state.Before.StorageClass, err)
+		}
+	}
+
+	if state.After != nil {
+		if err := state.After.CompletePricingInfo(catalog); err != nil {
+			return fmt.Errorf("%s(%s): %w", state.After.Name, state.After.StorageClass, err)
+		}
+	}
+	return nil
+}
+
+func (state *StorageBucketState) components() []priceComponent {
+	return mergeComponents(state.Before.components(), state.After.components())
+}
+
+// GetDelta returns the hourly cost change of the bucket.
+func (state *StorageBucketState) GetDelta() float64 {
+	return componentsDelta(state.components())
+}
+
+func (state *StorageBucketState) generalChanges() (name string, rows [][2]string) {
+	before, after, _ := syncBuckets(state.Before, state.After)
+	id := before.ID
+	if id == "" {
+		id = after.ID
+	}
+
+	name = generalChange(before.Name, after.Name)
+	rows = [][2]string{
+		{"ID", id},
+		{"Action", state.Action},
+		{"Location", generalChange(before.Location, after.Location)},
+		{"Location type", generalChange(before.LocationType, after.LocationType)},
+		{"Storage class", generalChange(before.StorageClass, after.StorageClass)},
+		{"Lifecycle", generalChange(before.lifecycleString(), after.lifecycleString())},
+	}
+	return
+}
+
+// Explain returns the SKU matches of the billing components.
+func (state *StorageBucketState) Explain() []ComponentMatch {
+	name, _ := state.generalChanges()
+	return componentsExplain(name, state.components())
+}
+
+// Currency returns the currency of the billing components.
+func (state *StorageBucketState) Currency() (string, error) {
+	return componentsCurrency(state.components())
+}
+
+// report describes the storage bucket for the machine-readable outputs.
+func (bucket *StorageBucket) report() *ResourceReport {
+	if bucket == nil {
+		return nil
+	}
+
+	return &ResourceReport{
+		Name: bucket.Name,
+		Attributes: map[string]interface{}{
+			"id":            bucket.ID,
+			"location":      bucket.Location,
+			"location_type": bucket.LocationType,
+			"storage_class": bucket.StorageClass,
+			"lifecycle":     bucket.lifecycleString(),
+		},
+		Components: componentCosts(bucket.components()),
+	}
+}
+
+// Report describes the change of the storage bucket for the machine-readable outputs.
+func (state *StorageBucketState) Report() StateReport {
+	return StateReport{Kind: "storage_bucket", Addresses: state.Addresses, Action: state.Action,
+		Before: state.Before.report(), After: state.After.report()}
+}
+
+// GetWebTables returns html pricing information table with hourly, monthly and yearly pricing.
+func (state *StorageBucketState) GetWebTables(stateNum int, month Period) (*web.PricingTypeTables, error) {
+	name, rows := state.generalChanges()
+	return componentsWebTables(stateNum, name, rows, state.components(), month)
+}
+
+// ToTable creates a table.Table and fills it with the pricing information from StorageBucketState.
+func (state *StorageBucketState) ToTable(p Period) (*table.Table, error) {
+	if _, _, err := syncBuckets(state.Before, state.After); err != nil {
+		return nil, err
+	}
+
+	name, rows := state.generalChanges()
+	return componentsTable(append([][2]string{{"Name", name}}, rows...), state.components(), p)
+}
+
+// GetSummaryRow returns the row for SummaryTable to be outputted about the certain state.
+func (state *StorageBucketState) GetSummaryRow(p Period) (table.Row, error) {
+	_, r, err := syncBuckets(state.Before, state.After)
+	if err != nil {
+		return table.Row{}, err
+	}
+	return table.Row{r.Name, r.ID, r.StorageClass, state.Action, fmt.Sprintf("%.6f", p.Cost(state.GetDelta()))}, nil
+}
+
+// ToStateOut creates StorageBucketStateOut from state struct to render output in json format.
+func (state *StorageBucketState) ToStateOut(p Period) (js.JSONOut, error) {
+	before, after, err := syncBuckets(state.Before, state.After)
+	if err != nil {
+		return nil, err
+	}
+
+	components := scaleComponents(state.components(), p)
+	out := &js.StorageBucketStateOut{
+		Name:         js.Change{Before: before.Name, After: after.Name},
+		ID:           js.Change{Before: before.ID, After: after.ID},
+		Location:     js.Change{Before: before.Location, After: after.Location},
+		LocationType: js.Change{Before: before.LocationType, After: after.LocationType},
+		StorageClass: js.Change{Before: before.StorageClass, After: after.StorageClass},
+		Lifecycle:    js.Change{Before: before.lifecycleString(), After: after.lifecycleString()},
+		Action:       state.Action,
+		Pricing: js.ComponentsStatePricing{
+			Before: componentsOut(components, state.Before != nil, false),
+			After:  componentsOut(components, state.After != nil, true),
+			Delta:  componentsDelta(components),
+		},
+	}
+	return out, nil
+}
+
+// syncBuckets replace nils in state's before and after to be able to use them.
+func syncBuckets(before, after *StorageBucket) (*StorageBucket, *StorageBucket, error) {
+	if after == nil && before == nil {
+		return nil, nil, fmt.Errorf("After and Before can't be nil at the same time.")
+	}
+	if after == nil {
+		return before, before, nil
+	}
+	if before == nil {
+		return after, after, nil
+	}
+	return before, after, nil
+}
@@ -0,0 +1,136 @@
+package resources
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	billing "github.com/googleinterns/terraform-cost-estimation/billing"
+	"github.com/googleinterns/terraform-cost-estimation/usage"
+	billingpb "google.golang.org/genproto/googleapis/cloud/billing/v1"
+	"google.golang.org/genproto/googleapis/type/money"
+)
+
+func TestNewStorageBucket(t *testing.T) {
+	tests := []struct {
+		name         string
+		location     string
+		storageClass string
+		lifecycle    []LifecycleRule
+		usage        usage.Values
+		bucket       *StorageBucket
+		err          error
+	}{
+		{"wrong_location", "central", "", nil, nil,
+			nil, fmt.Errorf("invalid bucket location 'CENTRAL'")},
+
+		{"wrong_class", "US", "COLD", nil, nil,
+			nil, fmt.Errorf("invalid storage class 'COLD'")},
+
+		{"wrong_lifecycle_class", "US", "", []LifecycleRule{{"SetStorageClass", "ICE", 30}}, nil,
+			nil, fmt.Errorf("invalid storage class 'ICE'")},
+
+		{"wrong_egress", "US", "", nil, usage.Values{"egress_gib.mars": 1},
+			nil, fmt.Errorf("invalid egress destination 'mars'")},
+
+		{"multi_region", "us", "MULTI_REGIONAL", nil, usage.Values{"storage_gib": 100, "class_a_operations": 1000},
+			&StorageBucket{Location: "US", LocationType: "MULTI_REGION", StorageClass: "STANDARD",
+				StorageGiB: map[string]float64{"STANDARD": 100}, ClassAOps: 1000, EgressGiB: map[string]float64{}}, nil},
+
+		{"region_with_egress", "us-central1", "NEARLINE", nil, usage.Values{"storage_gib": 10, "egress_gib.china": 5},
+			&StorageBucket{Location: "US-CENTRAL1", LocationType: "REGION", StorageClass: "NEARLINE",
+				StorageGiB: map[string]float64{"NEARLINE": 10}, EgressGiB: map[string]float64{"china": 5}}, nil},
+
+		{"explicit_classes", "NAM4", "", []LifecycleRule{{"SetStorageClass", "COLDLINE", 30}},
+			usage.Values{"storage_gib": 10, "storage_gib.STANDARD": 4, "storage_gib.COLDLINE": 6},
+			&StorageBucket{Location: "NAM4", LocationType: "DUAL_REGION", StorageClass: "STANDARD",
+				Lifecycle:  []LifecycleRule{{"SetStorageClass", "COLDLINE", 30}},
+				StorageGiB: map[string]float64{"STANDARD": 4, "COLDLINE": 6}, EgressGiB: map[string]float64{}}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bucket, err := NewStorageBucket("", "", test.location, test.storageClass, test.lifecycle, test.usage)
+			// Test fails if the errors are different or the buckets are different.
+			if !reflect.DeepEqual(err, test.err) || !reflect.DeepEqual(bucket, test.bucket) {
+				t.Errorf("NewStorageBucket(%s, %s, %+v, %+v) = %+v, %+v; want %+v, %+v", test.location, test.storageClass,
+					test.lifecycle, test.usage, bucket, err, test.bucket, test.err)
+			}
+		})
+	}
+}
+
+func TestStorageByClass(t *testing.T) {
+	toNearline := LifecycleRule{Action: "SetStorageClass", StorageClass: "NEARLINE", AgeDays: 30}
+	toColdline := LifecycleRule{Action: "SetStorageClass", StorageClass: "COLDLINE", AgeDays: 90}
+	toArchive := LifecycleRule{Action: "SetStorageClass", StorageClass: "ARCHIVE", AgeDays: 400}
+	deleteRule := LifecycleRule{Action: "Delete", AgeDays: 360}
+
+	tests := []struct {
+		name      string
+		lifecycle []LifecycleRule
+		retention float64
+		byClass   map[string]float64
+	}{
+		{"no_rules", nil, 0, map[string]float64{"STANDARD": 360}},
+		{"no_retention", []LifecycleRule{toNearline}, 0, map[string]float64{"STANDARD": 360}},
+		{"delete_rule", []LifecycleRule{toNearline, deleteRule}, 0, map[string]float64{"STANDARD": 30, "NEARLINE": 330}},
+		{"retention_days", []LifecycleRule{toColdline, toNearline}, 180,
+			map[string]float64{"STANDARD": 60, "NEARLINE": 120, "COLDLINE": 180}},
+		{"transition_after_delete", []LifecycleRule{toNearline, toArchive, deleteRule}, 720,
+			map[string]float64{"STANDARD": 30, "NEARLINE": 330}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bucket := &StorageBucket{StorageClass: "STANDARD", Lifecycle: test.lifecycle}
+			byClass := bucket.storageByClass(360, test.retention)
+
+			ok := len(byClass) == len(test.byClass)
+			for c, gib := range test.byClass {
+				ok = ok && math.Abs(byClass[c]-gib) < epsilon
+			}
+			if !ok {
+				t.Errorf("storageByClass(360, %f) with lifecycle %+v = %+v; want %+v", test.retention, test.lifecycle,
+					byClass, test.byClass)
+			}
+		})
+	}
+}
+
+func TestMonthlyPricing(t *testing.T) {
+	// Standard storage: 0.02 per GiB month up to 1 TiB and 0.01 above.
+	skus := []*billingpb.Sku{{
+		Description:    "Standard Storage US Regional",
+		ServiceRegions: []string{"us-central1"},
+		PricingInfo: []*billingpb.PricingInfo{{PricingExpression: &billingpb.PricingExpression{
+			UsageUnitDescription: "gibibyte month",
+			TieredRates: []*billingpb.PricingExpression_TierRate{
+				{StartUsageAmount: 0, UnitPrice: &money.Money{CurrencyCode: "USD", Nanos: 20000000}},
+				{StartUsageAmount: 1024, UnitPrice: &money.Money{CurrencyCode: "USD", Nanos: 10000000}},
+			},
+		}}},
+	}}
+
+	tests := []struct {
+		name    string
+		amount  float64
+		monthly float64
+	}{
+		{"first_tier", 100, 100 * 0.02},
+		{"two_tiers", 2048, 1024*0.02 + 1024*0.01},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := monthlyPricing(billing.NewCatalogFromSKUs(nil), skus, "us-central1", Description{}, test.amount)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if monthly := p.HourlyUnitPrice * test.amount * hourlyToMonthly; math.Abs(monthly-test.monthly) > epsilon {
+				t.Errorf("monthlyPricing(%f) = %f USD/month, want %f", test.amount, monthly, test.monthly)
+			}
+		})
+	}
+}
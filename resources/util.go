@@ -75,29 +75,29 @@ func initRow(h, before, after string, end bool) (row table.Row) {
 }
 
 // getMemCoreInfo returns two arrays with resource's core and memory information and the totalCost.
-func getMemCoreInfo(r *ComputeInstance) (core, mem []string, t float64, err error) {
+func getMemCoreInfo(r *ComputeInstance, p Period) (core, mem []string, t float64, err error) {
 	if r == nil {
 		return []string{"-", "0", "0"}, []string{"-", "0", "0"}, 0, nil
 	}
 
-	core = append(core, fmt.Sprintf("%.6f", float64(r.Cores.UnitPricing.HourlyUnitPrice)))
+	core = append(core, fmt.Sprintf("%.6f", p.Cost(r.Cores.UnitPricing.HourlyUnitPrice)))
 	core = append(core, fmt.Sprintf("%d", r.Cores.Number))
-	core = append(core, fmt.Sprintf("%.6f", float64(r.Cores.getTotalPrice())))
+	core = append(core, fmt.Sprintf("%.6f", p.Cost(r.Cores.getTotalPrice())))
 
-	mem = append(mem, fmt.Sprintf("%.6f", float64(r.Memory.UnitPricing.HourlyUnitPrice)))
+	mem = append(mem, fmt.Sprintf("%.6f", p.Cost(r.Memory.UnitPricing.HourlyUnitPrice)))
 	unitType := strings.Split(r.Memory.UnitPricing.UsageUnit, " ")[0]
 	memNum, err := conv.Convert("gib", r.Memory.AmountGiB, unitType)
 	if err != nil {
 		return nil, nil, 0, err
 	}
 	mem = append(mem, fmt.Sprintf("%.2f", memNum))
-	p := r.Memory.getTotalPrice()
-	mem = append(mem, fmt.Sprintf("%.6f", p))
-	return core, mem, r.Cores.getTotalPrice() + p, nil
+	memTotal := p.Cost(r.Memory.getTotalPrice())
+	mem = append(mem, fmt.Sprintf("%.6f", memTotal))
+	return core, mem, p.Cost(r.Cores.getTotalPrice()) + memTotal, nil
 }
 
-func completeInstanceOut(r *ComputeInstance) (*js.InstancePricing, error) {
-	core, mem, t, err := getMemCoreInfo(r)
+func completeInstanceOut(r *ComputeInstance, p Period) (*js.InstancePricing, error) {
+	core, mem, t, err := getMemCoreInfo(r, p)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,44 @@
+# See https://www.terraform.io/docs/providers/google/r/compute_global_forwarding_rule.html
+# and https://www.terraform.io/docs/providers/google/r/compute_forwarding_rule.html.
+
+resource "google_compute_url_map" "web" {
+  name            = "test-web"
+  default_service = "projects/test/global/backendServices/web"
+}
+
+resource "google_compute_target_http_proxy" "http" {
+  name    = "test-http"
+  url_map = google_compute_url_map.web.id
+}
+
+resource "google_compute_target_https_proxy" "https" {
+  name             = "test-https"
+  url_map          = google_compute_url_map.web.id
+  ssl_certificates = ["projects/test/global/sslCertificates/web"]
+}
+
+resource "google_compute_global_forwarding_rule" "http" {
+  name       = "test-http-rule"
+  target     = google_compute_target_http_proxy.http.id
+  port_range = "80"
+}
+
+resource "google_compute_global_forwarding_rule" "https" {
+  name       = "test-https-rule"
+  target     = google_compute_target_https_proxy.https.id
+  port_range = "443"
+}
+
+resource "google_compute_region_backend_service" "internal" {
+  name                  = "test-internal"
+  region                = "us-central1"
+  load_balancing_scheme = "INTERNAL"
+}
+
+resource "google_compute_forwarding_rule" "internal" {
+  name                  = "test-internal-rule"
+  region                = "us-central1"
+  load_balancing_scheme = "INTERNAL"
+  backend_service       = google_compute_region_backend_service.internal.id
+  all_ports             = true
+}
@@ -0,0 +1,31 @@
+# See https://www.terraform.io/docs/providers/google/r/compute_address.html
+# and https://www.terraform.io/docs/providers/google/r/compute_router_nat.html.
+
+resource "google_compute_address" "external" {
+  name   = "test-external"
+  region = "us-central1"
+}
+
+resource "google_compute_address" "internal" {
+  name         = "test-internal"
+  region       = "us-central1"
+  address_type = "INTERNAL"
+}
+
+resource "google_compute_global_address" "lb" {
+  name = "test-lb"
+}
+
+resource "google_compute_router" "router" {
+  name    = "test-router"
+  region  = "us-central1"
+  network = "default"
+}
+
+resource "google_compute_router_nat" "nat" {
+  name                               = "test-nat"
+  router                             = google_compute_router.router.name
+  region                             = "us-central1"
+  nat_ip_allocate_option             = "AUTO_ONLY"
+  source_subnetwork_ip_ranges_to_nat = "ALL_SUBNETWORKS_ALL_IP_RANGES"
+}
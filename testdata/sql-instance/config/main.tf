@@ -0,0 +1,27 @@
+# See https://www.terraform.io/docs/providers/google/r/sql_database_instance.html.
+
+resource "google_sql_database_instance" "primary" {
+  name             = "test-primary"
+  database_version = "POSTGRES_12"
+  region           = "us-central1"
+
+  settings {
+    tier              = "db-custom-2-7680"
+    availability_type = "REGIONAL"
+    disk_type         = "PD_SSD"
+    disk_size         = 100
+  }
+}
+
+resource "google_sql_database_instance" "replica" {
+  name                 = "test-replica"
+  database_version     = "POSTGRES_12"
+  region               = "us-central1"
+  master_instance_name = google_sql_database_instance.primary.name
+
+  settings {
+    tier      = "db-custom-2-7680"
+    disk_type = "PD_SSD"
+    disk_size = 100
+  }
+}
@@ -0,0 +1,3 @@
+// Package usage holds the usage assumptions of resources (stored data, number of operations,
+// network egress etc.) which cannot be read from Terraform plan files and are supplied by the user.
+package usage
@@ -0,0 +1,80 @@
+package usage
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// Values holds the usage assumptions of a resource as value names mapped to their monthly amount.
+// Value names can be grouped by a prefix delimited by '.' (e.g. "egress_gib.americas").
+type Values map[string]float64
+
+// Assumptions holds the usage assumptions for the resources of a plan file.
+// Defaults are keyed by resource type and apply to all resources of that type.
+// Resources are keyed by resource address and override the defaults of their type.
+type Assumptions struct {
+	Defaults  map[string]Values `json:"defaults"`
+	Resources map[string]Values `json:"resources"`
+}
+
+// Read decodes the usage assumptions from a JSON reader.
+func Read(r io.Reader) (*Assumptions, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Assumptions{}
+	if err = json.Unmarshal(data, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ReadFile decodes the usage assumptions from the JSON file with the given path.
+func ReadFile(path string) (*Assumptions, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// Get returns the usage values of the resource with the specified address and type.
+// The values for the address override the default values of the resource type.
+// A nil Assumptions returns empty values.
+func (a *Assumptions) Get(address, resourceType string) Values {
+	v := Values{}
+	if a == nil {
+		return v
+	}
+
+	for k, x := range a.Defaults[resourceType] {
+		v[k] = x
+	}
+	for k, x := range a.Resources[address] {
+		v[k] = x
+	}
+	return v
+}
+
+// Value returns the value with the given name, or 0 if it was not specified.
+func (v Values) Value(name string) float64 {
+	return v[name]
+}
+
+// Group returns the values whose names start with the given prefix followed by '.', keyed by the rest of their name.
+func (v Values) Group(prefix string) map[string]float64 {
+	g := map[string]float64{}
+	for k, x := range v {
+		if strings.HasPrefix(k, prefix+".") {
+			g[k[len(prefix)+1:]] = x
+		}
+	}
+	return g
+}
@@ -0,0 +1,53 @@
+package usage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	a, err := Read(strings.NewReader(`{
+		"defaults": {
+			"google_storage_bucket": {"storage_gib": 100, "class_a_operations": 1000}
+		},
+		"resources": {
+			"google_storage_bucket.logs": {"storage_gib": 2048, "egress_gib.china": 10}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		assumptions  *Assumptions
+		address      string
+		resourceType string
+		values       Values
+	}{
+		{"nil_assumptions", nil, "google_storage_bucket.logs", "google_storage_bucket", Values{}},
+		{"unknown_type", a, "google_compute_address.ip", "google_compute_address", Values{}},
+		{"defaults", a, "google_storage_bucket.assets", "google_storage_bucket",
+			Values{"storage_gib": 100, "class_a_operations": 1000}},
+		{"override", a, "google_storage_bucket.logs", "google_storage_bucket",
+			Values{"storage_gib": 2048, "class_a_operations": 1000, "egress_gib.china": 10}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if v := test.assumptions.Get(test.address, test.resourceType); !reflect.DeepEqual(v, test.values) {
+				t.Errorf("Get(%s, %s) = %+v; want %+v", test.address, test.resourceType, v, test.values)
+			}
+		})
+	}
+}
+
+func TestGroup(t *testing.T) {
+	v := Values{"egress_gib.china": 10, "egress_gib.australia": 2.5, "egress_gibs": 1, "storage_gib": 5}
+	expected := map[string]float64{"china": 10, "australia": 2.5}
+
+	if g := v.Group("egress_gib"); !reflect.DeepEqual(g, expected) {
+		t.Errorf("Group(egress_gib) = %+v; want %+v", g, expected)
+	}
+}